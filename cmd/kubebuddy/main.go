@@ -47,6 +47,9 @@ func newServerCmd() *cobra.Command {
 		seedData       bool
 		enableWebUI    bool
 		webuiPort      string
+		reservation    string
+		snapshotEvery  string
+		snapshotKeep   string
 	)
 
 	cmd := &cobra.Command{
@@ -59,6 +62,9 @@ Environment Variables:
   KUBEBUDDY_PORT                Server port (overridden by --port)
   KUBEBUDDY_CREATE_ADMIN_KEY    Set to "true" to create admin key (overridden by --create-admin-key)
   KUBEBUDDY_SEED                Set to "true" to seed database (overridden by --seed)
+  KUBEBUDDY_RESERVATION         Default reservation basis: min, max or p0-p100 (overridden by --reservation)
+  KUBEBUDDY_SNAPSHOT_INTERVAL   Capacity snapshot interval, 0 disables (overridden by --snapshot-interval)
+  KUBEBUDDY_SNAPSHOT_RETENTION  Capacity snapshot retention, 0 keeps all (overridden by --snapshot-retention)
   KUBEBUDDY_ADMIN_API_KEY       Required when using --create-admin-key flag`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Load configuration from environment variables if not set via flags
@@ -82,6 +88,36 @@ Environment Variables:
 					seedData = true
 				}
 			}
+			if !cmd.Flags().Changed("reservation") {
+				if envReservation := os.Getenv("KUBEBUDDY_RESERVATION"); envReservation != "" {
+					reservation = envReservation
+				}
+			}
+
+			if !cmd.Flags().Changed("snapshot-interval") {
+				if envInterval := os.Getenv("KUBEBUDDY_SNAPSHOT_INTERVAL"); envInterval != "" {
+					snapshotEvery = envInterval
+				}
+			}
+			if !cmd.Flags().Changed("snapshot-retention") {
+				if envRetention := os.Getenv("KUBEBUDDY_SNAPSHOT_RETENTION"); envRetention != "" {
+					snapshotKeep = envRetention
+				}
+			}
+
+			reservationBasis, err := domain.ParseReservationBasis(reservation)
+			if err != nil {
+				return err
+			}
+
+			snapshotInterval, err := domain.ParseWindow(snapshotEvery)
+			if err != nil {
+				return fmt.Errorf("invalid snapshot interval: %w", err)
+			}
+			snapshotRetention, err := domain.ParseWindow(snapshotKeep)
+			if err != nil {
+				return fmt.Errorf("invalid snapshot retention: %w", err)
+			}
 
 			// Expand ~ in database path
 			if strings.HasPrefix(dbPath, "~/") {
@@ -139,6 +175,12 @@ Environment Variables:
 
 			// Create and start API server
 			server := api.NewServer(store, ":"+port)
+			server.SetReservation(reservationBasis)
+
+			// Persist capacity snapshots in the background for history and forecasts
+			snapshotCtx, stopSnapshots := context.WithCancel(ctx)
+			defer stopSnapshots()
+			server.StartSnapshots(snapshotCtx, snapshotInterval, snapshotRetention)
 
 			// Start WebUI if enabled
 			var webuiServer *http.Server
@@ -159,6 +201,7 @@ Environment Variables:
 			go func() {
 				<-sigChan
 				fmt.Println("\nShutting down servers...")
+				stopSnapshots()
 				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 
@@ -182,6 +225,9 @@ Environment Variables:
 	cmd.Flags().BoolVar(&seedData, "seed", false, "Seed database with sample data")
 	cmd.Flags().BoolVar(&enableWebUI, "webui", false, "Enable WebUI on separate port (requires KUBEBUDDY_ADMIN_API_KEY)")
 	cmd.Flags().StringVar(&webuiPort, "webui-port", "8081", "WebUI server port")
+	cmd.Flags().StringVar(&reservation, "reservation", string(domain.DefaultReservation), "Default reservation basis for planning, admission and reports (min, max or p0-p100)")
+	cmd.Flags().StringVar(&snapshotEvery, "snapshot-interval", "1h", "Capacity snapshot interval, e.g. 1h or 1d (0 disables snapshots)")
+	cmd.Flags().StringVar(&snapshotKeep, "snapshot-retention", "90d", "How long capacity snapshots are kept (0 keeps them all)")
 
 	return cmd
 }
@@ -254,7 +300,7 @@ func setupAPIRoutes(mux *http.ServeMux, c *client.Client, apiEndpoint, apiKey st
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			result, err := c.CreateCompute(ctx, &compute)
+			result, err := c.CreateCompute(ctx, &compute, r.URL.Query().Get("force") == "true")
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
@@ -282,7 +328,7 @@ func setupAPIRoutes(mux *http.ServeMux, c *client.Client, apiEndpoint, apiKey st
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			result, err := c.UpdateCompute(ctx, id, &compute)
+			result, err := c.UpdateCompute(ctx, id, &compute, r.URL.Query().Get("force") == "true")
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -44,6 +46,17 @@ func (s *Server) createAssignment(c *gin.Context) {
 		return
 	}
 
+	if err := assignment.Schedule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid schedule", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+	c.Header("X-Reservation-Basis", string(basis))
+
 	// Verify service exists
 	service, err := s.store.Services().Get(c.Request.Context(), assignment.ServiceID)
 	if err != nil {
@@ -58,23 +71,21 @@ func (s *Server) createAssignment(c *gin.Context) {
 		return
 	}
 
-	// Populate compute resources from components
-	componentAssignments, err := s.store.ComputeComponents().ListByCompute(c.Request.Context(), compute.ID)
-	if err == nil && len(componentAssignments) > 0 {
-		// Load actual components
-		components := make([]*domain.Component, 0, len(componentAssignments))
-		for _, ca := range componentAssignments {
-			comp, err := s.store.Components().Get(c.Request.Context(), ca.ComponentID)
-			if err == nil {
-				components = append(components, comp)
-			}
-		}
-		// Calculate total resources from components
-		compute.Resources = compute.GetTotalResourcesFromComponents(components, componentAssignments)
+	// Populate compute resources from components, with overcommit applied and the
+	// resources of hosted computes rolled up
+	hosted, err := s.store.Computes().List(c.Request.Context(), storage.ComputeFilters{ParentID: compute.ID})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load hosted computes", err)
+		return
+	}
+	if err := s.populateResources(c.Request.Context(), append([]*domain.Compute{compute}, hosted...)); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute resources", err)
+		return
 	}
 
-	// Check if assignment already exists first (for upsert logic)
-	existing, err := s.store.Assignments().GetByComputeAndService(c.Request.Context(), assignment.ComputeID, assignment.ServiceID)
+	// Check if assignment already exists first (for upsert logic); assignments with another
+	// schedule are separate
+	existing, err := s.store.Assignments().GetByComputeAndService(c.Request.Context(), assignment.ComputeID, assignment.ServiceID, assignment.Schedule)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, "failed to check existing assignment", err)
 		return
@@ -101,14 +112,15 @@ func (s *Server) createAssignment(c *gin.Context) {
 		servicesMap[svc.ID] = svc
 	}
 
-	if !force {
-		// Check placement rules
-		if !service.CanPlaceOn(compute, allAssignments) {
-			handleError(c, http.StatusBadRequest, "placement rules violated", nil)
-			return
-		}
+	// Get all computes to resolve topology domains of existing assignments
+	allComputes, err := s.store.Computes().List(c.Request.Context(), storage.ComputeFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		return
+	}
 
-		// For updates, exclude the existing assignment from capacity check
+	if !force {
+		// For updates, exclude the existing assignment from placement and capacity checks
 		assignmentsForCapacity := allAssignments
 		if existing != nil {
 			assignmentsForCapacity = make([]*domain.Assignment, 0, len(allAssignments)-1)
@@ -119,30 +131,63 @@ func (s *Server) createAssignment(c *gin.Context) {
 			}
 		}
 
-		// Check if resources are available
-		allocated := compute.GetAllocatedResources(assignmentsForCapacity, servicesMap)
-		available := compute.GetAvailableResources(allocated)
+		// Only assignments running while this one does compete for capacity
+		window := assignment.Schedule.From(time.Now())
+		assignmentsForCapacity = domain.ScheduledAssignments(assignmentsForCapacity, window)
 
-		// Use service max spec for capacity planning, multiplied by requested quantity
 		quantity := assignment.Quantity
 		if quantity == 0 {
 			quantity = 1
 		}
 
-		requiredResources := make(domain.Resources)
-		for key, value := range service.MaxSpec {
-			switch v := value.(type) {
-			case int:
-				requiredResources[key] = v * quantity
-			case float64:
-				requiredResources[key] = v * float64(quantity)
-			default:
-				requiredResources[key] = value
-			}
+		// Check placement rules
+		if err := service.CheckPlacement(compute, assignmentsForCapacity, allComputes, servicesMap); err != nil {
+			handleError(c, http.StatusBadRequest, "placement rules violated", err)
+			return
+		}
+
+		// Instances of the request itself must also stay apart when the service excludes itself
+		if err := service.CheckInstancesApart(compute, quantity); err != nil {
+			handleError(c, http.StatusBadRequest, "placement rules violated", err)
+			return
 		}
 
+		// Check spread constraint against the requested quantity, on top of the instances other
+		// assignments running at the same time place on the compute
+		if err := service.CheckSpreadMax(compute.ID, assignmentsForCapacity, quantity); err != nil {
+			handleError(c, http.StatusBadRequest, "placement rules violated", err)
+			return
+		}
+
+		// Capacity held by reservations on the compute is not available
+		held, err := s.heldOn(c.Request.Context(), compute.ID, allServices, assignmentsForCapacity, basis, *window.StartsAt)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+			return
+		}
+		compute.Held = held
+
+		// Check if resources are available
+		allocated := compute.GetAllocatedResources(assignmentsForCapacity, servicesMap, basis)
+		available := compute.GetAvailableResources(allocated)
+
+		// Use the spec reserved under the basis, as the planner does, multiplied by requested quantity
+		requiredResources := basis.Spec(service).Scale(float64(quantity))
+
 		if !domain.CanFitResources(requiredResources, available) {
-			handleError(c, http.StatusBadRequest, "insufficient resources available", nil)
+			handleError(c, http.StatusBadRequest, fmt.Sprintf("insufficient resources available (reservation %s)", basis), nil)
+			return
+		}
+
+		// Check that the storage requirements fit storage groups of their tier and redundancy,
+		// and the tier capacity the specs leave
+		requiredStorage, err := compute.CheckStorage(service, quantity, assignmentsForCapacity, servicesMap)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "insufficient storage available", err)
+			return
+		}
+		if !domain.CanFitResources(requiredResources.Add(requiredStorage), available) {
+			handleError(c, http.StatusBadRequest, fmt.Sprintf("insufficient storage available (reservation %s)", basis), nil)
 			return
 		}
 	}
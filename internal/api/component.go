@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -141,20 +143,51 @@ func (s *Server) assignComponent(c *gin.Context) {
 		assignment.Quantity = 1
 	}
 
+	if assignment.RaidLevel != "" {
+		level, err := domain.ParseRaidLevel(string(assignment.RaidLevel))
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid RAID configuration", err)
+			return
+		}
+		assignment.RaidLevel = level
+	}
+	if err := assignment.ValidateRaid(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid RAID configuration", err)
+		return
+	}
+
 	assignment.CreatedAt = time.Now()
 
 	// Verify compute exists
-	if _, err := s.store.Computes().Get(c.Request.Context(), assignment.ComputeID); err != nil {
+	compute, err := s.store.Computes().Get(c.Request.Context(), assignment.ComputeID)
+	if err != nil {
 		handleError(c, http.StatusNotFound, "compute not found", err)
 		return
 	}
 
 	// Verify component exists
-	if _, err := s.store.Components().Get(c.Request.Context(), assignment.ComponentID); err != nil {
+	component, err := s.store.Components().Get(c.Request.Context(), assignment.ComponentID)
+	if err != nil {
 		handleError(c, http.StatusNotFound, "component not found", err)
 		return
 	}
 
+	// Reject RAID groups that cannot be built, unless force=true (e.g. to add the disks of a
+	// group one model at a time)
+	if assignment.RaidGroup != "" && c.Query("force") != "true" {
+		components, assignments, rules, err := s.computeComponents(c.Request.Context(), compute.ID)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load compute components", err)
+			return
+		}
+		components = append(components, component)
+
+		if err := compute.CheckRaidGroup(&assignment, components, assignments, rules); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid RAID group", err)
+			return
+		}
+	}
+
 	if err := s.store.ComputeComponents().Assign(c.Request.Context(), &assignment); err != nil {
 		handleError(c, http.StatusInternalServerError, "failed to assign component", err)
 		return
@@ -166,6 +199,20 @@ func (s *Server) assignComponent(c *gin.Context) {
 func (s *Server) unassignComponent(c *gin.Context) {
 	id := c.Param("id")
 
+	assignment, err := s.store.ComputeComponents().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "assignment not found", err)
+		return
+	}
+
+	// Reject removing disks a storage pool is built on, unless force=true
+	if c.Query("force") != "true" {
+		if err := s.checkPoolsKept(c.Request.Context(), assignment); err != nil {
+			handleError(c, http.StatusConflict, "component is used by a storage pool", err)
+			return
+		}
+	}
+
 	if err := s.store.ComputeComponents().Unassign(c.Request.Context(), id); err != nil {
 		handleError(c, http.StatusNotFound, "assignment not found", err)
 		return
@@ -174,6 +221,43 @@ func (s *Server) unassignComponent(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "component unassigned successfully"})
 }
 
+// checkPoolsKept returns an error when removing the component assignment breaks a storage pool
+// of its compute
+func (s *Server) checkPoolsKept(ctx context.Context, removed *domain.ComputeComponent) error {
+	if removed.RaidGroup == "" {
+		return nil
+	}
+
+	pools, err := s.store.Pools().List(ctx, storage.PoolFilters{ComputeID: removed.ComputeID})
+	if err != nil || len(pools) == 0 {
+		return err
+	}
+
+	compute, err := s.store.Computes().Get(ctx, removed.ComputeID)
+	if err != nil {
+		return err
+	}
+	components, assignments, rules, err := s.computeComponents(ctx, compute.ID)
+	if err != nil {
+		return err
+	}
+	remaining := make([]*domain.ComputeComponent, 0, len(assignments))
+	for _, assignment := range assignments {
+		if assignment.ID != removed.ID {
+			remaining = append(remaining, assignment)
+		}
+	}
+
+	volumes, err := s.store.Volumes().List(ctx, storage.VolumeFilters{})
+	if err != nil {
+		return err
+	}
+
+	before := compute.GetStorageLayoutFromComponents(components, assignments, rules)
+	after := compute.GetStorageLayoutFromComponents(components, remaining, rules)
+	return domain.CheckPoolsKept(pools, before, after, volumes)
+}
+
 func (s *Server) listComputeComponents(c *gin.Context) {
 	computeID := c.Query("compute_id")
 	componentID := c.Query("component_id")
@@ -197,3 +281,43 @@ func (s *Server) listComputeComponents(c *gin.Context) {
 
 	c.JSON(http.StatusOK, assignments)
 }
+
+func (s *Server) getComputeStorage(c *gin.Context) {
+	compute, err := s.store.Computes().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, http.StatusNotFound, "compute not found", err)
+		return
+	}
+
+	components, assignments, rules, err := s.computeComponents(c.Request.Context(), compute.ID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute components", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, compute.GetStorageLayoutFromComponents(components, assignments, rules))
+}
+
+// computeComponents loads the components assigned to a compute, the assignments and the
+// derivation rules
+func (s *Server) computeComponents(ctx context.Context, computeID string) ([]*domain.Component, []*domain.ComputeComponent, []*domain.DerivationRule, error) {
+	rules, err := s.store.DerivationRules().List(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load derivation rules: %w", err)
+	}
+
+	assignments, err := s.store.ComputeComponents().ListByCompute(ctx, computeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	components := make([]*domain.Component, 0, len(assignments))
+	for _, assignment := range assignments {
+		component, err := s.store.Components().Get(ctx, assignment.ComponentID)
+		if err == nil {
+			components = append(components, component)
+		}
+	}
+
+	return components, assignments, rules, nil
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -11,12 +13,20 @@ import (
 )
 
 func (s *Server) listComputes(c *gin.Context) {
+	tags, err := domain.ParseSelector(c.Query("tags"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid tag selector", err)
+		return
+	}
+
+	// Exact labels are filtered by the store, expressions after listing
 	filters := storage.ComputeFilters{
 		Type:     c.Query("type"),
 		Provider: c.Query("provider"),
 		Region:   c.Query("region"),
 		State:    c.Query("state"),
-		Tags:     ParseTags(c.Query("tags")),
+		Tags:     tags.MatchLabels,
+		ParentID: c.Query("parent_id"),
 	}
 
 	computes, err := s.store.Computes().List(c.Request.Context(), filters)
@@ -25,9 +35,78 @@ func (s *Server) listComputes(c *gin.Context) {
 		return
 	}
 
+	if len(tags.MatchExpressions) > 0 {
+		expressions := domain.TagSelector{MatchExpressions: tags.MatchExpressions}
+		matching := make([]*domain.Compute, 0, len(computes))
+		for _, compute := range computes {
+			if expressions.Matches(compute.Tags) {
+				matching = append(matching, compute)
+			}
+		}
+		computes = matching
+	}
+
+	// Hardware facts are derived from components, so they are matched after listing
+	if hardware := c.Query("hardware"); hardware != "" {
+		selector, err := domain.ParseSelector(hardware)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid hardware selector", err)
+			return
+		}
+
+		facts, err := s.hardwareFacts(c.Request.Context())
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load hardware facts", err)
+			return
+		}
+
+		matching := make([]*domain.Compute, 0, len(computes))
+		for _, compute := range computes {
+			if selector.MatchesFacts(facts[compute.ID]) {
+				matching = append(matching, compute)
+			}
+		}
+		computes = matching
+	}
+
 	c.JSON(http.StatusOK, computes)
 }
 
+func (s *Server) getComputeHardware(c *gin.Context) {
+	compute, err := s.store.Computes().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, http.StatusNotFound, "compute not found", err)
+		return
+	}
+
+	facts, err := s.hardwareFacts(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load hardware facts", err)
+		return
+	}
+
+	hardware := facts[compute.ID]
+	if hardware == nil {
+		hardware = make(domain.HardwareFacts)
+	}
+	c.JSON(http.StatusOK, hardware)
+}
+
+// hardwareFacts returns the hardware facts of every compute by compute ID, hosted computes
+// inheriting the facts of their parent
+func (s *Server) hardwareFacts(ctx context.Context) (map[string]domain.HardwareFacts, error) {
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := make(map[string]domain.HardwareFacts, len(computes))
+	for _, compute := range computes {
+		facts[compute.ID] = compute.Facts
+	}
+	return facts, nil
+}
+
 func (s *Server) getCompute(c *gin.Context) {
 	id := c.Param("id")
 
@@ -48,6 +127,11 @@ func (s *Server) createCompute(c *gin.Context) {
 		return
 	}
 
+	if err := domain.ValidateOvercommit(compute.Overcommit); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid overcommit ratios", err)
+		return
+	}
+
 	// Initialize empty maps if nil
 	if compute.Tags == nil {
 		compute.Tags = make(map[string]string)
@@ -69,6 +153,13 @@ func (s *Server) createCompute(c *gin.Context) {
 		return
 	}
 
+	if existing != nil {
+		compute.ID = existing.ID
+	}
+	if !s.checkHierarchy(c, &compute) {
+		return
+	}
+
 	if existing != nil {
 		// Update existing compute
 		compute.ID = existing.ID
@@ -117,6 +208,11 @@ func (s *Server) updateCompute(c *gin.Context) {
 		return
 	}
 
+	if err := domain.ValidateOvercommit(compute.Overcommit); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid overcommit ratios", err)
+		return
+	}
+
 	// Check for name+provider+region+type conflict if any of these fields changed
 	if compute.Name != existing.Name || compute.Provider != existing.Provider ||
 		compute.Region != existing.Region || compute.Type != existing.Type {
@@ -141,6 +237,10 @@ func (s *Server) updateCompute(c *gin.Context) {
 	compute.ID = existing.ID
 	compute.CreatedAt = existing.CreatedAt
 
+	if !s.checkHierarchy(c, &compute) {
+		return
+	}
+
 	if err := s.store.Computes().Update(c.Request.Context(), &compute); err != nil {
 		handleError(c, http.StatusInternalServerError, "failed to update compute", err)
 		return
@@ -152,6 +252,16 @@ func (s *Server) updateCompute(c *gin.Context) {
 func (s *Server) deleteCompute(c *gin.Context) {
 	id := c.Param("id")
 
+	hosted, err := s.store.Computes().List(c.Request.Context(), storage.ComputeFilters{ParentID: id})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check hosted computes", err)
+		return
+	}
+	if len(hosted) > 0 {
+		handleError(c, http.StatusConflict, fmt.Sprintf("compute hosts %d compute(s), delete or move them first", len(hosted)), nil)
+		return
+	}
+
 	if err := s.store.Computes().Delete(c.Request.Context(), id); err != nil {
 		handleError(c, http.StatusNotFound, "compute not found", err)
 		return
@@ -159,3 +269,120 @@ func (s *Server) deleteCompute(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "compute deleted successfully"})
 }
+
+// checkHierarchy validates the size and parent of a compute, writing the error response on failure.
+// A compute hosting others must stay a baremetal compute without a parent, and a hosted compute
+// must fit in the resources left on its parent, less the capacity reservations hold there, unless force=true.
+func (s *Server) checkHierarchy(c *gin.Context, compute *domain.Compute) bool {
+	ctx := c.Request.Context()
+
+	if len(compute.Size) > 0 {
+		registry, err := s.resourceRegistry(ctx)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+			return false
+		}
+		size, err := registry.Normalize(compute.Size)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid size", err)
+			return false
+		}
+		compute.Size = size
+	}
+
+	if compute.ID != "" {
+		hosted, err := s.store.Computes().List(ctx, storage.ComputeFilters{ParentID: compute.ID})
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check hosted computes", err)
+			return false
+		}
+		if len(hosted) > 0 && !compute.CanHostComputes() {
+			handleError(c, http.StatusConflict, fmt.Sprintf("compute hosts %d compute(s) and must stay a baremetal compute without a parent", len(hosted)), nil)
+			return false
+		}
+	}
+
+	if compute.ParentID == "" {
+		return true
+	}
+
+	parent, err := s.store.Computes().Get(ctx, compute.ParentID)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "parent compute not found", err)
+		return false
+	}
+	if err := compute.ValidateParent(parent); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid parent", err)
+		return false
+	}
+
+	if c.Query("force") == "true" {
+		return true
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return false
+	}
+
+	// Resources left on the parent, without the compute itself
+	siblings, err := s.store.Computes().List(ctx, storage.ComputeFilters{ParentID: parent.ID})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load hosted computes", err)
+		return false
+	}
+	host := []*domain.Compute{parent}
+	for _, sibling := range siblings {
+		if sibling.ID != compute.ID {
+			host = append(host, sibling)
+		}
+	}
+	candidate := *compute
+	if err := s.populateResources(ctx, host); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute resources", err)
+		return false
+	}
+	if err := s.populateResources(ctx, []*domain.Compute{&candidate}); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute resources", err)
+		return false
+	}
+
+	// Only assignments running now take capacity of the parent, as for the planner
+	now := time.Now()
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{ComputeID: parent.ID})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return false
+	}
+	assignments = domain.ActiveAssignments(assignments, now)
+	services, err := s.store.Services().List(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+		return false
+	}
+	servicesMap := make(map[string]*domain.Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+
+	// Capacity held by reservations on the parent is not available
+	allAssignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return false
+	}
+	held, err := s.heldOn(ctx, parent.ID, services, domain.ActiveAssignments(allAssignments, now), basis, now)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return false
+	}
+	parent.Held = held
+
+	available := parent.GetAvailableResources(parent.GetAllocatedResources(assignments, servicesMap, basis))
+	if !domain.CanFitResources(candidate.Footprint(), available) {
+		handleError(c, http.StatusBadRequest, fmt.Sprintf("insufficient resources available on parent %s (reservation %s)", parent.Name, basis), nil)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+func (s *Server) listDerivationRules(c *gin.Context) {
+	rules, err := s.store.DerivationRules().List(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list derivation rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (s *Server) getDerivationRule(c *gin.Context) {
+	id := c.Param("id")
+
+	rule, err := s.store.DerivationRules().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "derivation rule not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (s *Server) createDerivationRule(c *gin.Context) {
+	var rule domain.DerivationRule
+
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if rule.Aggregation == "" {
+		rule.Aggregation = domain.AggregationSum
+	}
+
+	if err := rule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid derivation rule", err)
+		return
+	}
+
+	// Check if rule with same name already exists (upsert)
+	existing, err := s.store.DerivationRules().GetByName(c.Request.Context(), rule.Name)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check existing derivation rule", err)
+		return
+	}
+
+	if existing != nil {
+		// Update existing rule
+		rule.ID = existing.ID
+		rule.CreatedAt = existing.CreatedAt
+		rule.UpdatedAt = time.Now()
+
+		if err := s.store.DerivationRules().Update(c.Request.Context(), &rule); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to update derivation rule", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, rule)
+	} else {
+		// Create new rule
+		if rule.ID == "" {
+			rule.ID = uuid.New().String()
+		}
+
+		now := time.Now()
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+
+		if rule.Priority == 0 {
+			rule.Priority = 100 // Default priority
+		}
+
+		if err := s.store.DerivationRules().Create(c.Request.Context(), &rule); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to create derivation rule", err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+func (s *Server) updateDerivationRule(c *gin.Context) {
+	id := c.Param("id")
+
+	existing, err := s.store.DerivationRules().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "derivation rule not found", err)
+		return
+	}
+
+	var rule domain.DerivationRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if rule.Aggregation == "" {
+		rule.Aggregation = domain.AggregationSum
+	}
+
+	if err := rule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid derivation rule", err)
+		return
+	}
+
+	if rule.Name != existing.Name {
+		conflict, err := s.store.DerivationRules().GetByName(c.Request.Context(), rule.Name)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check uniqueness", err)
+			return
+		}
+		if conflict != nil {
+			handleError(c, http.StatusConflict, "derivation rule with this name already exists", nil)
+			return
+		}
+	}
+
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if err := s.store.DerivationRules().Update(c.Request.Context(), &rule); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to update derivation rule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (s *Server) deleteDerivationRule(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := s.store.DerivationRules().Delete(c.Request.Context(), id); err != nil {
+		handleError(c, http.StatusNotFound, "derivation rule not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "derivation rule deleted successfully"})
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+// planDrain returns the rehoming plan for every assignment on a compute
+func (s *Server) planDrain(c *gin.Context) {
+	plan, ok := s.loadDrainPlan(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// executeDrain moves the assignments of a compute, with their port assignments, in one transaction
+func (s *Server) executeDrain(c *gin.Context) {
+	plan, ok := s.loadDrainPlan(c)
+	if !ok {
+		return
+	}
+
+	if !plan.Feasible && c.Query("force") != "true" {
+		handleError(c, http.StatusConflict, "some assignments cannot be moved, use force=true to move the others", nil)
+		return
+	}
+
+	if len(plan.Moves) == 0 {
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	createdBy := ""
+	if apiKey := GetAPIKey(c); apiKey != nil {
+		createdBy = apiKey.Name
+	}
+
+	err := s.store.WithTx(c.Request.Context(), func(tx storage.Storage) error {
+		warnings, err := applyMoves(c.Request.Context(), tx, plan.Moves)
+		if err != nil {
+			return err
+		}
+		plan.Warnings = warnings
+
+		return tx.Journal().Create(c.Request.Context(), &domain.JournalEntry{
+			ID:        uuid.New().String(),
+			ComputeID: plan.Compute.ID,
+			Category:  domain.JournalCategoryMaintenance,
+			Content:   drainJournalContent(plan),
+			CreatedBy: createdBy,
+		})
+	})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to drain compute", err)
+		return
+	}
+
+	plan.Executed = true
+	c.JSON(http.StatusOK, plan)
+}
+
+// loadDrainPlan plans the drain of the compute in the request path, writing the error response on failure
+func (s *Server) loadDrainPlan(c *gin.Context) (*domain.DrainPlan, bool) {
+	id := c.Param("id")
+
+	if _, err := s.store.Computes().Get(c.Request.Context(), id); err != nil {
+		handleError(c, http.StatusNotFound, "compute not found", err)
+		return nil, false
+	}
+
+	strategy := c.Query("strategy")
+	if _, err := domain.GetScoringStrategy(strategy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid strategy", err)
+		return nil, false
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return nil, false
+	}
+
+	planner, _, err := s.loadPlanner(c.Request.Context(), basis, time.Now())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return nil, false
+	}
+
+	plan, err := planner.PlanDrain(id, strategy)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to plan drain", err)
+		return nil, false
+	}
+
+	return plan, true
+}
+
+// applyMoves moves assignment instances to their target computes. Instances join an existing
+// assignment of the same service and schedule on the target, otherwise a new assignment is created. When
+// every instance of an assignment leaves its compute, its port assignments follow the first
+// target (re-pointed to the target's primary IP) and the source assignment is deleted.
+func applyMoves(ctx context.Context, tx storage.Storage, moves []domain.Move) ([]string, error) {
+	var warnings []string
+
+	remaining := make(map[string]*domain.Assignment)
+	firstTarget := make(map[string]*domain.Assignment)
+	order := make([]string, 0)
+
+	for _, move := range moves {
+		source, ok := remaining[move.AssignmentID]
+		if !ok {
+			var err error
+			source, err = tx.Assignments().Get(ctx, move.AssignmentID)
+			if err != nil {
+				return nil, fmt.Errorf("assignment %s not found: %w", move.AssignmentID, err)
+			}
+			if source.Quantity == 0 {
+				source.Quantity = 1
+			}
+			remaining[move.AssignmentID] = source
+			order = append(order, move.AssignmentID)
+		}
+		if move.Quantity > source.Quantity {
+			return nil, fmt.Errorf("assignment %s has only %d instance(s) left to move", source.ID, source.Quantity)
+		}
+
+		target, err := tx.Assignments().GetByComputeAndService(ctx, move.To.ID, move.ServiceID, source.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing assignment: %w", err)
+		}
+		if target != nil {
+			target.Quantity += move.Quantity
+			if err := tx.Assignments().Update(ctx, target); err != nil {
+				return nil, err
+			}
+		} else {
+			target = &domain.Assignment{
+				ID:        uuid.New().String(),
+				ServiceID: move.ServiceID,
+				ComputeID: move.To.ID,
+				Quantity:  move.Quantity,
+				Notes:     source.Notes,
+				Schedule:  source.Schedule,
+			}
+			if err := tx.Assignments().Create(ctx, target); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, ok := firstTarget[source.ID]; !ok {
+			firstTarget[source.ID] = target
+		}
+		source.Quantity -= move.Quantity
+	}
+
+	for _, id := range order {
+		source := remaining[id]
+		if source.Quantity > 0 {
+			if err := tx.Assignments().Update(ctx, source); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		moved, err := movePortAssignments(ctx, tx, source, firstTarget[id])
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, moved...)
+
+		if err := tx.Assignments().Delete(ctx, source.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// movePortAssignments re-points the port assignments of source to target
+func movePortAssignments(ctx context.Context, tx storage.Storage, source, target *domain.Assignment) ([]string, error) {
+	var warnings []string
+
+	ports, err := tx.PortAssignments().List(ctx, storage.PortAssignmentFilters{AssignmentID: source.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list port assignments: %w", err)
+	}
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	primary, err := tx.ComputeIPs().GetPrimaryIP(ctx, target.ComputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, port := range ports {
+		port.AssignmentID = target.ID
+
+		if primary == nil {
+			warnings = append(warnings, fmt.Sprintf("port %d/%s kept its IP, target compute %s has no primary IP", port.Port, port.Protocol, target.ComputeID))
+		} else if primary.IPID != port.IPID {
+			conflict, err := tx.PortAssignments().GetByIPPortProtocol(ctx, primary.IPID, port.Port, string(port.Protocol))
+			if err != nil {
+				return nil, err
+			}
+			if conflict != nil {
+				return nil, fmt.Errorf("port %d/%s is already assigned on the primary IP of compute %s", port.Port, port.Protocol, target.ComputeID)
+			}
+			port.IPID = primary.IPID
+		}
+
+		if err := tx.PortAssignments().Update(ctx, port); err != nil {
+			return nil, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// drainJournalContent describes the executed moves as markdown
+func drainJournalContent(plan *domain.DrainPlan) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Drained compute %s (%d move(s))\n\n", plan.Compute.Name, len(plan.Moves))
+	for _, move := range plan.Moves {
+		fmt.Fprintf(&b, "- %s: %d instance(s) moved to %s\n", move.ServiceName, move.Quantity, move.To.Name)
+	}
+	for _, unmovable := range plan.Unmovable {
+		fmt.Fprintf(&b, "- %s: %d instance(s) not moved (%s)\n", unmovable.ServiceName, unmovable.Quantity, unmovable.Reason)
+	}
+
+	return b.String()
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/studiowebux/kubebuddy/internal/domain"
@@ -118,21 +117,3 @@ func GetAPIKey(c *gin.Context) *domain.APIKey {
 	key, _ := c.Get("api_key")
 	return key.(*domain.APIKey)
 }
-
-// ParseTags helper for parsing tag query parameters (format: "key1=value1,key2=value2")
-func ParseTags(tagsParam string) map[string]string {
-	tags := make(map[string]string)
-	if tagsParam == "" {
-		return tags
-	}
-
-	pairs := strings.Split(tagsParam, ",")
-	for _, pair := range pairs {
-		kv := strings.SplitN(pair, "=", 2)
-		if len(kv) == 2 {
-			tags[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
-		}
-	}
-
-	return tags
-}
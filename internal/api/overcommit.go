@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+func (s *Server) listOvercommitPolicies(c *gin.Context) {
+	policies, err := s.store.OvercommitPolicies().List(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list overcommit policies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (s *Server) getOvercommitPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	policy, err := s.store.OvercommitPolicies().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "overcommit policy not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (s *Server) createOvercommitPolicy(c *gin.Context) {
+	var policy domain.OvercommitPolicy
+
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := policy.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid overcommit policy", err)
+		return
+	}
+
+	if policy.Tags == nil {
+		policy.Tags = make(map[string]string)
+	}
+
+	// Check if policy with same name already exists (upsert)
+	existing, err := s.store.OvercommitPolicies().GetByName(c.Request.Context(), policy.Name)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check existing overcommit policy", err)
+		return
+	}
+
+	if existing != nil {
+		// Update existing policy
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+		policy.UpdatedAt = time.Now()
+
+		if err := s.store.OvercommitPolicies().Update(c.Request.Context(), &policy); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to update overcommit policy", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	} else {
+		// Create new policy
+		if policy.ID == "" {
+			policy.ID = uuid.New().String()
+		}
+
+		now := time.Now()
+		policy.CreatedAt = now
+		policy.UpdatedAt = now
+
+		if policy.Priority == 0 {
+			policy.Priority = 100 // Default priority
+		}
+
+		if err := s.store.OvercommitPolicies().Create(c.Request.Context(), &policy); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to create overcommit policy", err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, policy)
+	}
+}
+
+func (s *Server) updateOvercommitPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	existing, err := s.store.OvercommitPolicies().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "overcommit policy not found", err)
+		return
+	}
+
+	var policy domain.OvercommitPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := policy.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid overcommit policy", err)
+		return
+	}
+
+	if policy.Name != existing.Name {
+		conflict, err := s.store.OvercommitPolicies().GetByName(c.Request.Context(), policy.Name)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check uniqueness", err)
+			return
+		}
+		if conflict != nil {
+			handleError(c, http.StatusConflict, "overcommit policy with this name already exists", nil)
+			return
+		}
+	}
+
+	if policy.Tags == nil {
+		policy.Tags = make(map[string]string)
+	}
+
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	policy.UpdatedAt = time.Now()
+
+	if err := s.store.OvercommitPolicies().Update(c.Request.Context(), &policy); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to update overcommit policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (s *Server) deleteOvercommitPolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := s.store.OvercommitPolicies().Delete(c.Request.Context(), id); err != nil {
+		handleError(c, http.StatusNotFound, "overcommit policy not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "overcommit policy deleted successfully"})
+}
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/studiowebux/kubebuddy/internal/domain"
 	"github.com/studiowebux/kubebuddy/internal/storage"
 )
@@ -16,94 +20,275 @@ func (s *Server) planCapacity(c *gin.Context) {
 		return
 	}
 
+	if err := request.Schedule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid schedule", err)
+		return
+	}
+
+	if _, err := domain.GetScoringStrategy(request.Strategy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid strategy", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
 	// Load all data for planning
-	computes, err := s.store.Computes().List(c.Request.Context(), storage.ComputeFilters{})
+	planner, _, err := s.loadPlanner(c.Request.Context(), basis, *request.Schedule.From(time.Now()).StartsAt)
 	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
 		return
 	}
 
-	// Populate compute resources from components
-	for _, compute := range computes {
-		// Get component assignments for this compute
-		componentAssignments, err := s.store.ComputeComponents().ListByCompute(c.Request.Context(), compute.ID)
-		if err != nil {
-			continue // Skip on error
+	if !request.WhatIf.IsEmpty() {
+		if _, err := planner.WithWhatIf(request.WhatIf); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid what-if scenario", err)
+			return
 		}
+	}
 
-		if len(componentAssignments) > 0 {
-			// Load actual components
-			components := make([]*domain.Component, 0, len(componentAssignments))
-			for _, ca := range componentAssignments {
-				comp, err := s.store.Components().Get(c.Request.Context(), ca.ComponentID)
-				if err == nil {
-					components = append(components, comp)
-				}
-			}
+	result, err := planner.Plan(request)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to plan capacity", err)
+		return
+	}
 
-			// Calculate total resources from components
-			compute.Resources = compute.GetTotalResourcesFromComponents(components, componentAssignments)
-		}
+	c.JSON(http.StatusOK, result)
+}
+
+func (s *Server) applyPlan(c *gin.Context) {
+	var request domain.PlanRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
 	}
 
-	services, err := s.store.Services().List(c.Request.Context())
-	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+	if err := request.Schedule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid schedule", err)
 		return
 	}
 
-	assignments, err := s.store.Assignments().List(c.Request.Context(), storage.AssignmentFilters{})
+	if _, err := domain.GetScoringStrategy(request.Strategy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid strategy", err)
+		return
+	}
+
+	if !request.WhatIf.IsEmpty() {
+		handleError(c, http.StatusBadRequest, "a what-if plan cannot be applied", nil)
+		return
+	}
+
+	if request.AsVM {
+		handleError(c, http.StatusBadRequest, "an as_vm plan cannot be applied, create the planned VMs instead", nil)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	planner, assignments, err := s.loadPlanner(c.Request.Context(), basis, *request.Schedule.From(time.Now()).StartsAt)
 	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
 		return
 	}
 
-	// Create planner and execute
-	planner := domain.NewCapacityPlanner(computes, services, assignments)
 	result, err := planner.Plan(request)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, "failed to plan capacity", err)
 		return
 	}
 
+	if !result.Feasible || len(result.Placements) == 0 {
+		handleError(c, http.StatusConflict, "no suitable compute found, nothing applied", nil)
+		return
+	}
+
+	if result.Spread != nil && !result.Spread.Satisfied && c.Query("force") != "true" {
+		handleError(c, http.StatusConflict, "topology spread not met, use force=true to apply anyway", nil)
+		return
+	}
+
+	planned := result.PlannedAssignments(request.ServiceID, assignments)
+
+	err = s.store.WithTx(c.Request.Context(), func(tx storage.Storage) error {
+		for _, assignment := range planned {
+			if assignment.ID != "" {
+				if err := tx.Assignments().Update(c.Request.Context(), assignment); err != nil {
+					return err
+				}
+				continue
+			}
+			assignment.ID = uuid.New().String()
+			if err := tx.Assignments().Create(c.Request.Context(), assignment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to apply plan", err)
+		return
+	}
+
+	result.Applied = true
+	result.Assignments = planned
+
 	c.JSON(http.StatusOK, result)
 }
 
-type CapacityReportResponse struct {
-	TotalComputes      int                    `json:"total_computes"`
-	ActiveComputes     int                    `json:"active_computes"`
-	TotalServices      int                    `json:"total_services"`
-	TotalAssignments   int                    `json:"total_assignments"`
-	ComputeUtilization []ComputeUtilization   `json:"compute_utilization"`
+func (s *Server) planStack(c *gin.Context) {
+	var request domain.StackPlanRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := request.Schedule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid schedule", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	planner, _, err := s.loadPlanner(c.Request.Context(), basis, *request.Schedule.From(time.Now()).StartsAt)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return
+	}
+
+	result, err := planner.PlanStack(request)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "failed to plan stack", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
-type ComputeUtilization struct {
-	Compute         *domain.Compute       `json:"compute"`
-	TotalResources  domain.Resources      `json:"total_resources"`
-	Allocated       domain.Resources      `json:"allocated"`
-	Available       domain.Resources      `json:"available"`
-	UtilizationPct  float64               `json:"utilization_pct"`
-	Statistics      *ResourceStatistics   `json:"statistics,omitempty"`
+// applyStack plans the stack and, when every member fits, creates all assignments in one transaction
+func (s *Server) applyStack(c *gin.Context) {
+	var request domain.StackPlanRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := request.Schedule.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid schedule", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	planner, assignments, err := s.loadPlanner(c.Request.Context(), basis, *request.Schedule.From(time.Now()).StartsAt)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return
+	}
+
+	result, err := planner.PlanStack(request)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "failed to plan stack", err)
+		return
+	}
+
+	if !result.Feasible {
+		handleError(c, http.StatusConflict, "stack does not fit, nothing applied", nil)
+		return
+	}
+
+	if !result.SpreadSatisfied() && c.Query("force") != "true" {
+		handleError(c, http.StatusConflict, "topology spread not met, use force=true to apply anyway", nil)
+		return
+	}
+
+	planned := result.PlannedAssignments(assignments)
+
+	err = s.store.WithTx(c.Request.Context(), func(tx storage.Storage) error {
+		for _, assignment := range planned {
+			if assignment.ID != "" {
+				if err := tx.Assignments().Update(c.Request.Context(), assignment); err != nil {
+					return err
+				}
+				continue
+			}
+			assignment.ID = uuid.New().String()
+			if err := tx.Assignments().Create(c.Request.Context(), assignment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to apply stack", err)
+		return
+	}
+
+	result.Applied = true
+	result.Assignments = planned
+	result.Message = "all stack members placed and assigned"
+
+	c.JSON(http.StatusOK, result)
 }
 
-type ResourceStatistics struct {
-	Min    domain.Resources `json:"min"`
-	Max    domain.Resources `json:"max"`
-	Avg    domain.Resources `json:"avg"`
-	Median domain.Resources `json:"median"`
+// loadComputes lists computes with their resources calculated from assigned components
+// and scaled by their overcommit ratios
+func (s *Server) loadComputes(ctx context.Context) ([]*domain.Compute, error) {
+	computes, err := s.store.Computes().List(ctx, storage.ComputeFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.populateResources(ctx, computes); err != nil {
+		return nil, err
+	}
+
+	return computes, nil
 }
 
-func (s *Server) capacityReport(c *gin.Context) {
-	computes, err := s.store.Computes().List(c.Request.Context(), storage.ComputeFilters{})
+// populateResources calculates compute resources, storage groups and hardware facts from assigned
+// components with the derivation rules, or the size of hosted computes, applies the overcommit
+// ratios of each compute and the matching overcommit policies, then rolls hosted resources up to
+// their parent, which also gives its hardware facts to hosted computes without components. Storage
+// pools replace the RAID groups they are built on, their volumes allocated.
+func (s *Server) populateResources(ctx context.Context, computes []*domain.Compute) error {
+	rules, err := s.store.DerivationRules().List(ctx)
 	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
-		return
+		return fmt.Errorf("failed to load derivation rules: %w", err)
+	}
+
+	policies, err := s.store.OvercommitPolicies().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load overcommit policies: %w", err)
+	}
+
+	pools, err := s.store.Pools().List(ctx, storage.PoolFilters{})
+	if err != nil {
+		return fmt.Errorf("failed to load storage pools: %w", err)
+	}
+
+	volumes, err := s.store.Volumes().List(ctx, storage.VolumeFilters{})
+	if err != nil {
+		return fmt.Errorf("failed to load volumes: %w", err)
 	}
 
 	// Populate compute resources from components
 	for _, compute := range computes {
 		// Get component assignments for this compute
-		componentAssignments, err := s.store.ComputeComponents().ListByCompute(c.Request.Context(), compute.ID)
+		componentAssignments, err := s.store.ComputeComponents().ListByCompute(ctx, compute.ID)
 		if err != nil {
 			continue // Skip on error
 		}
@@ -112,209 +297,264 @@ func (s *Server) capacityReport(c *gin.Context) {
 			// Load actual components
 			components := make([]*domain.Component, 0, len(componentAssignments))
 			for _, ca := range componentAssignments {
-				comp, err := s.store.Components().Get(c.Request.Context(), ca.ComponentID)
+				comp, err := s.store.Components().Get(ctx, ca.ComponentID)
 				if err == nil {
 					components = append(components, comp)
 				}
 			}
 
 			// Calculate total resources from components
-			compute.Resources = compute.GetTotalResourcesFromComponents(components, componentAssignments)
+			compute.Resources = compute.GetTotalResourcesFromComponents(components, componentAssignments, rules)
+			compute.StorageGroups = compute.GetStorageGroupsFromComponents(components, componentAssignments, rules)
+			compute.StorageGroups = domain.PoolStorageGroups(compute.StorageGroups, computePools(pools, compute.ID), volumes)
+			compute.Facts = compute.GetHardwareFactsFromComponents(components, componentAssignments)
 		}
+
+		compute.ApplySize()
+		compute.ApplyOvercommit(policies)
 	}
 
-	services, err := s.store.Services().List(c.Request.Context())
+	domain.RollUpHosted(computes)
+	domain.InheritHardwareFacts(computes)
+
+	return nil
+}
+
+// computePools returns the storage pools of one compute
+func computePools(pools []*domain.Pool, computeID string) []*domain.Pool {
+	result := make([]*domain.Pool, 0)
+	for _, pool := range pools {
+		if pool.ComputeID == computeID {
+			result = append(result, pool)
+		}
+	}
+	return result
+}
+
+// reservationBasis returns the reservation basis from the reservation query parameter, or the
+// server default, writing the error response on failure
+func (s *Server) reservationBasis(c *gin.Context) (domain.ReservationBasis, bool) {
+	value := c.Query("reservation")
+	if value == "" {
+		return s.reservation, true
+	}
+
+	basis, err := domain.ParseReservationBasis(value)
 	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load services", err)
-		return
+		handleError(c, http.StatusBadRequest, "invalid reservation basis", err)
+		return "", false
 	}
 
-	// Build services map for resource calculation
-	servicesMap := make(map[string]*domain.Service)
-	for _, svc := range services {
-		servicesMap[svc.ID] = svc
+	return basis, true
+}
+
+// loadPlanner loads computes, services, assignments and the component catalog into a capacity planner
+// reserving service specs under the basis, with the capacity of reservations not expired at the
+// instant at held (the start of the planned window, as assignment admission does). Assignments
+// that ended are left out; the others are returned as well for callers that apply a plan.
+func (s *Server) loadPlanner(ctx context.Context, basis domain.ReservationBasis, at time.Time) (*domain.CapacityPlanner, []*domain.Assignment, error) {
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load computes: %w", err)
 	}
 
-	assignments, err := s.store.Assignments().List(c.Request.Context(), storage.AssignmentFilters{})
+	services, err := s.store.Services().List(ctx)
 	if err != nil {
-		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
-		return
+		return nil, nil, fmt.Errorf("failed to load services: %w", err)
 	}
 
-	// Calculate utilization for each compute
-	computeUtils := make([]ComputeUtilization, 0, len(computes))
-	activeCount := 0
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load assignments: %w", err)
+	}
 
-	for _, compute := range computes {
-		if compute.State == domain.ComputeStateActive {
-			activeCount++
-		}
+	// Assignments that already ended no longer take capacity
+	now := time.Now()
+	assignments = domain.ScheduledAssignments(assignments, domain.Schedule{StartsAt: &now})
 
-		allocated := compute.GetAllocatedResources(assignments, servicesMap)
-		available := compute.GetAvailableResources(allocated)
-
-		// Calculate average utilization percentage
-		totalUtil := 0.0
-		resourceCount := 0
-
-		for key, total := range compute.Resources {
-			if alloc, ok := allocated[key]; ok {
-				// Convert both to float64 for comparison
-				var totalFloat, allocFloat float64
-
-				switch t := total.(type) {
-				case int:
-					totalFloat = float64(t)
-				case float64:
-					totalFloat = t
-				default:
-					continue
-				}
+	components, err := s.store.Components().List(ctx, storage.ComponentFilters{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load components: %w", err)
+	}
 
-				switch a := alloc.(type) {
-				case int:
-					allocFloat = float64(a)
-				case float64:
-					allocFloat = a
-				default:
-					continue
-				}
+	rules, err := s.store.DerivationRules().List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load derivation rules: %w", err)
+	}
 
-				if totalFloat > 0 {
-					totalUtil += (allocFloat / totalFloat) * 100
-					resourceCount++
-				}
-			}
-		}
+	policies, err := s.store.OvercommitPolicies().List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load overcommit policies: %w", err)
+	}
 
-		avgUtil := 0.0
-		if resourceCount > 0 {
-			avgUtil = totalUtil / float64(resourceCount)
-		}
+	planner := domain.NewCapacityPlanner(computes, services, assignments)
+	planner.SetComponents(components)
+	planner.SetDerivationRules(rules)
+	planner.SetOvercommitPolicies(policies)
+	planner.SetReservation(basis)
 
-		// Calculate statistics for this compute's assignments
-		computeAssignments := make([]*domain.Assignment, 0)
-		for _, a := range assignments {
-			if a.ComputeID == compute.ID {
-				computeAssignments = append(computeAssignments, a)
-			}
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load reservations: %w", err)
+	}
+	planner.HoldReservations(reservations, at)
+
+	return planner, assignments, nil
+}
+
+func (s *Server) capacityReport(c *gin.Context) {
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	// Report the assignments running at the instant given by the at query parameter (default now)
+	at := time.Now()
+	if atStr := c.Query("at"); atStr != "" {
+		parsed, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid at time", err)
+			return
 		}
-		stats := calculateResourceStatistics(computeAssignments, servicesMap)
+		at = parsed
+	}
 
-		computeUtils = append(computeUtils, ComputeUtilization{
-			Compute:        compute,
-			TotalResources: compute.Resources,
-			Allocated:      allocated,
-			Available:      available,
-			UtilizationPct: avgUtil,
-			Statistics:     stats,
-		})
+	var whatIf *domain.WhatIf
+	if c.Request.Method == http.MethodPost {
+		whatIf = &domain.WhatIf{}
+		if err := c.ShouldBindJSON(whatIf); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid request body", err)
+			return
+		}
 	}
 
-	report := CapacityReportResponse{
-		TotalComputes:      len(computes),
-		ActiveComputes:     activeCount,
-		TotalServices:      len(services),
-		TotalAssignments:   len(assignments),
-		ComputeUtilization: computeUtils,
+	computes, err := s.loadComputes(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		return
 	}
 
-	c.JSON(http.StatusOK, report)
-}
+	services, err := s.store.Services().List(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+		return
+	}
 
-// calculateResourceStatistics calculates min/max/avg/median for resources across assignments
-// All values are based on sum of max_spec (what services could use at maximum)
-// Min = smallest max_spec across all assignments
-// Max = sum of all max_spec (total if all services maxed out)
-// Avg = average max_spec value
-// Median = median max_spec value
-func calculateResourceStatistics(assignments []*domain.Assignment, servicesMap map[string]*domain.Service) *ResourceStatistics {
-	if len(assignments) == 0 {
-		return nil
+	assignments, err := s.store.Assignments().List(c.Request.Context(), storage.AssignmentFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return
 	}
+	assignments = domain.ActiveAssignments(assignments, at)
 
-	// Collect individual max values for statistics
-	maxValues := make(map[string][]float64)
+	if !whatIf.IsEmpty() {
+		components, err := s.store.Components().List(c.Request.Context(), storage.ComponentFilters{})
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load components", err)
+			return
+		}
 
-	for _, assignment := range assignments {
-		service, ok := servicesMap[assignment.ServiceID]
-		if !ok {
-			continue
+		rules, err := s.store.DerivationRules().List(c.Request.Context())
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load derivation rules", err)
+			return
 		}
 
-		quantity := assignment.Quantity
-		if quantity == 0 {
-			quantity = 1
+		policies, err := s.store.OvercommitPolicies().List(c.Request.Context())
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load overcommit policies", err)
+			return
 		}
 
-		// Process MaxSpec
-		for key, value := range service.MaxSpec {
-			var floatVal float64
-			switch v := value.(type) {
-			case int:
-				floatVal = float64(v) * float64(quantity)
-			case float64:
-				floatVal = v * float64(quantity)
-			default:
-				continue
-			}
-			maxValues[key] = append(maxValues[key], floatVal)
+		computes, services, assignments, err = whatIf.Apply(computes, services, assignments, components, rules, policies)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid what-if scenario", err)
+			return
 		}
 	}
 
-	// Build final statistics
-	min := make(domain.Resources)
-	max := make(domain.Resources)
-	avg := make(domain.Resources)
-	median := make(domain.Resources)
+	// Hold reservations on the scenario, against the assignments it leaves
+	if _, _, err := s.holdReservations(c.Request.Context(), computes, services, assignments, basis, at); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return
+	}
 
-	for key, values := range maxValues {
-		if len(values) == 0 {
-			continue
-		}
+	report := domain.BuildCapacityReport(computes, services, assignments, basis)
+	report.WhatIf = !whatIf.IsEmpty()
+	if c.Query("at") != "" {
+		report.At = &at
+	}
 
-		// Min is the smallest max_spec value
-		minVal := values[0]
-		for _, v := range values {
-			if v < minVal {
-				minVal = v
-			}
+	c.JSON(http.StatusOK, report)
+}
+
+// capacityTimeline reports the utilization at every scheduled change between from (default now)
+// and to (default from plus the within window, 90 days by default)
+func (s *Server) capacityTimeline(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	from := time.Now()
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid from time", err)
+			return
 		}
-		min[key] = minVal
+		from = parsed
+	}
 
-		// Max is sum of all max_spec
-		sum := 0.0
-		for _, v := range values {
-			sum += v
+	to := from.Add(domain.DefaultTimelineWindow)
+	if withinStr := c.Query("within"); withinStr != "" {
+		within, err := domain.ParseWindow(withinStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid within", err)
+			return
 		}
-		max[key] = sum
-
-		// Average
-		avg[key] = sum / float64(len(values))
-
-		// Median (sort values)
-		sortedValues := make([]float64, len(values))
-		copy(sortedValues, values)
-		// Simple bubble sort for small arrays
-		for i := 0; i < len(sortedValues); i++ {
-			for j := i + 1; j < len(sortedValues); j++ {
-				if sortedValues[i] > sortedValues[j] {
-					sortedValues[i], sortedValues[j] = sortedValues[j], sortedValues[i]
-				}
-			}
+		to = from.Add(within)
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid to time", err)
+			return
 		}
+		to = parsed
+	}
+	if !to.After(from) {
+		handleError(c, http.StatusBadRequest, "to must be after from", nil)
+		return
+	}
 
-		if len(sortedValues)%2 == 0 {
-			median[key] = (sortedValues[len(sortedValues)/2-1] + sortedValues[len(sortedValues)/2]) / 2
-		} else {
-			median[key] = sortedValues[len(sortedValues)/2]
-		}
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		return
+	}
+
+	services, err := s.store.Services().List(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+		return
+	}
+
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return
 	}
 
-	return &ResourceStatistics{
-		Min:    min,
-		Max:    max,
-		Avg:    avg,
-		Median: median,
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return
 	}
+
+	timeline := domain.BuildCapacityTimeline(computes, services, assignments, reservations, basis, from, to, c.Query("group_by"))
+
+	c.JSON(http.StatusOK, timeline)
 }
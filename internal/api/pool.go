@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func (s *Server) listPools(c *gin.Context) {
+	filters := storage.PoolFilters{
+		ComputeID: c.Query("compute_id"),
+	}
+
+	pools, err := s.store.Pools().List(c.Request.Context(), filters)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list pools", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pools)
+}
+
+func (s *Server) getPool(c *gin.Context) {
+	id := c.Param("id")
+
+	pool, err := s.store.Pools().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "pool not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pool)
+}
+
+func (s *Server) createPool(c *gin.Context) {
+	var pool domain.Pool
+
+	if err := c.ShouldBindJSON(&pool); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Check if a pool with the same name already exists on the compute (upsert)
+	existing, err := s.store.Pools().GetByName(c.Request.Context(), pool.ComputeID, pool.Name)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check existing pool", err)
+		return
+	}
+
+	now := time.Now()
+	if existing != nil {
+		pool.ID = existing.ID
+		pool.CreatedAt = existing.CreatedAt
+	} else {
+		if pool.ID == "" {
+			pool.ID = uuid.New().String()
+		}
+		pool.CreatedAt = now
+	}
+	pool.UpdatedAt = now
+
+	if !s.preparePool(c, &pool) {
+		return
+	}
+
+	if existing != nil {
+		if err := s.store.Pools().Update(c.Request.Context(), &pool); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to update pool", err)
+			return
+		}
+		c.JSON(http.StatusOK, pool)
+	} else {
+		if err := s.store.Pools().Create(c.Request.Context(), &pool); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to create pool", err)
+			return
+		}
+		c.JSON(http.StatusCreated, pool)
+	}
+}
+
+func (s *Server) updatePool(c *gin.Context) {
+	id := c.Param("id")
+
+	existing, err := s.store.Pools().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "pool not found", err)
+		return
+	}
+
+	var pool domain.Pool
+	if err := c.ShouldBindJSON(&pool); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if pool.Name != existing.Name || pool.ComputeID != existing.ComputeID {
+		conflict, err := s.store.Pools().GetByName(c.Request.Context(), pool.ComputeID, pool.Name)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check uniqueness", err)
+			return
+		}
+		if conflict != nil {
+			handleError(c, http.StatusConflict, "pool with this name already exists on the compute", nil)
+			return
+		}
+	}
+
+	pool.ID = existing.ID
+	pool.CreatedAt = existing.CreatedAt
+	pool.UpdatedAt = time.Now()
+
+	if !s.preparePool(c, &pool) {
+		return
+	}
+
+	if err := s.store.Pools().Update(c.Request.Context(), &pool); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to update pool", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pool)
+}
+
+func (s *Server) deletePool(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	volumes, err := s.store.Volumes().List(ctx, storage.VolumeFilters{PoolID: id})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load volumes", err)
+		return
+	}
+	if len(volumes) > 0 {
+		handleError(c, http.StatusConflict, "pool still has volumes, delete them first", nil)
+		return
+	}
+
+	if err := s.store.Pools().Delete(ctx, id); err != nil {
+		handleError(c, http.StatusNotFound, "pool not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "pool deleted successfully"})
+}
+
+// getComputePools returns the capacity and free space of the pools of a compute
+func (s *Server) getComputePools(c *gin.Context) {
+	compute, err := s.store.Computes().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, http.StatusNotFound, "compute not found", err)
+		return
+	}
+
+	usages, err := s.poolUsages(c.Request.Context(), compute)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load pools", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usages)
+}
+
+// preparePool validates a pool and checks that its RAID groups exist on the compute and belong
+// to no other pool, writing the error response on failure
+func (s *Server) preparePool(c *gin.Context, pool *domain.Pool) bool {
+	ctx := c.Request.Context()
+
+	if err := pool.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid pool", err)
+		return false
+	}
+
+	compute, err := s.store.Computes().Get(ctx, pool.ComputeID)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "compute not found", err)
+		return false
+	}
+
+	components, assignments, rules, err := s.computeComponents(ctx, compute.ID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute components", err)
+		return false
+	}
+
+	pools, err := s.store.Pools().List(ctx, storage.PoolFilters{ComputeID: compute.ID})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load pools", err)
+		return false
+	}
+
+	layouts := compute.GetStorageLayoutFromComponents(components, assignments, rules)
+	if err := domain.CheckPool(pool, layouts, pools); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid pool", err)
+		return false
+	}
+
+	return true
+}
+
+// poolUsages builds the usage of the pools of a compute from its storage layout and volumes
+func (s *Server) poolUsages(ctx context.Context, compute *domain.Compute) ([]domain.PoolUsage, error) {
+	pools, err := s.store.Pools().List(ctx, storage.PoolFilters{ComputeID: compute.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]domain.PoolUsage, 0, len(pools))
+	if len(pools) == 0 {
+		return usages, nil
+	}
+
+	components, assignments, rules, err := s.computeComponents(ctx, compute.ID)
+	if err != nil {
+		return nil, err
+	}
+	layouts := compute.GetStorageLayoutFromComponents(components, assignments, rules)
+
+	for _, pool := range pools {
+		volumes, err := s.store.Volumes().List(ctx, storage.VolumeFilters{PoolID: pool.ID})
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, domain.BuildPoolUsage(pool, layouts, volumes))
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+// rebalance returns rebalancing proposals over the current assignments
+func (s *Server) rebalance(c *gin.Context) {
+	plan, ok := s.loadRebalancePlan(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// applyRebalance applies one proposal as a single transaction
+func (s *Server) applyRebalance(c *gin.Context) {
+	number, err := strconv.Atoi(c.Query("proposal"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid proposal number", err)
+		return
+	}
+
+	plan, ok := s.loadRebalancePlan(c)
+	if !ok {
+		return
+	}
+
+	if number < 1 || number > len(plan.Proposals) {
+		handleError(c, http.StatusNotFound, fmt.Sprintf("proposal %d not found, %d proposal(s) available", number, len(plan.Proposals)), nil)
+		return
+	}
+	proposal := plan.Proposals[number-1]
+
+	createdBy := ""
+	if apiKey := GetAPIKey(c); apiKey != nil {
+		createdBy = apiKey.Name
+	}
+
+	err = s.store.WithTx(c.Request.Context(), func(tx storage.Storage) error {
+		warnings, err := applyMoves(c.Request.Context(), tx, proposal.Moves)
+		if err != nil {
+			return err
+		}
+		plan.Warnings = warnings
+
+		// One journal entry per source compute
+		sources := make([]*domain.Compute, 0)
+		seen := make(map[string]bool)
+		for _, move := range proposal.Moves {
+			if !seen[move.From.ID] {
+				seen[move.From.ID] = true
+				sources = append(sources, move.From)
+			}
+		}
+		for _, source := range sources {
+			err := tx.Journal().Create(c.Request.Context(), &domain.JournalEntry{
+				ID:        uuid.New().String(),
+				ComputeID: source.ID,
+				Category:  domain.JournalCategoryMaintenance,
+				Content:   rebalanceJournalContent(plan.Goal, proposal, source),
+				CreatedBy: createdBy,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to apply rebalance proposal", err)
+		return
+	}
+
+	plan.Applied = number
+	c.JSON(http.StatusOK, plan)
+}
+
+// loadRebalancePlan runs the rebalance analysis from the request body, writing the error response on failure
+func (s *Server) loadRebalancePlan(c *gin.Context) (*domain.RebalancePlan, bool) {
+	var request domain.RebalanceRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return nil, false
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return nil, false
+	}
+
+	planner, _, err := s.loadPlanner(c.Request.Context(), basis, time.Now())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return nil, false
+	}
+
+	plan, err := planner.Rebalance(request)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid rebalance request", err)
+		return nil, false
+	}
+
+	return plan, true
+}
+
+// rebalanceJournalContent describes the moves off one compute as markdown
+func rebalanceJournalContent(goal string, proposal domain.RebalanceProposal, source *domain.Compute) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Rebalance (%s): %s\n\n", goal, proposal.Description)
+	for _, move := range proposal.Moves {
+		if move.From.ID == source.ID {
+			fmt.Fprintf(&b, "- %s: %d instance(s) moved to %s\n", move.ServiceName, move.Quantity, move.To.Name)
+		}
+	}
+
+	return b.String()
+}
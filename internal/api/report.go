@@ -14,12 +14,18 @@ type ComputeReportResponse struct {
 	ServiceAssignments  interface{} `json:"service_assignments"`
 	IPAssignments       interface{} `json:"ip_assignments"`
 	JournalEntries      interface{} `json:"journal_entries"`
-	Statistics          *ResourceStatistics `json:"statistics,omitempty"`
+	Statistics          *domain.ResourceStatistics `json:"statistics,omitempty"`
+	Reservation         domain.ReservationBasis    `json:"reservation"` // Basis of the statistics
 }
 
 func (s *Server) getComputeReport(c *gin.Context) {
 	computeID := c.Param("id")
 
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
 	// Get compute
 	compute, err := s.store.Computes().Get(c.Request.Context(), computeID)
 	if err != nil {
@@ -66,7 +72,7 @@ func (s *Server) getComputeReport(c *gin.Context) {
 	}
 
 	// Calculate statistics for this compute's assignments
-	stats := calculateResourceStatistics(serviceAssignments, servicesMap)
+	stats := domain.CalculateResourceStatistics(serviceAssignments, servicesMap, basis)
 
 	report := ComputeReportResponse{
 		Compute:             compute,
@@ -75,6 +81,7 @@ func (s *Server) getComputeReport(c *gin.Context) {
 		IPAssignments:       ipAssignments,
 		JournalEntries:      journalEntries,
 		Statistics:          stats,
+		Reservation:         basis,
 	}
 
 	c.JSON(http.StatusOK, report)
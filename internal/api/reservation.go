@@ -0,0 +1,507 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func (s *Server) listReservations(c *gin.Context) {
+	filters := storage.ReservationFilters{
+		Owner:     c.Query("owner"),
+		ComputeID: c.Query("compute_id"),
+	}
+
+	reservations, err := s.store.Reservations().List(c.Request.Context(), filters)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list reservations", err)
+		return
+	}
+
+	status := domain.ReservationStatus(c.Query("status"))
+	now := time.Now()
+
+	filtered := make([]*domain.Reservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		reservation.Status = reservation.StatusAt(now)
+		if status != "" && reservation.Status != status {
+			continue
+		}
+		filtered = append(filtered, reservation)
+	}
+
+	c.JSON(http.StatusOK, filtered)
+}
+
+func (s *Server) getReservation(c *gin.Context) {
+	id := c.Param("id")
+
+	reservation, err := s.store.Reservations().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "reservation not found", err)
+		return
+	}
+	reservation.Status = reservation.StatusAt(time.Now())
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (s *Server) createReservation(c *gin.Context) {
+	var reservation domain.Reservation
+
+	if err := c.ShouldBindJSON(&reservation); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if !s.prepareReservation(c, &reservation) {
+		return
+	}
+
+	// Check if reservation with same name already exists (upsert)
+	existing, err := s.store.Reservations().GetByName(c.Request.Context(), reservation.Name)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check existing reservation", err)
+		return
+	}
+
+	now := time.Now()
+	if existing != nil {
+		reservation.ID = existing.ID
+		reservation.CreatedAt = existing.CreatedAt
+	} else {
+		if reservation.ID == "" {
+			reservation.ID = uuid.New().String()
+		}
+		reservation.CreatedAt = now
+	}
+	reservation.UpdatedAt = now
+
+	if !s.checkReservationCapacity(c, &reservation) {
+		return
+	}
+
+	if existing != nil {
+		if err := s.store.Reservations().Update(c.Request.Context(), &reservation); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to update reservation", err)
+			return
+		}
+		reservation.Status = reservation.StatusAt(now)
+		c.JSON(http.StatusOK, reservation)
+	} else {
+		if err := s.store.Reservations().Create(c.Request.Context(), &reservation); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to create reservation", err)
+			return
+		}
+		reservation.Status = reservation.StatusAt(now)
+		c.JSON(http.StatusCreated, reservation)
+	}
+}
+
+func (s *Server) updateReservation(c *gin.Context) {
+	id := c.Param("id")
+
+	existing, err := s.store.Reservations().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "reservation not found", err)
+		return
+	}
+
+	var reservation domain.Reservation
+	if err := c.ShouldBindJSON(&reservation); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if !s.prepareReservation(c, &reservation) {
+		return
+	}
+
+	if reservation.Name != existing.Name {
+		conflict, err := s.store.Reservations().GetByName(c.Request.Context(), reservation.Name)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check uniqueness", err)
+			return
+		}
+		if conflict != nil {
+			handleError(c, http.StatusConflict, "reservation with this name already exists", nil)
+			return
+		}
+	}
+
+	reservation.ID = existing.ID
+	reservation.CreatedAt = existing.CreatedAt
+	reservation.UpdatedAt = time.Now()
+
+	if !s.checkReservationCapacity(c, &reservation) {
+		return
+	}
+
+	if err := s.store.Reservations().Update(c.Request.Context(), &reservation); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to update reservation", err)
+		return
+	}
+	reservation.Status = reservation.StatusAt(reservation.UpdatedAt)
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (s *Server) deleteReservation(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := s.store.Reservations().Delete(c.Request.Context(), id); err != nil {
+		handleError(c, http.StatusNotFound, "reservation not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reservation deleted successfully"})
+}
+
+// convertReservation places instances of a service in the capacity held by a reservation,
+// creates their assignments and takes their reserved resources out of the reservation, in one
+// transaction. A reservation with nothing left is deleted.
+func (s *Server) convertReservation(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	reservation, err := s.store.Reservations().Get(ctx, id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "reservation not found", err)
+		return
+	}
+
+	now := time.Now()
+	if reservation.StatusAt(now) == domain.ReservationExpired {
+		handleError(c, http.StatusConflict, "reservation has expired", nil)
+		return
+	}
+
+	var conversion domain.ReservationConversion
+	if err := c.ShouldBindJSON(&conversion); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	if conversion.Replicas <= 0 {
+		conversion.Replicas = 1
+	}
+
+	if _, err := domain.GetScoringStrategy(conversion.Strategy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid strategy", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	service, err := s.store.Services().Get(ctx, conversion.ServiceID)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "service not found", err)
+		return
+	}
+
+	consumed, remaining, err := reservation.Consume(basis.Spec(service).Scale(float64(conversion.Replicas)))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "service does not fit in the reservation", err)
+		return
+	}
+
+	planner, assignments, err := s.loadPlanner(ctx, basis, now)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return
+	}
+
+	// Release the capacity held by this reservation so the instances can take it
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return
+	}
+	others := make([]*domain.Reservation, 0, len(reservations))
+	for _, other := range reservations {
+		if other.ID != reservation.ID {
+			others = append(others, other)
+		}
+	}
+	planner.HoldReservations(others, now)
+
+	plan, err := planner.Plan(reservation.PlanRequest(conversion))
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to plan capacity", err)
+		return
+	}
+	if !plan.Feasible {
+		handleError(c, http.StatusConflict, fmt.Sprintf("instances do not fit in the reserved capacity: %s", plan.Message), nil)
+		return
+	}
+
+	planned := plan.PlannedAssignments(service.ID, assignments)
+
+	err = s.store.WithTx(ctx, func(tx storage.Storage) error {
+		for _, assignment := range planned {
+			if assignment.ID != "" {
+				if err := tx.Assignments().Update(ctx, assignment); err != nil {
+					return err
+				}
+				continue
+			}
+			assignment.ID = uuid.New().String()
+			if err := tx.Assignments().Create(ctx, assignment); err != nil {
+				return err
+			}
+		}
+
+		if len(remaining) == 0 {
+			return tx.Reservations().Delete(ctx, reservation.ID)
+		}
+		reservation.Resources = remaining
+		reservation.UpdatedAt = now
+		return tx.Reservations().Update(ctx, reservation)
+	})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to convert reservation", err)
+		return
+	}
+
+	result := domain.ConversionResult{
+		Plan:        plan,
+		Assignments: planned,
+		Consumed:    consumed,
+	}
+	if len(remaining) > 0 {
+		reservation.Status = reservation.StatusAt(now)
+		result.Reservation = reservation
+		result.Message = fmt.Sprintf("%d instance(s) of %s placed, reservation %s keeps %s", conversion.Replicas, service.Name, reservation.Name, formatQuantities(remaining))
+	} else {
+		result.Message = fmt.Sprintf("%d instance(s) of %s placed, reservation %s fully converted and released", conversion.Replicas, service.Name, reservation.Name)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// reservationReport lists the reservations expiring within a window (default 7 days) with
+// where their capacity is held
+func (s *Server) reservationReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	within := 7 * 24 * time.Hour
+	if value := c.Query("within"); value != "" {
+		parsed, err := domain.ParseWindow(value)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid within", err)
+			return
+		}
+		within = parsed
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		return
+	}
+
+	services, err := s.store.Services().List(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+		return
+	}
+
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return
+	}
+
+	now := time.Now()
+	assignments = domain.ScheduledAssignments(assignments, domain.Schedule{StartsAt: &now})
+
+	reservations, placements, err := s.holdReservations(ctx, computes, services, assignments, basis, now)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return
+	}
+
+	for _, reservation := range reservations {
+		reservation.Status = reservation.StatusAt(now)
+	}
+
+	c.JSON(http.StatusOK, domain.ExpiringReservations(reservations, placements, now, within))
+}
+
+// prepareReservation normalizes the resources of a reservation, defaults its start to now and
+// validates it, writing the error response on failure
+func (s *Server) prepareReservation(c *gin.Context, reservation *domain.Reservation) bool {
+	registry, err := s.resourceRegistry(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+		return false
+	}
+	resources, err := registry.Normalize(reservation.Resources)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid resources", err)
+		return false
+	}
+	reservation.Resources = resources
+
+	if reservation.StartsAt.IsZero() {
+		reservation.StartsAt = time.Now()
+	}
+	reservation.Status = ""
+
+	if err := reservation.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid reservation", err)
+		return false
+	}
+
+	if reservation.ComputeID != "" {
+		if _, err := s.store.Computes().Get(c.Request.Context(), reservation.ComputeID); err != nil {
+			handleError(c, http.StatusBadRequest, "compute not found", err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkReservationCapacity verifies that the capacity of the reservation can be held next to
+// the other reservations, unless force=true, writing the error response on failure
+func (s *Server) checkReservationCapacity(c *gin.Context, reservation *domain.Reservation) bool {
+	if c.Query("force") == "true" {
+		return true
+	}
+
+	ctx := c.Request.Context()
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return false
+	}
+
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load computes", err)
+		return false
+	}
+
+	services, err := s.store.Services().List(ctx)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load services", err)
+		return false
+	}
+
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load assignments", err)
+		return false
+	}
+
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load reservations", err)
+		return false
+	}
+	candidates := make([]*domain.Reservation, 0, len(reservations)+1)
+	for _, other := range reservations {
+		if other.ID != reservation.ID {
+			candidates = append(candidates, other)
+		}
+	}
+	candidates = append(candidates, reservation)
+
+	servicesMap := make(map[string]*domain.Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+
+	// Assignments running while the reservation holds its capacity compete with it
+	now := time.Now()
+	assignments = domain.ScheduledAssignments(assignments, domain.Schedule{StartsAt: &now, EndsAt: &reservation.ExpiresAt})
+
+	placements := domain.HoldReservations(computes, candidates, assignments, servicesMap, basis, now)
+	if placement, ok := placements[reservation.ID]; ok && len(placement.Shortfall) > 0 {
+		handleError(c, http.StatusConflict, fmt.Sprintf("insufficient capacity to hold reservation, short of %s (use force=true to hold what is available)", formatQuantities(placement.Shortfall)), nil)
+		return false
+	}
+
+	return true
+}
+
+// holdReservations holds the capacity of the reservations not expired at the instant on the
+// computes, returning the reservations and their placements
+func (s *Server) holdReservations(ctx context.Context, computes []*domain.Compute, services []*domain.Service, assignments []*domain.Assignment, basis domain.ReservationBasis, at time.Time) ([]*domain.Reservation, map[string]*domain.ReservationPlacement, error) {
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	servicesMap := make(map[string]*domain.Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+
+	placements := domain.HoldReservations(computes, reservations, assignments, servicesMap, basis, at)
+	return reservations, placements, nil
+}
+
+// heldOn returns the resources reservations not expired at the instant hold on one compute.
+// Selector reservations spread over several computes, so every compute is loaded when a
+// reservation is holding.
+func (s *Server) heldOn(ctx context.Context, computeID string, services []*domain.Service, assignments []*domain.Assignment, basis domain.ReservationBasis, at time.Time) (domain.Resources, error) {
+	reservations, err := s.store.Reservations().List(ctx, storage.ReservationFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := at
+	holding := false
+	for _, reservation := range reservations {
+		if reservation.StatusAt(now) != domain.ReservationExpired {
+			holding = true
+			break
+		}
+	}
+	if !holding {
+		return nil, nil
+	}
+
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	servicesMap := make(map[string]*domain.Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+
+	domain.HoldReservations(computes, reservations, assignments, servicesMap, basis, now)
+	for _, compute := range computes {
+		if compute.ID == computeID {
+			return compute.Held, nil
+		}
+	}
+	return nil, nil
+}
+
+// formatQuantities formats resources as key=value pairs in key order
+func formatQuantities(resources domain.Resources) string {
+	parts := make([]string, 0, len(resources))
+	for _, key := range resources.Keys() {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, resources[key]))
+	}
+	return strings.Join(parts, ", ")
+}
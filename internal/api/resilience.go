@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+// resilienceReport simulates the loss of each failure domain and reports stranded services.
+// GET reads topology_key and strategy from the query, POST reads a resilience request body
+// that may include a what-if scenario.
+func (s *Server) resilienceReport(c *gin.Context) {
+	request := domain.ResilienceRequest{
+		TopologyKey: c.Query("topology_key"),
+		Strategy:    c.Query("strategy"),
+	}
+	if c.Request.Method == http.MethodPost {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid request body", err)
+			return
+		}
+	}
+
+	if _, err := domain.GetScoringStrategy(request.Strategy); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid strategy", err)
+		return
+	}
+
+	basis, ok := s.reservationBasis(c)
+	if !ok {
+		return
+	}
+
+	planner, _, err := s.loadPlanner(c.Request.Context(), basis, time.Now())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load planning data", err)
+		return
+	}
+
+	if !request.WhatIf.IsEmpty() {
+		if _, err := planner.WithWhatIf(request.WhatIf); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid what-if scenario", err)
+			return
+		}
+	}
+
+	report, err := planner.AnalyzeResilience(request)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to analyze resilience", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+// listResources lists the known resource keys with their units and aliases
+func (s *Server) listResources(c *gin.Context) {
+	registry, err := s.resourceRegistry(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, registry.Definitions())
+}
+
+// resourceRegistry builds the registry of known resource keys: the built-in keys and the
+// targets of the derivation rules
+func (s *Server) resourceRegistry(ctx context.Context) (*domain.ResourceRegistry, error) {
+	rules, err := s.store.DerivationRules().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load derivation rules: %w", err)
+	}
+
+	return domain.NewResourceRegistry(domain.ResourceDefinitions(rules)...)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/studiowebux/kubebuddy/internal/domain"
 	"github.com/studiowebux/kubebuddy/internal/storage"
 )
 
@@ -14,6 +15,9 @@ type Server struct {
 	store  storage.Storage
 	router *gin.Engine
 	addr   string
+
+	// Default reservation basis, overridden per request with the reservation query parameter
+	reservation domain.ReservationBasis
 }
 
 // NewServer creates a new API server
@@ -26,9 +30,10 @@ func NewServer(store storage.Storage, addr string) *Server {
 	router.Use(CORSMiddleware())
 
 	s := &Server{
-		store:  store,
-		router: router,
-		addr:   addr,
+		store:       store,
+		router:      router,
+		addr:        addr,
+		reservation: domain.DefaultReservation,
 	}
 
 	s.setupRoutes()
@@ -36,6 +41,11 @@ func NewServer(store storage.Storage, addr string) *Server {
 	return s
 }
 
+// SetReservation sets the default reservation basis used for planning, admission and reports
+func (s *Server) SetReservation(basis domain.ReservationBasis) {
+	s.reservation = basis
+}
+
 // setupRoutes configures all API routes
 func (s *Server) setupRoutes() {
 	// Health check (no auth required)
@@ -52,9 +62,14 @@ func (s *Server) setupRoutes() {
 	{
 		computes.GET("", s.listComputes)
 		computes.GET("/:id", s.getCompute)
+		computes.GET("/:id/hardware", s.getComputeHardware)
+		computes.GET("/:id/storage", s.getComputeStorage)
+		computes.GET("/:id/pools", s.getComputePools)
 		computes.POST("", RequireWrite(), s.createCompute)
 		computes.PUT("/:id", RequireWrite(), s.updateCompute)
 		computes.DELETE("/:id", RequireWrite(), s.deleteCompute)
+		computes.GET("/:id/drain", s.planDrain)
+		computes.POST("/:id/drain", RequireWrite(), s.executeDrain)
 	}
 
 	// Service routes
@@ -67,6 +82,9 @@ func (s *Server) setupRoutes() {
 		services.DELETE("/:id", RequireWrite(), s.deleteService)
 	}
 
+	// Resource key registry
+	api.GET("/resources", s.listResources)
+
 	// Assignment routes
 	assignments := api.Group("/assignments")
 	{
@@ -80,13 +98,28 @@ func (s *Server) setupRoutes() {
 	capacity := api.Group("/capacity")
 	{
 		capacity.POST("/plan", s.planCapacity)
+		capacity.POST("/plan/apply", RequireWrite(), s.applyPlan)
+		capacity.POST("/plan-stack", s.planStack)
+		capacity.POST("/plan-stack/apply", RequireWrite(), s.applyStack)
+		capacity.POST("/rebalance", s.rebalance)
+		capacity.POST("/rebalance/apply", RequireWrite(), s.applyRebalance)
 		capacity.GET("/report", s.capacityReport)
+		capacity.POST("/report", s.capacityReport)
+		capacity.GET("/resilience", s.resilienceReport)
+		capacity.POST("/resilience", s.resilienceReport)
+		capacity.GET("/snapshots", s.listSnapshots)
+		capacity.GET("/snapshots/:id", s.getSnapshot)
+		capacity.POST("/snapshots", RequireWrite(), s.takeSnapshot)
+		capacity.GET("/history", s.capacityHistory)
+		capacity.GET("/forecast", s.capacityForecast)
+		capacity.GET("/timeline", s.capacityTimeline)
 	}
 
 	// Report routes
 	reports := api.Group("/reports")
 	{
 		reports.GET("/compute/:id", s.getComputeReport)
+		reports.GET("/reservations", s.reservationReport)
 	}
 
 	// Journal routes
@@ -173,6 +206,57 @@ func (s *Server) setupRoutes() {
 		firewallAssignments.PATCH("/:id/enabled", RequireWrite(), s.updateFirewallRuleEnabled)
 	}
 
+	// Overcommit policy routes
+	overcommitPolicies := api.Group("/overcommit-policies")
+	{
+		overcommitPolicies.GET("", s.listOvercommitPolicies)
+		overcommitPolicies.GET("/:id", s.getOvercommitPolicy)
+		overcommitPolicies.POST("", RequireWrite(), s.createOvercommitPolicy)
+		overcommitPolicies.PUT("/:id", RequireWrite(), s.updateOvercommitPolicy)
+		overcommitPolicies.DELETE("/:id", RequireWrite(), s.deleteOvercommitPolicy)
+	}
+
+	// Derivation rule routes
+	derivationRules := api.Group("/derivation-rules")
+	{
+		derivationRules.GET("", s.listDerivationRules)
+		derivationRules.GET("/:id", s.getDerivationRule)
+		derivationRules.POST("", RequireWrite(), s.createDerivationRule)
+		derivationRules.PUT("/:id", RequireWrite(), s.updateDerivationRule)
+		derivationRules.DELETE("/:id", RequireWrite(), s.deleteDerivationRule)
+	}
+
+	// Reservation routes
+	reservations := api.Group("/reservations")
+	{
+		reservations.GET("", s.listReservations)
+		reservations.GET("/:id", s.getReservation)
+		reservations.POST("", RequireWrite(), s.createReservation)
+		reservations.PUT("/:id", RequireWrite(), s.updateReservation)
+		reservations.DELETE("/:id", RequireWrite(), s.deleteReservation)
+		reservations.POST("/:id/convert", RequireWrite(), s.convertReservation)
+	}
+
+	// Storage pool routes
+	pools := api.Group("/pools")
+	{
+		pools.GET("", s.listPools)
+		pools.GET("/:id", s.getPool)
+		pools.POST("", RequireWrite(), s.createPool)
+		pools.PUT("/:id", RequireWrite(), s.updatePool)
+		pools.DELETE("/:id", RequireWrite(), s.deletePool)
+	}
+
+	// Volume routes
+	volumes := api.Group("/volumes")
+	{
+		volumes.GET("", s.listVolumes)
+		volumes.GET("/:id", s.getVolume)
+		volumes.POST("", RequireWrite(), s.createVolume)
+		volumes.PUT("/:id", RequireWrite(), s.updateVolume)
+		volumes.DELETE("/:id", RequireWrite(), s.deleteVolume)
+	}
+
 	// Admin routes (API key management)
 	admin := api.Group("/admin")
 	admin.Use(RequireAdmin())
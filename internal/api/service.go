@@ -46,6 +46,22 @@ func (s *Server) createService(c *gin.Context) {
 		service.MaxSpec = make(domain.Resources)
 	}
 
+	if err := service.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid service", err)
+		return
+	}
+
+	registry, err := s.resourceRegistry(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+		return
+	}
+
+	if err := service.NormalizeResources(registry); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid service", err)
+		return
+	}
+
 	// Check if service with same name already exists (upsert)
 	existing, err := s.store.Services().GetByName(c.Request.Context(), service.Name)
 	if err != nil {
@@ -95,6 +111,22 @@ func (s *Server) updateService(c *gin.Context) {
 		return
 	}
 
+	if err := service.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid service", err)
+		return
+	}
+
+	registry, err := s.resourceRegistry(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+		return
+	}
+
+	if err := service.NormalizeResources(registry); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid service", err)
+		return
+	}
+
 	// Check for name conflict if name is being changed
 	if service.Name != existing.Name {
 		conflict, err := s.store.Services().GetByName(c.Request.Context(), service.Name)
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+// StartSnapshots takes a capacity snapshot right away and then every interval until ctx is
+// done, deleting the snapshots older than retention (0 keeps them all)
+func (s *Server) StartSnapshots(ctx context.Context, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.TakeSnapshot(ctx); err != nil {
+				fmt.Printf("Failed to take capacity snapshot: %v\n", err)
+			}
+			if retention > 0 {
+				if _, err := s.store.Snapshots().DeleteBefore(ctx, time.Now().Add(-retention)); err != nil {
+					fmt.Printf("Failed to prune capacity snapshots: %v\n", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// TakeSnapshot persists the utilization of every compute under the default reservation basis
+func (s *Server) TakeSnapshot(ctx context.Context) (*domain.CapacitySnapshot, error) {
+	now := time.Now().UTC()
+	report, err := s.capacityReportAt(ctx, s.reservation, now)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := domain.NewCapacitySnapshot(report, now)
+	snapshot.ID = uuid.New().String()
+
+	err = s.store.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.Snapshots().Create(ctx, snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// capacityReportAt builds the capacity report of the stored infrastructure at an instant, with
+// the assignments running and the capacity held by reservations at that instant
+func (s *Server) capacityReportAt(ctx context.Context, basis domain.ReservationBasis, at time.Time) (*domain.CapacityReport, error) {
+	computes, err := s.loadComputes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load computes: %w", err)
+	}
+
+	services, err := s.store.Services().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load services: %w", err)
+	}
+
+	assignments, err := s.store.Assignments().List(ctx, storage.AssignmentFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assignments: %w", err)
+	}
+	assignments = domain.ActiveAssignments(assignments, at)
+
+	if _, _, err := s.holdReservations(ctx, computes, services, assignments, basis, at); err != nil {
+		return nil, fmt.Errorf("failed to load reservations: %w", err)
+	}
+
+	return domain.BuildCapacityReport(computes, services, assignments, basis), nil
+}
+
+func (s *Server) takeSnapshot(c *gin.Context) {
+	snapshot, err := s.TakeSnapshot(c.Request.Context())
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to take snapshot", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+func (s *Server) listSnapshots(c *gin.Context) {
+	filters, ok := snapshotFilters(c)
+	if !ok {
+		return
+	}
+	filters.ComputeID = c.Query("compute_id")
+	filters.Samples = c.Query("samples") == "true"
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filters.Limit = limit
+		}
+	}
+
+	snapshots, err := s.store.Snapshots().List(c.Request.Context(), filters)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list snapshots", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+func (s *Server) getSnapshot(c *gin.Context) {
+	snapshot, err := s.store.Snapshots().Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, http.StatusNotFound, "snapshot not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+func (s *Server) capacityHistory(c *gin.Context) {
+	history, ok := s.loadCapacityHistory(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (s *Server) capacityForecast(c *gin.Context) {
+	request := domain.ForecastRequest{
+		GroupBy:  c.Query("group_by"),
+		Resource: c.Query("resource"),
+		Method:   domain.ForecastMethod(c.Query("method")),
+	}
+
+	if thresholdStr := c.Query("threshold"); thresholdStr != "" {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid threshold", err)
+			return
+		}
+		request.Threshold = threshold
+	}
+
+	if seasonStr := c.Query("season"); seasonStr != "" {
+		season, err := strconv.Atoi(seasonStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid season", err)
+			return
+		}
+		request.SeasonLength = season
+	}
+
+	if horizonStr := c.Query("horizon"); horizonStr != "" {
+		horizon, err := domain.ParseWindow(horizonStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid horizon", err)
+			return
+		}
+		request.Horizon = horizon
+	}
+
+	if err := request.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid forecast request", err)
+		return
+	}
+
+	if request.Resource != "" {
+		registry, err := s.resourceRegistry(c.Request.Context())
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to load resource keys", err)
+			return
+		}
+		resource, ok := registry.Resolve(request.Resource)
+		if !ok {
+			handleError(c, http.StatusBadRequest, fmt.Sprintf("unknown resource key %q", request.Resource), nil)
+			return
+		}
+		request.Resource = resource
+	}
+
+	history, ok := s.loadCapacityHistory(c)
+	if !ok {
+		return
+	}
+
+	forecast, err := domain.ForecastCapacity(history, request)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid forecast request", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// loadCapacityHistory groups the snapshots between the from and to query parameters by the
+// group_by query parameter
+func (s *Server) loadCapacityHistory(c *gin.Context) (*domain.CapacityHistory, bool) {
+	filters, ok := snapshotFilters(c)
+	if !ok {
+		return nil, false
+	}
+	filters.Samples = true
+
+	snapshots, err := s.store.Snapshots().List(c.Request.Context(), filters)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list snapshots", err)
+		return nil, false
+	}
+
+	return domain.BuildCapacityHistory(snapshots, c.Query("group_by")), true
+}
+
+// snapshotFilters parses the from and to query parameters (RFC3339)
+func snapshotFilters(c *gin.Context) (storage.SnapshotFilters, bool) {
+	var filters storage.SnapshotFilters
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid from time", err)
+			return filters, false
+		}
+		filters.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "invalid to time", err)
+			return filters, false
+		}
+		filters.To = &to
+	}
+
+	return filters, true
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func (s *Server) listVolumes(c *gin.Context) {
+	filters := storage.VolumeFilters{
+		PoolID:    c.Query("pool_id"),
+		ServiceID: c.Query("service_id"),
+	}
+
+	volumes, err := s.store.Volumes().List(c.Request.Context(), filters)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to list volumes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volumes)
+}
+
+func (s *Server) getVolume(c *gin.Context) {
+	id := c.Param("id")
+
+	volume, err := s.store.Volumes().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "volume not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volume)
+}
+
+func (s *Server) createVolume(c *gin.Context) {
+	var volume domain.Volume
+
+	if err := c.ShouldBindJSON(&volume); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Check if a volume with the same name already exists in the pool (upsert)
+	existing, err := s.store.Volumes().GetByName(c.Request.Context(), volume.PoolID, volume.Name)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to check existing volume", err)
+		return
+	}
+
+	now := time.Now()
+	if existing != nil {
+		volume.ID = existing.ID
+		volume.CreatedAt = existing.CreatedAt
+	} else {
+		if volume.ID == "" {
+			volume.ID = uuid.New().String()
+		}
+		volume.CreatedAt = now
+	}
+	volume.UpdatedAt = now
+
+	if !s.prepareVolume(c, &volume) {
+		return
+	}
+
+	if existing != nil {
+		if err := s.store.Volumes().Update(c.Request.Context(), &volume); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to update volume", err)
+			return
+		}
+		c.JSON(http.StatusOK, volume)
+	} else {
+		if err := s.store.Volumes().Create(c.Request.Context(), &volume); err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to create volume", err)
+			return
+		}
+		c.JSON(http.StatusCreated, volume)
+	}
+}
+
+func (s *Server) updateVolume(c *gin.Context) {
+	id := c.Param("id")
+
+	existing, err := s.store.Volumes().Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, http.StatusNotFound, "volume not found", err)
+		return
+	}
+
+	var volume domain.Volume
+	if err := c.ShouldBindJSON(&volume); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if volume.Name != existing.Name || volume.PoolID != existing.PoolID {
+		conflict, err := s.store.Volumes().GetByName(c.Request.Context(), volume.PoolID, volume.Name)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, "failed to check uniqueness", err)
+			return
+		}
+		if conflict != nil {
+			handleError(c, http.StatusConflict, "volume with this name already exists in the pool", nil)
+			return
+		}
+	}
+
+	volume.ID = existing.ID
+	volume.CreatedAt = existing.CreatedAt
+	volume.UpdatedAt = time.Now()
+
+	if !s.prepareVolume(c, &volume) {
+		return
+	}
+
+	if err := s.store.Volumes().Update(c.Request.Context(), &volume); err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to update volume", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volume)
+}
+
+func (s *Server) deleteVolume(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := s.store.Volumes().Delete(c.Request.Context(), id); err != nil {
+		handleError(c, http.StatusNotFound, "volume not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "volume deleted successfully"})
+}
+
+// prepareVolume validates a volume, checks that its service has the storage requirement it
+// provides and, unless force=true, that it fits in the free space of its pool, writing the error
+// response on failure
+func (s *Server) prepareVolume(c *gin.Context, volume *domain.Volume) bool {
+	ctx := c.Request.Context()
+
+	if err := volume.Validate(); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid volume", err)
+		return false
+	}
+
+	pool, err := s.store.Pools().Get(ctx, volume.PoolID)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "pool not found", err)
+		return false
+	}
+
+	if volume.ServiceID != "" {
+		service, err := s.store.Services().Get(ctx, volume.ServiceID)
+		if err != nil {
+			handleError(c, http.StatusBadRequest, "service not found", err)
+			return false
+		}
+		if err := volume.CheckRequirement(service); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid volume", err)
+			return false
+		}
+	}
+
+	if c.Query("force") == "true" {
+		return true
+	}
+
+	compute, err := s.store.Computes().Get(ctx, pool.ComputeID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load compute", err)
+		return false
+	}
+
+	usages, err := s.poolUsages(ctx, compute)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "failed to load pools", err)
+		return false
+	}
+	for _, usage := range usages {
+		if usage.Pool.ID != pool.ID {
+			continue
+		}
+		if err := usage.CheckVolume(volume); err != nil {
+			handleError(c, http.StatusConflict, fmt.Sprintf("insufficient pool space: %s (use force=true to overcommit)", err), nil)
+			return false
+		}
+	}
+
+	return true
+}
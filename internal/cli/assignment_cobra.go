@@ -17,7 +17,11 @@ func newAssignmentCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "assignment",
 		Short: "Manage service assignments",
-		Long:  `Manage service-to-compute assignments`,
+		Long: `Manage service-to-compute assignments
+
+Assignments run indefinitely unless given a start and an end date, to model planned
+deployments and temporary workloads. Capacity checks only count assignments whose schedules
+overlap.`,
 	}
 
 	cmd.AddCommand(newAssignmentListCmd())
@@ -90,19 +94,30 @@ func newAssignmentCreateCmd() *cobra.Command {
 	var (
 		serviceID string
 		computeID string
-		force     bool
-		quantity  int
+		force       bool
+		quantity    int
+		reservation string
+		startsAt    string
+		endsAt      string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new assignment",
+		Example: `  kubebuddy assignment create --service postgres-db --compute server-01
+  kubebuddy assignment create --service batch-job --compute server-01 --starts-at 2026-11-01 --ends-at 2026-11-15`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := requireAPIKey(cmd); err != nil {
 				return err
 			}
 
+			schedule, err := parseSchedule(startsAt, endsAt)
+			if err != nil {
+				return err
+			}
+
 			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
 
 			// Resolve service ID or name
 			service, err := c.ResolveService(context.Background(), serviceID)
@@ -126,6 +141,7 @@ func newAssignmentCreateCmd() *cobra.Command {
 				ServiceID: service.ID,
 				ComputeID: compute.ID,
 				Quantity:  quantity,
+				Schedule:  schedule,
 			}
 
 			result, err := c.CreateAssignment(context.Background(), assignment, force)
@@ -142,6 +158,9 @@ func newAssignmentCreateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&computeID, "compute", "", "Compute ID or name (required)")
 	cmd.Flags().BoolVar(&force, "force", false, "Force assignment even if resources insufficient")
 	cmd.Flags().IntVar(&quantity, "quantity", 1, "Number of service instances (default: 1)")
+	cmd.Flags().StringVar(&startsAt, "starts-at", "", "Start of the assignment (RFC3339 or YYYY-MM-DD, default: already running)")
+	cmd.Flags().StringVar(&endsAt, "ends-at", "", "End of the assignment (RFC3339 or YYYY-MM-DD, default: indefinitely)")
+	addReservationFlag(cmd, &reservation)
 
 	cmd.MarkFlagRequired("service")
 	cmd.MarkFlagRequired("compute")
@@ -221,3 +240,23 @@ func completeAssignmentIDs(toComplete string) []string {
 	})
 	return completions
 }
+
+// parseSchedule parses the optional start and end dates of an assignment or plan
+func parseSchedule(startsAt, endsAt string) (domain.Schedule, error) {
+	var schedule domain.Schedule
+	if startsAt != "" {
+		t, err := parseTime(startsAt)
+		if err != nil {
+			return schedule, fmt.Errorf("invalid start: %w", err)
+		}
+		schedule.StartsAt = &t
+	}
+	if endsAt != "" {
+		t, err := parseTime(endsAt)
+		if err != nil {
+			return schedule, fmt.Errorf("invalid end: %w", err)
+		}
+		schedule.EndsAt = &t
+	}
+	return schedule, schedule.Validate()
+}
@@ -209,12 +209,23 @@ func newComponentAssignCmd() *cobra.Command {
 		notes       string
 		raidLevel   string
 		raidGroup   string
+		raidSpans   int
+		spare       bool
+		force       bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "assign",
 		Short: "Assign a component to one or more computes",
-		Long:  `Assign a component to compute(s). Use comma-separated names/IDs for multiple: --computes server1,server2,server3`,
+		Long: `Assign a component to compute(s). Use comma-separated names/IDs for multiple: --computes server1,server2,server3
+
+Storage components with the same --raid-group form a RAID array. The API rejects arrays that
+cannot be built: too few disks for the level, an odd RAID10, RAID50/RAID60 disks that do not
+divide into spans, mixed storage types or levels, and hot spares on RAID0 or JBOD or smaller
+than the members. Use --force to add the disks of an array one model at a time.`,
+		Example: `  kubebuddy component assign --computes server1 --component "Samsung PM9A3 3.84TB" --quantity 4 --raid 10 --raid-group data
+  kubebuddy component assign --computes server1 --component "Seagate Exos 16TB" --quantity 8 --raid 60 --raid-spans 2 --raid-group archive
+  kubebuddy component assign --computes server1 --component "Seagate Exos 16TB" --raid 60 --raid-group archive --spare`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := requireAPIKey(cmd); err != nil {
 				return err
@@ -236,9 +247,16 @@ func newComponentAssignCmd() *cobra.Command {
 			}
 
 			// Normalize RAID level (accept numeric or string format)
-			normalizedRaid := normalizeRaidLevel(raidLevel)
-			if raidLevel != "" && normalizedRaid == "" {
-				return fmt.Errorf("invalid RAID level: %s (use 0, 1, 5, 6, or 10)", raidLevel)
+			var level domain.RaidLevel
+			if raidLevel != "" {
+				level, err = domain.ParseRaidLevel(raidLevel)
+				if err != nil {
+					return err
+				}
+			}
+			role := domain.RaidRole("")
+			if spare {
+				role = domain.RaidRoleSpare
 			}
 
 			// Track results
@@ -266,12 +284,14 @@ func newComponentAssignCmd() *cobra.Command {
 					Slot:        slot,
 					SerialNo:    serialNo,
 					Notes:       notes,
-					RaidLevel:   domain.RaidLevel(normalizedRaid),
+					RaidLevel:   level,
 					RaidGroup:   raidGroup,
+					RaidRole:    role,
+					RaidSpans:   raidSpans,
 					CreatedAt:   time.Now(),
 				}
 
-				_, err = c.AssignComponent(ctx, assignment)
+				_, err = c.AssignComponent(ctx, assignment, force)
 				if err != nil {
 					errors = append(errors, fmt.Sprintf("%s: %v", compute.Name, err))
 				} else {
@@ -300,8 +320,11 @@ func newComponentAssignCmd() *cobra.Command {
 	cmd.Flags().StringVar(&slot, "slot", "", "Physical slot (e.g., CPU1, DIMM0-3)")
 	cmd.Flags().StringVar(&serialNo, "serial", "", "Serial number")
 	cmd.Flags().StringVar(&notes, "notes", "", "Installation notes (e.g., 'Boot drive', 'Data pool')")
-	cmd.Flags().StringVar(&raidLevel, "raid", "", "RAID level for storage: 0, 1, 5, 6, or 10")
+	cmd.Flags().StringVar(&raidLevel, "raid", "", "RAID level for storage: 0, 1, 5, 6, 10, 50, 60 or jbod")
 	cmd.Flags().StringVar(&raidGroup, "raid-group", "", "RAID group ID (storage components in same group form RAID array)")
+	cmd.Flags().IntVar(&raidSpans, "raid-spans", 0, "Spans of a RAID50 or RAID60 array (default 2)")
+	cmd.Flags().BoolVar(&spare, "spare", false, "Add the disks as hot spares of the RAID group")
+	cmd.Flags().BoolVar(&force, "force", false, "Assign even if the RAID array cannot be built yet")
 
 	cmd.MarkFlagRequired("computes")
 	cmd.MarkFlagRequired("component")
@@ -318,17 +341,22 @@ func newComponentAssignCmd() *cobra.Command {
 }
 
 func newComponentUnassignCmd() *cobra.Command {
+	var force bool
+
 	cmd := &cobra.Command{
 		Use:   "unassign [assignment-id]",
 		Short: "Unassign a component from a compute",
-		Args:  cobra.ExactArgs(1),
+		Long: `Unassign a component from a compute. Removing disks from a RAID group a storage pool
+is built on is rejected when the group can no longer be built or the pool no longer holds its
+volumes; use --force to remove them anyway.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := requireAPIKey(cmd); err != nil {
 				return err
 			}
 
 			c := client.New(endpoint, apiKey)
-			if err := c.UnassignComponent(context.Background(), args[0]); err != nil {
+			if err := c.UnassignComponent(context.Background(), args[0], force); err != nil {
 				return err
 			}
 
@@ -337,6 +365,8 @@ func newComponentUnassignCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&force, "force", false, "Unassign even if a storage pool is built on the component")
+
 	return cmd
 }
 
@@ -422,33 +452,3 @@ func completeComponentIDs(toComplete string) []string {
 
 	return completions
 }
-
-// normalizeRaidLevel converts numeric or string RAID levels to the canonical format
-func normalizeRaidLevel(level string) string {
-	if level == "" {
-		return ""
-	}
-
-	normalized := strings.ToLower(strings.TrimSpace(level))
-
-	// Map both numeric and string formats to canonical format
-	raidMap := map[string]string{
-		"0":      "raid0",
-		"1":      "raid1",
-		"5":      "raid5",
-		"6":      "raid6",
-		"10":     "raid10",
-		"raid0":  "raid0",
-		"raid1":  "raid1",
-		"raid5":  "raid5",
-		"raid6":  "raid6",
-		"raid10": "raid10",
-		"none":   "none",
-	}
-
-	if canonical, ok := raidMap[normalized]; ok {
-		return canonical
-	}
-
-	return "" // Invalid RAID level
-}
@@ -2,9 +2,12 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,24 +26,62 @@ func newComputeCmd() *cobra.Command {
 
 	cmd.AddCommand(newComputeListCmd())
 	cmd.AddCommand(newComputeGetCmd())
+	cmd.AddCommand(newComputeHardwareCmd())
+	cmd.AddCommand(newComputeStorageCmd())
 	cmd.AddCommand(newComputeCreateCmd())
 	cmd.AddCommand(newComputeUpdateCmd())
 	cmd.AddCommand(newComputeDeleteCmd())
+	cmd.AddCommand(newComputeDrainCmd())
 
 	return cmd
 }
 
 func newComputeListCmd() *cobra.Command {
-	return &cobra.Command{
+	var parent string
+	var tags string
+	var hardware string
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all compute resources",
+		Long: `List compute resources.
+
+--tags matches the compute tags and --hardware the hardware facts derived from assigned
+components, keyed by <component type>.<field> (manufacturer, model, name or any spec field).
+Both take comma-separated terms:
+  key=value, key!=value   equal, not equal (a value with *, ? or [ is a glob pattern)
+  key>n, key>=n           numeric greater than, greater than or equal
+  key<n, key<=n           numeric less than, less than or equal
+  key=~regex              matches the regular expression
+  key, !key               exists, does not exist
+See 'compute hardware' for the facts of a compute.`,
+		Example: `  kubebuddy compute list --tags env=prod,generation>3
+  kubebuddy compute list --tags 'rack=~^r[0-9]+$'
+  kubebuddy compute list --hardware cpu.manufacturer=AMD
+  kubebuddy compute list --hardware 'gpu.model=*A100*,gpu.vram_gb>=40,!hdd.model'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := requireAPIKey(cmd); err != nil {
 				return err
 			}
 
 			c := client.New(endpoint, apiKey)
-			computes, err := c.ListComputes(context.Background(), storage.ComputeFilters{})
+
+			filters := storage.ComputeFilters{}
+			if parent != "" {
+				host, err := c.ResolveCompute(context.Background(), parent)
+				if err != nil {
+					return err
+				}
+				filters.ParentID = host.ID
+			}
+
+			var computes []*domain.Compute
+			var err error
+			if tags != "" || hardware != "" {
+				computes, err = c.ListComputesBySelector(context.Background(), filters, tags, hardware)
+			} else {
+				computes, err = c.ListComputes(context.Background(), filters)
+			}
 			if err != nil {
 				return err
 			}
@@ -49,6 +90,16 @@ func newComputeListCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&parent, "parent", "", "Only list computes hosted on this compute (ID or name)")
+	cmd.Flags().StringVar(&tags, "tags", "", "Only list computes whose tags match this selector (e.g. env=prod,generation>3)")
+	cmd.Flags().StringVar(&hardware, "hardware", "", "Only list computes whose hardware facts match this selector (e.g. cpu.manufacturer=AMD)")
+
+	cmd.RegisterFlagCompletionFunc("parent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
 }
 
 func newComputeGetCmd() *cobra.Command {
@@ -81,6 +132,130 @@ func newComputeGetCmd() *cobra.Command {
 	return cmd
 }
 
+func newComputeHardwareCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "hardware <id|name>",
+		Short: "Show the hardware facts derived from the components of a compute",
+		Long: `Show the hardware facts derived from the components of a compute, as matched by
+hardwareAffinity and hardwareAntiAffinity placement rules and 'compute list --hardware'.
+Hosted computes without components have the facts of their parent.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			ctx := context.Background()
+			compute, err := c.ResolveCompute(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			facts, err := c.GetComputeHardware(ctx, compute.ID)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(facts)
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "FACT\tVALUES")
+			for _, key := range facts.Keys() {
+				fmt.Fprintf(w, "%s\t%s\n", key, strings.Join(facts[key], ", "))
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newComputeStorageCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "storage <id|name>",
+		Short: "Show the storage layout of a compute",
+		Long: `Show the storage groups of a compute: its RAID groups and one group per tier for standalone
+disks, with the raw capacity of the member disks, the usable capacity, the capacity holding
+parity or mirror copies, the capacity of members larger than the smallest one (unused) and
+the hot spares. A RAID group that cannot be built shows why and has no usable capacity.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			ctx := context.Background()
+			compute, err := c.ResolveCompute(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			layouts, err := c.GetComputeStorage(ctx, compute.ID)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(layouts)
+				return nil
+			}
+
+			if len(layouts) == 0 {
+				fmt.Println("No storage components assigned")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "GROUP\tTIER\tLEVEL\tDISKS\tSPARES\tRAW (GB)\tUSABLE (GB)\tPARITY (GB)\tUNUSED (GB)\tSTATUS")
+			for _, layout := range layouts {
+				level := string(layout.RaidLevel)
+				if layout.Spans > 0 {
+					level = fmt.Sprintf("%s (%d spans)", level, layout.Spans)
+				}
+				status := "ok"
+				if layout.Error != "" {
+					status = layout.Error
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n",
+					layout.Group, layout.Tier, level, layout.Disks, layout.Spares,
+					layout.Raw, layout.Usable, layout.Parity, layout.Unused, status)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
 func newComputeCreateCmd() *cobra.Command {
 	var (
 		name            string
@@ -92,12 +267,19 @@ func newComputeCreateCmd() *cobra.Command {
 		annualCost      float64
 		contractEnd     string
 		renewalDate     string
+		overcommit      string
+		parent          string
+		size            string
+		force           bool
+		reservation     string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new compute resource",
-		Long:  `Create a new compute resource. Use 'component assign' to add hardware components.`,
+		Long: `Create a new compute resource. Use 'component assign' to add hardware components.
+A VM or VPS hosted on a baremetal compute sets --parent and --size: its size is carved
+out of the resources of the parent.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := requireAPIKey(cmd); err != nil {
 				return err
@@ -134,9 +316,22 @@ func newComputeCreateCmd() *cobra.Command {
 				}
 				compute.NextRenewalDate = &t
 			}
+			if overcommit != "" {
+				ratios, err := parseRatios(overcommit)
+				if err != nil {
+					return err
+				}
+				compute.Overcommit = ratios
+			}
 
 			c := client.New(endpoint, apiKey)
-			result, err := c.CreateCompute(context.Background(), compute)
+			c.SetReservation(reservation)
+
+			if err := setHierarchy(c, compute, parent, size); err != nil {
+				return err
+			}
+
+			result, err := c.CreateCompute(context.Background(), compute, force)
 			if err != nil {
 				return err
 			}
@@ -155,6 +350,11 @@ func newComputeCreateCmd() *cobra.Command {
 	cmd.Flags().Float64Var(&annualCost, "annual-cost", 0, "Annual cost")
 	cmd.Flags().StringVar(&contractEnd, "contract-end", "", "Contract end date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&renewalDate, "renewal-date", "", "Next renewal date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&overcommit, "overcommit", "", "Overcommit ratios as key=ratio pairs, comma-separated (e.g., cores=4,memory=1)")
+	cmd.Flags().StringVar(&parent, "parent", "", "Baremetal compute hosting this VM or VPS (ID or name)")
+	cmd.Flags().StringVar(&size, "size", "", "Resources of a VM or VPS as JSON, numbers or quantities with units (e.g. '{\"cores\":4,\"memory\":\"8Gi\"}')")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the capacity check of the parent")
+	addReservationFlag(cmd, &reservation)
 
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("provider")
@@ -170,6 +370,11 @@ func newComputeCreateCmd() *cobra.Command {
 		return completeProviders(), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	// Add completion for parent flag
+	cmd.RegisterFlagCompletionFunc("parent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
 	// Add completion for region flag
 	cmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completeRegions(), cobra.ShellCompDirectiveNoFileComp
@@ -190,6 +395,11 @@ func newComputeUpdateCmd() *cobra.Command {
 		annualCost      float64
 		contractEnd     string
 		renewalDate     string
+		overcommit      string
+		parent          string
+		size            string
+		force           bool
+		reservation     string
 	)
 
 	cmd := &cobra.Command{
@@ -254,8 +464,25 @@ func newComputeUpdateCmd() *cobra.Command {
 				}
 				existing.NextRenewalDate = &t
 			}
+			if cmd.Flags().Changed("overcommit") {
+				ratios, err := parseRatios(overcommit)
+				if err != nil {
+					return err
+				}
+				existing.Overcommit = ratios
+			}
+			if cmd.Flags().Changed("parent") {
+				existing.ParentID = ""
+			}
+			if cmd.Flags().Changed("size") {
+				existing.Size = nil
+			}
+			if err := setHierarchy(c, existing, parent, size); err != nil {
+				return err
+			}
 
-			result, err := c.UpdateCompute(context.Background(), existing.ID, existing)
+			c.SetReservation(reservation)
+			result, err := c.UpdateCompute(context.Background(), existing.ID, existing, force)
 			if err != nil {
 				return err
 			}
@@ -275,6 +502,11 @@ func newComputeUpdateCmd() *cobra.Command {
 	cmd.Flags().Float64Var(&annualCost, "annual-cost", 0, "Annual cost")
 	cmd.Flags().StringVar(&contractEnd, "contract-end", "", "Contract end date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&renewalDate, "renewal-date", "", "Next renewal date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&overcommit, "overcommit", "", "Overcommit ratios as key=ratio pairs, comma-separated (empty string clears them)")
+	cmd.Flags().StringVar(&parent, "parent", "", "Baremetal compute hosting this VM or VPS (ID or name, empty string clears it)")
+	cmd.Flags().StringVar(&size, "size", "", "Resources of a VM or VPS as JSON (empty string clears it)")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the capacity check of the parent")
+	addReservationFlag(cmd, &reservation)
 
 	// Add completion for type flag
 	cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -291,6 +523,11 @@ func newComputeUpdateCmd() *cobra.Command {
 		return completeProviders(), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	// Add completion for parent flag
+	cmd.RegisterFlagCompletionFunc("parent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
 	// Add completion for region flag
 	cmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completeRegions(), cobra.ShellCompDirectiveNoFileComp
@@ -333,6 +570,138 @@ func newComputeDeleteCmd() *cobra.Command {
 	return cmd
 }
 
+func newComputeDrainCmd() *cobra.Command {
+	var jsonOutput bool
+	var executeFlag bool
+	var forceFlag bool
+	var strategy string
+	var reservation string
+
+	cmd := &cobra.Command{
+		Use:   "drain <id|name>",
+		Short: "Plan or execute moving every assignment off a compute",
+		Long: `Compute a rehoming plan for every assignment on a compute, following placement
+rules and capacity, and list assignments that cannot be moved. With --execute the
+assignments and their port assignments are moved in one transaction and a journal
+entry is written on the drained compute.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+			ctx := context.Background()
+
+			compute, err := c.ResolveCompute(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			var plan *domain.DrainPlan
+			if executeFlag {
+				plan, err = c.ExecuteDrain(ctx, compute.ID, strategy, forceFlag)
+			} else {
+				plan, err = c.PlanDrain(ctx, compute.ID, strategy)
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(plan)
+				return nil
+			}
+
+			fmt.Printf("# Drain: %s\n\n", compute.Name)
+			fmt.Printf("Strategy: %s\n", plan.Strategy)
+			fmt.Printf("Reservation: %s\n\n", plan.Reservation)
+			if plan.Feasible {
+				fmt.Printf("✓ %s\n\n", plan.Message)
+			} else {
+				fmt.Printf("✗ %s\n\n", plan.Message)
+			}
+
+			printMoves(plan.Moves)
+
+			if len(plan.Unmovable) > 0 {
+				fmt.Println("## Cannot Be Moved")
+				fmt.Println()
+				fmt.Println("| Service | Instances | Reason |")
+				fmt.Println("|---------|-----------|--------|")
+				for _, unmovable := range plan.Unmovable {
+					fmt.Printf("| %s | %d | %s |\n", unmovable.ServiceName, unmovable.Quantity, unmovable.Reason)
+				}
+				fmt.Println()
+			}
+
+			for _, warning := range plan.Warnings {
+				fmt.Printf("⚠ %s\n", warning)
+			}
+
+			if plan.Executed {
+				fmt.Printf("✓ Moved %d assignment(s), journal entry added\n", len(plan.Moves))
+			} else if len(plan.Moves) > 0 {
+				fmt.Println("Run with --execute to apply the moves")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&executeFlag, "execute", false, "Move the assignments and their port assignments")
+	cmd.Flags().BoolVar(&forceFlag, "force", false, "Move what can be moved even if some assignments cannot (requires --execute)")
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("Scoring strategy used to pick targets (%s, default %s)", strings.Join(domain.ScoringStrategies(), ", "), domain.DefaultStrategy))
+	addReservationFlag(cmd, &reservation)
+
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// setHierarchy resolves the parent compute and parses the size of a hosted compute
+func setHierarchy(c *client.Client, compute *domain.Compute, parent, size string) error {
+	if parent != "" {
+		host, err := c.ResolveCompute(context.Background(), parent)
+		if err != nil {
+			return fmt.Errorf("parent compute: %w", err)
+		}
+		compute.ParentID = host.ID
+	}
+	if size != "" {
+		if err := json.Unmarshal([]byte(size), &compute.Size); err != nil {
+			return fmt.Errorf("invalid size JSON: %w", err)
+		}
+	}
+	return nil
+}
+
+// printMoves prints planned assignment moves as a markdown table
+func printMoves(moves []domain.Move) {
+	if len(moves) == 0 {
+		return
+	}
+
+	fmt.Println("## Moves")
+	fmt.Println()
+	fmt.Println("| Service | Instances | From | To |")
+	fmt.Println("|---------|-----------|------|----|")
+	for _, move := range moves {
+		fmt.Printf("| %s | %d | %s | %s |\n", move.ServiceName, move.Quantity, move.From.Name, move.To.Name)
+	}
+	fmt.Println()
+}
+
 // Helper function for compute ID completion
 func completeComputeIDs(toComplete string) []string {
 	if apiKey == "" {
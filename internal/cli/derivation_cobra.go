@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+func newDerivationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "derivation",
+		Aliases: []string{"derivation-rule"},
+		Short:   "Manage component-to-resource derivation rules",
+		Long: `Manage the rules deriving compute resources from assigned components.
+
+A rule reads the first spec field present on components of a type, converts it from its
+unit to the unit of the target resource key and aggregates it per compute (sum, count,
+max, min, or raid for RAID-aware storage). For one component, only the first rule
+(lowest priority) of a resource whose spec field is set applies.
+
+Rule targets become known resource keys that services may request.`,
+	}
+
+	cmd.AddCommand(newDerivationListCmd())
+	cmd.AddCommand(newDerivationGetCmd())
+	cmd.AddCommand(newDerivationCreateCmd())
+	cmd.AddCommand(newDerivationDeleteCmd())
+
+	return cmd
+}
+
+func newDerivationListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List derivation rules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			rules, err := c.ListDerivationRules(context.Background())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(rules)
+				return nil
+			}
+
+			if len(rules) == 0 {
+				fmt.Println("No derivation rules found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCOMPONENT TYPE\tSPEC FIELDS\tUNIT\tRESOURCE\tAGGREGATION\tPRIORITY")
+			for _, rule := range rules {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", rule.Name, rule.ComponentType, formatSpecFields(rule.SpecFields), formatUnit(rule.Unit), rule.Resource, rule.Aggregation, rule.Priority)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newDerivationGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id|name>",
+		Short: "Get derivation rule details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			rule, err := c.ResolveDerivationRule(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			printJSON(rule)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeDerivationRuleNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+func newDerivationCreateCmd() *cobra.Command {
+	var (
+		name          string
+		componentType string
+		specFields    string
+		unit          string
+		resource      string
+		aggregation   string
+		priority      int
+		description   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create or update a derivation rule",
+		Long:  `Create a derivation rule. A rule with the same name is updated.`,
+		Example: `  # FPGA cards: count them and sum their logic cells
+  kubebuddy derivation create --name fpga-count --component-type fpga --resource fpga --aggregation count
+  kubebuddy derivation create --name fpga-cells --component-type fpga --spec-fields logic_cells --resource fpga_cells
+
+  # Licence seats provided by OS components
+  kubebuddy derivation create --name os-seats --component-type os --spec-fields seats --resource licence_seats
+
+  # Largest NVMe namespace, spec in TB
+  kubebuddy derivation create --name nvme-namespace --component-type nvme --spec-fields namespace_tb --unit TB --resource nvme_namespace_gb --aggregation max`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			rule := &domain.DerivationRule{
+				Name:          name,
+				ComponentType: componentType,
+				SpecFields:    parseList(specFields),
+				Unit:          unit,
+				Resource:      resource,
+				Aggregation:   domain.Aggregation(aggregation),
+				Priority:      priority,
+				Description:   description,
+			}
+
+			c := client.New(endpoint, apiKey)
+			result, err := c.CreateDerivationRule(context.Background(), rule)
+			if err != nil {
+				return err
+			}
+
+			printJSON(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Rule name (required, unique)")
+	cmd.Flags().StringVar(&componentType, "component-type", "", "Component type the rule applies to (required)")
+	cmd.Flags().StringVar(&specFields, "spec-fields", "", "Spec fields to read, comma-separated, first present wins (required except for count)")
+	cmd.Flags().StringVar(&unit, "unit", "", "Unit of the spec field (count, MiB, GiB, TiB, GB, TB, Mbps, Gbps), converted to the unit of the resource")
+	cmd.Flags().StringVar(&resource, "resource", "", "Target resource key (required)")
+	cmd.Flags().StringVar(&aggregation, "aggregation", string(domain.AggregationSum), "Aggregation: sum, count, max, min or raid")
+	cmd.Flags().IntVar(&priority, "priority", 100, "Priority (lower = evaluated first)")
+	cmd.Flags().StringVar(&description, "description", "", "Description")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("component-type")
+	cmd.MarkFlagRequired("resource")
+
+	cmd.RegisterFlagCompletionFunc("aggregation", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"sum", "count", "max", "min", "raid"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newDerivationDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id|name>",
+		Short: "Delete a derivation rule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			rule, err := c.ResolveDerivationRule(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeleteDerivationRule(context.Background(), rule.ID); err != nil {
+				return err
+			}
+
+			printJSON(map[string]string{"message": "derivation rule deleted successfully"})
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeDerivationRuleNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+// parseList splits a comma-separated list, dropping empty entries
+func parseList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatSpecFields formats spec fields comma-separated
+func formatSpecFields(fields []string) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+	return strings.Join(fields, ",")
+}
+
+// formatUnit formats a rule unit, "-" when the spec field is already in the unit of the resource
+func formatUnit(unit string) string {
+	if unit == "" {
+		return "-"
+	}
+	return unit
+}
+
+func completeDerivationRuleNames(toComplete string) []string {
+	if apiKey == "" {
+		return nil
+	}
+
+	c := client.New(endpoint, apiKey)
+	rules, err := c.ListDerivationRules(context.Background())
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, rule := range rules {
+		completions = append(completions, rule.Name+"\t"+rule.ComponentType+" -> "+rule.Resource)
+	}
+
+	return completions
+}
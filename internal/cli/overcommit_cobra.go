@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+func newOvercommitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "overcommit",
+		Short: "Manage overcommit policies",
+		Long: `Manage tag-based overcommit policies.
+
+A policy scales the capacity of every compute matching its tags, per resource key
+(e.g. cores=4 lets services reserve four times the physical cores). Ratios set on a
+compute with 'compute update --overcommit' take precedence over policies.`,
+	}
+
+	cmd.AddCommand(newOvercommitListCmd())
+	cmd.AddCommand(newOvercommitGetCmd())
+	cmd.AddCommand(newOvercommitCreateCmd())
+	cmd.AddCommand(newOvercommitDeleteCmd())
+
+	return cmd
+}
+
+func newOvercommitListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List overcommit policies",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			policies, err := c.ListOvercommitPolicies(context.Background())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(policies)
+				return nil
+			}
+
+			if len(policies) == 0 {
+				fmt.Println("No overcommit policies found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tPRIORITY\tTAGS\tRATIOS")
+			for _, policy := range policies {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", policy.Name, policy.Priority, formatTags(policy.Tags), formatRatios(policy.Ratios))
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newOvercommitGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id|name>",
+		Short: "Get overcommit policy details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			policy, err := c.ResolveOvercommitPolicy(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			printJSON(policy)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeOvercommitPolicyNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+func newOvercommitCreateCmd() *cobra.Command {
+	var (
+		name        string
+		tags        string
+		ratios      string
+		priority    int
+		description string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create or update an overcommit policy",
+		Long:  `Create an overcommit policy. A policy with the same name is updated.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			parsed, err := parseRatios(ratios)
+			if err != nil {
+				return err
+			}
+
+			policy := &domain.OvercommitPolicy{
+				Name:        name,
+				Tags:        parseTags(tags),
+				Ratios:      parsed,
+				Priority:    priority,
+				Description: description,
+			}
+
+			c := client.New(endpoint, apiKey)
+			result, err := c.CreateOvercommitPolicy(context.Background(), policy)
+			if err != nil {
+				return err
+			}
+
+			printJSON(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Policy name (required, unique)")
+	cmd.Flags().StringVar(&tags, "tags", "", "Computes must have all tags, as key=value pairs, comma-separated (empty matches every compute)")
+	cmd.Flags().StringVar(&ratios, "ratios", "", "Overcommit ratios as key=ratio pairs, comma-separated (e.g., cores=4,memory=1) (required)")
+	cmd.Flags().IntVar(&priority, "priority", 100, "Priority (lower = higher priority)")
+	cmd.Flags().StringVar(&description, "description", "", "Description")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("ratios")
+
+	return cmd
+}
+
+func newOvercommitDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id|name>",
+		Short: "Delete an overcommit policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			policy, err := c.ResolveOvercommitPolicy(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeleteOvercommitPolicy(context.Background(), policy.ID); err != nil {
+				return err
+			}
+
+			printJSON(map[string]string{"message": "overcommit policy deleted successfully"})
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeOvercommitPolicyNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+// formatRatios formats overcommit ratios as sorted key=ratio pairs
+func formatRatios(ratios map[string]float64) string {
+	if len(ratios) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(ratios))
+	for key := range ratios {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%g", key, ratios[key]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatTags formats tags as sorted key=value pairs
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "*"
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+tags[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+func completeOvercommitPolicyNames(toComplete string) []string {
+	if apiKey == "" {
+		return nil
+	}
+
+	c := client.New(endpoint, apiKey)
+	policies, err := c.ListOvercommitPolicies(context.Background())
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, policy := range policies {
+		completions = append(completions, policy.Name+"\t"+formatRatios(policy.Ratios))
+	}
+
+	return completions
+}
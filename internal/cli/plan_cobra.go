@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/studiowebux/kubebuddy/internal/client"
@@ -39,14 +43,33 @@ func getIntValue(val interface{}) int {
 
 func newPlanCmd() *cobra.Command {
 	var jsonOutput bool
+	var reservation string
 	var computeID string
 	var assignFlag bool
 	var forceFlag bool
+	var replicas int
+	var strategy string
+	var whatIfFile string
+	var removeComputes []string
+	var removeAssignments []string
+	var asVM bool
+	var explain bool
+	var startsAt string
+	var endsAt string
 
 	cmd := &cobra.Command{
 		Use:   "plan <service-id>",
 		Short: "Plan capacity for a service",
-		Long:  `Evaluate capacity and get placement recommendations for a service`,
+		Long: `Evaluate capacity and get placement recommendations for a service.
+With --as-vm each replica gets a new VM, sized to the reserved spec, on a baremetal host;
+--assign then creates the VMs and their assignments.
+
+With --explain the output lists every compute that is not a candidate with the first check
+it fails: state, constraint, placement rule (affinity selector, topology key, spreadMax),
+missing resources per key, or buffer.
+
+With --starts-at and --ends-at the placement only needs capacity for that window: only
+assignments running during it are counted, and --assign gives the window to the assignments.`,
 		Args:  cobra.ExactArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
@@ -60,12 +83,29 @@ func newPlanCmd() *cobra.Command {
 			}
 
 			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
 			ctx := context.Background()
 
-			// Resolve service ID or name
-			service, err := c.ResolveService(ctx, args[0])
+			schedule, err := parseSchedule(startsAt, endsAt)
+			if err != nil {
+				return err
+			}
+
+			whatIf, err := loadWhatIf(whatIfFile, removeComputes, removeAssignments)
 			if err != nil {
-				return fmt.Errorf("failed to resolve service: %w", err)
+				return err
+			}
+			if whatIf != nil && assignFlag {
+				return fmt.Errorf("--assign cannot be used with a what-if scenario")
+			}
+
+			// Resolve service ID or name, including hypothetical services
+			service := whatIf.FindService(args[0])
+			if service == nil {
+				service, err = c.ResolveService(ctx, args[0])
+				if err != nil {
+					return fmt.Errorf("failed to resolve service: %w", err)
+				}
 			}
 
 			// Resolve compute ID or name if specified
@@ -80,9 +120,15 @@ func newPlanCmd() *cobra.Command {
 
 			request := domain.PlanRequest{
 				ServiceID: service.ID,
+				Replicas:  replicas,
+				Strategy:  strategy,
 				Constraints: domain.Constraints{
 					ComputeID: resolvedComputeID,
 				},
+				WhatIf:   whatIf,
+				AsVM:     asVM,
+				Explain:  explain,
+				Schedule: schedule,
 			}
 
 			result, err := c.PlanCapacity(context.Background(), request)
@@ -99,6 +145,21 @@ func newPlanCmd() *cobra.Command {
 			// User-friendly output
 
 			fmt.Printf("# Capacity Planning: %s\n\n", service.Name)
+			if result.WhatIf {
+				fmt.Printf("What-if scenario: nothing is stored\n\n")
+			}
+			if result.Strategy != "" {
+				fmt.Printf("Strategy: %s\n\n", result.Strategy)
+			}
+			if result.Reservation != "" {
+				fmt.Printf("Reservation: %s\n\n", result.Reservation)
+			}
+			if !result.Schedule.IsZero() {
+				fmt.Printf("Window: %s\n\n", result.Schedule)
+			}
+			if len(result.VMSpec) > 0 {
+				fmt.Printf("New VM per replica: %s\n\n", formatResources(result.VMSpec))
+			}
 
 			if result.Feasible {
 				fmt.Printf("✓ Feasible - Found %d candidate(s)\n\n", len(result.Candidates))
@@ -108,6 +169,11 @@ func newPlanCmd() *cobra.Command {
 					fmt.Printf("- **Provider:** %s\n", candidate.Compute.Provider)
 					fmt.Printf("- **Region:** %s\n", candidate.Compute.Region)
 					fmt.Printf("- **Score:** %.1f\n", candidate.Score)
+					if len(candidate.ScoreTerms) > 1 {
+						for _, term := range candidate.ScoreTerms {
+							fmt.Printf("  - %s: %+.1f\n", term.Term, term.Delta)
+						}
+					}
 					fmt.Printf("- **Utilization After:** %.0f%%\n", candidate.UtilizationAfter*100)
 
 					// Use RAID-aware resources from compute object (already calculated by API)
@@ -124,45 +190,18 @@ func newPlanCmd() *cobra.Command {
 					var allocatedStorageGB float64
 
 					if err == nil {
-						// For each assignment, fetch service and use MaxSpec
+						// For each assignment, fetch service and use the spec reserved under the plan's basis
 						for _, assignment := range assignments {
 							svc, err := c.GetService(ctx, assignment.ServiceID)
 							if err != nil {
 								continue // Skip if service not found
 							}
+							reserved := result.Reservation.Spec(svc)
 
-							if cores, ok := svc.MaxSpec["cores"]; ok {
-								switch v := cores.(type) {
-								case int:
-									allocatedCores += v
-								case float64:
-									allocatedCores += int(v)
-								}
-							}
-							if mem, ok := svc.MaxSpec["memory"]; ok {
-								switch v := mem.(type) {
-								case int:
-									allocatedMemoryMB += float64(v)
-								case float64:
-									allocatedMemoryMB += v
-								}
-							}
-							if vram, ok := svc.MaxSpec["vram"]; ok {
-								switch v := vram.(type) {
-								case int:
-									allocatedVRAMMB += float64(v)
-								case float64:
-									allocatedVRAMMB += v
-								}
-							}
-							if nvme, ok := svc.MaxSpec["nvme"]; ok {
-								switch v := nvme.(type) {
-								case int:
-									allocatedStorageGB += float64(v)
-								case float64:
-									allocatedStorageGB += v
-								}
-							}
+							allocatedCores += int(reserved["cores"])
+							allocatedMemoryMB += reserved["memory"].Float()
+							allocatedVRAMMB += reserved["vram"].Float()
+							allocatedStorageGB += reserved["nvme"].Float()
 						}
 					}
 
@@ -199,16 +238,24 @@ func newPlanCmd() *cobra.Command {
 
 					fmt.Println()
 				}
+
+				printPlacements(result)
+				printRejections(result.Rejections)
 			} else {
 				fmt.Println("✗ Not feasible - No suitable compute found")
 				if result.Message != "" {
 					fmt.Printf("\n%s\n", result.Message)
 				}
+				if len(result.Rejections) > 0 {
+					fmt.Println()
+					printRejections(result.Rejections)
+				}
 
 				// Show available computes and their resources
 				fmt.Println("\n**Available Computes:**")
 				computes, err := c.ListComputes(ctx, storage.ComputeFilters{})
-				if err == nil {
+				rules, rulesErr := c.ListDerivationRules(ctx)
+				if err == nil && rulesErr == nil {
 					for _, compute := range computes {
 						// Filter to specific compute if requested
 						if resolvedComputeID != "" && compute.ID != resolvedComputeID {
@@ -237,7 +284,7 @@ func newPlanCmd() *cobra.Command {
 						}
 
 						// Calculate total resources with RAID support
-						totalResources := compute.GetTotalResourcesFromComponents(components, componentAssignments)
+						totalResources := compute.GetTotalResourcesFromComponents(components, componentAssignments, rules)
 
 						// Get assignments for this compute to show allocated resources
 						assignments, err := c.ListAssignments(ctx, storage.AssignmentFilters{
@@ -250,45 +297,18 @@ func newPlanCmd() *cobra.Command {
 						var allocatedStorageGB float64
 
 						if err == nil {
-							// For each assignment, fetch service and use MaxSpec
+							// For each assignment, fetch service and use the spec reserved under the plan's basis
 							for _, assignment := range assignments {
 								svc, err := c.GetService(ctx, assignment.ServiceID)
 								if err != nil {
 									continue // Skip if service not found
 								}
+								reserved := result.Reservation.Spec(svc)
 
-								if cores, ok := svc.MaxSpec["cores"]; ok {
-									switch v := cores.(type) {
-									case int:
-										allocatedCores += v
-									case float64:
-										allocatedCores += int(v)
-									}
-								}
-								if mem, ok := svc.MaxSpec["memory"]; ok {
-									switch v := mem.(type) {
-									case int:
-										allocatedMemoryMB += float64(v)
-									case float64:
-										allocatedMemoryMB += v
-									}
-								}
-								if vram, ok := svc.MaxSpec["vram"]; ok {
-									switch v := vram.(type) {
-									case int:
-										allocatedVRAMMB += float64(v)
-									case float64:
-										allocatedVRAMMB += v
-									}
-								}
-								if nvme, ok := svc.MaxSpec["nvme"]; ok {
-									switch v := nvme.(type) {
-									case int:
-										allocatedStorageGB += float64(v)
-									case float64:
-										allocatedStorageGB += v
-									}
-								}
+								allocatedCores += int(reserved["cores"])
+								allocatedMemoryMB += reserved["memory"].Float()
+								allocatedVRAMMB += reserved["vram"].Float()
+								allocatedStorageGB += reserved["nvme"].Float()
 							}
 						}
 
@@ -326,34 +346,34 @@ func newPlanCmd() *cobra.Command {
 
 				if len(result.Recommendations) > 0 {
 					fmt.Println("\n**Recommendations:**")
-					for _, rec := range result.Recommendations {
-						fmt.Printf("- %s: %d x %s\n", rec.Rationale, rec.Quantity, rec.Type)
-						if len(rec.Spec) > 0 {
-							for k, v := range rec.Spec {
-								fmt.Printf("  - %s: %v\n", k, v)
-							}
-						}
-					}
+					printRecommendations(result.Recommendations)
 				}
 			}
 
 			// Handle assignment creation if --assign flag is used
 			if assignFlag {
-				if result.Feasible && len(result.Candidates) > 0 {
-					// Assign to best candidate (first in list)
-					targetCompute := result.Candidates[0].Compute
-					fmt.Printf("\nCreating assignment on %s...\n", targetCompute.Name)
+				if result.Feasible && len(result.Placements) > 0 {
+					if result.Spread != nil && !result.Spread.Satisfied && !forceFlag {
+						return fmt.Errorf("topology spread on %q not met, use --force to assign anyway", result.Spread.TopologyKey)
+					}
 
-					assignment := &domain.Assignment{
-						ServiceID: service.ID,
-						ComputeID: targetCompute.ID,
+					if asVM {
+						return createPlannedVMs(ctx, c, service, result)
 					}
 
-					created, err := c.CreateAssignment(ctx, assignment, false)
+					// Re-plan and assign on the server, in one transaction
+					applied, err := c.ApplyPlan(ctx, request, forceFlag)
 					if err != nil {
-						return fmt.Errorf("failed to create assignment: %w", err)
+						return fmt.Errorf("failed to create assignments: %w", err)
+					}
+					names := make(map[string]string)
+					for _, placement := range applied.Placements {
+						names[placement.Compute.ID] = placement.Compute.Name
+					}
+					fmt.Println()
+					for _, assignment := range applied.Assignments {
+						fmt.Printf("✓ Assignment %s on %s (quantity %d)\n", assignment.ID, names[assignment.ComputeID], assignment.Quantity)
 					}
-					fmt.Printf("✓ Assignment created: %s\n", created.ID)
 				} else if !result.Feasible && forceFlag && resolvedComputeID != "" {
 					// Force assignment to specific compute
 					fmt.Printf("\n⚠ Forcing assignment despite insufficient resources...\n")
@@ -361,6 +381,7 @@ func newPlanCmd() *cobra.Command {
 					assignment := &domain.Assignment{
 						ServiceID: service.ID,
 						ComputeID: resolvedComputeID,
+						Schedule:  schedule,
 					}
 
 					created, err := c.CreateAssignment(ctx, assignment, true)
@@ -381,8 +402,20 @@ func newPlanCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 	cmd.Flags().StringVar(&computeID, "compute", "", "Plan for specific compute ID or name (optional)")
-	cmd.Flags().BoolVar(&assignFlag, "assign", false, "Create assignment on best candidate")
-	cmd.Flags().BoolVar(&forceFlag, "force", false, "Force assignment even if resources insufficient (requires --assign)")
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "Number of replicas to place")
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("Scoring strategy (%s, default %s)", strings.Join(domain.ScoringStrategies(), ", "), domain.DefaultStrategy))
+	cmd.Flags().BoolVar(&assignFlag, "assign", false, "Create assignments for the planned placements")
+	cmd.Flags().BoolVar(&forceFlag, "force", false, "Force assignment even if resources insufficient or topology spread not met (requires --assign)")
+	cmd.Flags().BoolVar(&asVM, "as-vm", false, "Place a new VM per replica on a baremetal host instead of the service on an existing compute")
+	cmd.Flags().BoolVar(&explain, "explain", false, "List why each rejected compute is not a candidate")
+	cmd.Flags().StringVar(&startsAt, "starts-at", "", "Target date the placement starts (RFC3339 or YYYY-MM-DD, default: now)")
+	cmd.Flags().StringVar(&endsAt, "ends-at", "", "Date the placement ends (RFC3339 or YYYY-MM-DD, default: indefinitely)")
+	addWhatIfFlags(cmd, &whatIfFile, &removeComputes, &removeAssignments)
+	addReservationFlag(cmd, &reservation)
+
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	// Add auto-completion for --compute flag
 	cmd.RegisterFlagCompletionFunc("compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -391,3 +424,445 @@ func newPlanCmd() *cobra.Command {
 
 	return cmd
 }
+
+// createPlannedVMs creates a VM per planned replica on its host, sized to the VM spec of the
+// plan, and assigns one instance of the service to it
+func createPlannedVMs(ctx context.Context, c *client.Client, service *domain.Service, result *domain.PlanResult) error {
+	computes, err := c.ListComputes(ctx, storage.ComputeFilters{})
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(computes))
+	for _, compute := range computes {
+		taken[compute.Name] = true
+	}
+
+	for _, placement := range result.Placements {
+		host := placement.Compute
+
+		name := ""
+		for n := 1; name == "" || taken[name]; n++ {
+			name = fmt.Sprintf("%s-%s-%d", service.Name, host.Name, n)
+		}
+		taken[name] = true
+
+		vm := &domain.Compute{
+			Name:     name,
+			Type:     domain.ComputeTypeVM,
+			Provider: host.Provider,
+			Region:   host.Region,
+			Tags:     host.Tags,
+			State:    domain.ComputeStateActive,
+			ParentID: host.ID,
+			Size:     result.VMSpec,
+		}
+
+		fmt.Printf("\nCreating VM %s on %s...\n", name, host.Name)
+		created, err := c.CreateCompute(ctx, vm, false)
+		if err != nil {
+			return fmt.Errorf("failed to create VM: %w", err)
+		}
+
+		assignment, err := c.CreateAssignment(ctx, &domain.Assignment{
+			ServiceID: service.ID,
+			ComputeID: created.ID,
+			Quantity:  1,
+			Schedule:  result.Schedule,
+		}, false)
+		if err != nil {
+			return fmt.Errorf("failed to create assignment: %w", err)
+		}
+		fmt.Printf("✓ VM created: %s, assignment created: %s\n", created.ID, assignment.ID)
+	}
+
+	return nil
+}
+
+func newPlanStackCmd() *cobra.Command {
+	var jsonOutput bool
+	var reservation string
+	var strategy string
+	var applyFlag bool
+	var forceFlag bool
+	var startsAt string
+	var endsAt string
+
+	cmd := &cobra.Command{
+		Use:   "plan-stack <service[=replicas]>...",
+		Short: "Plan capacity for a group of services",
+		Long: `Plan several services together (e.g. an app, its queue and its database).
+Members are placed in order and capacity taken by earlier members is counted
+for later ones. The stack is all-or-nothing: --apply creates every assignment
+in one transaction, or none at all.`,
+		Example: `  kubebuddy plan-stack app=3 queue postgres-db=2
+  kubebuddy plan-stack app=3 queue postgres-db=2 --apply`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeServiceIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+			ctx := context.Background()
+
+			schedule, err := parseSchedule(startsAt, endsAt)
+			if err != nil {
+				return err
+			}
+
+			request := domain.StackPlanRequest{
+				Strategy: strategy,
+				Members:  make([]domain.StackMember, 0, len(args)),
+				Schedule: schedule,
+			}
+
+			for _, arg := range args {
+				name, count, hasCount := strings.Cut(arg, "=")
+				replicas := 1
+				if hasCount {
+					if _, err := fmt.Sscanf(count, "%d", &replicas); err != nil || replicas < 1 {
+						return fmt.Errorf("invalid replica count in %q", arg)
+					}
+				}
+
+				service, err := c.ResolveService(ctx, name)
+				if err != nil {
+					return fmt.Errorf("failed to resolve service %s: %w", name, err)
+				}
+
+				request.Members = append(request.Members, domain.StackMember{
+					ServiceID: service.ID,
+					Replicas:  replicas,
+				})
+			}
+
+			var result *domain.StackPlanResult
+			if applyFlag {
+				result, err = c.ApplyStack(ctx, request, forceFlag)
+			} else {
+				result, err = c.PlanStack(ctx, request)
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(result)
+				return nil
+			}
+
+			fmt.Printf("# Stack Planning\n\n")
+			if result.Strategy != "" {
+				fmt.Printf("Strategy: %s\n\n", result.Strategy)
+			}
+			if result.Reservation != "" {
+				fmt.Printf("Reservation: %s\n\n", result.Reservation)
+			}
+			if !result.Schedule.IsZero() {
+				fmt.Printf("Window: %s\n\n", result.Schedule)
+			}
+
+			if result.Feasible {
+				fmt.Printf("✓ Feasible - All %d member(s) placed\n\n", len(result.Members))
+			} else {
+				fmt.Printf("✗ Not feasible - %s\n\n", result.Message)
+			}
+
+			for _, member := range result.Members {
+				status := "✓"
+				if !member.Feasible {
+					status = "✗"
+				}
+				fmt.Printf("## %s %s (%d replica(s))\n\n", status, member.ServiceName, member.Replicas)
+
+				printPlacements(&domain.PlanResult{
+					Placements: member.Placements,
+					Spread:     member.Spread,
+					Message:    member.Message,
+				})
+				if len(member.Placements) == 0 && member.Message != "" {
+					fmt.Printf("%s\n\n", member.Message)
+				}
+
+				for _, rec := range member.Recommendations {
+					fmt.Printf("- Recommendation: %s: %d x %s\n", rec.Rationale, rec.Quantity, rec.Type)
+				}
+				if len(member.Recommendations) > 0 {
+					fmt.Println()
+				}
+			}
+
+			if result.Applied {
+				fmt.Printf("✓ Applied %d assignment(s)\n", len(result.Assignments))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("Scoring strategy (%s, default %s)", strings.Join(domain.ScoringStrategies(), ", "), domain.DefaultStrategy))
+	cmd.Flags().BoolVar(&applyFlag, "apply", false, "Create all assignments in one transaction when the stack fits")
+	cmd.Flags().BoolVar(&forceFlag, "force", false, "Apply even if a topology spread is not met (requires --apply)")
+	cmd.Flags().StringVar(&startsAt, "starts-at", "", "Target date the stack starts (RFC3339 or YYYY-MM-DD, default: now)")
+	cmd.Flags().StringVar(&endsAt, "ends-at", "", "Date the stack ends (RFC3339 or YYYY-MM-DD, default: indefinitely)")
+	addReservationFlag(cmd, &reservation)
+
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newRebalanceCmd() *cobra.Command {
+	var jsonOutput bool
+	var reservation string
+	var goal string
+	var strategy string
+	var maxMoves int
+	var applyProposal int
+
+	cmd := &cobra.Command{
+		Use:   "rebalance",
+		Short: "Propose moves that free computes or even out utilization",
+		Long: `Analyze the current assignments and propose a minimal set of moves that respect
+placement rules, spreadMax and capacity. Goal "consolidate" proposes, per compute,
+the moves that free it entirely; goal "balance" evens out utilization. Apply a
+proposal as one batch with --apply <number>.`,
+		Example: `  kubebuddy rebalance
+  kubebuddy rebalance --goal balance --max-moves 5
+  kubebuddy rebalance --apply 1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+			ctx := context.Background()
+
+			request := domain.RebalanceRequest{
+				Goal:     goal,
+				Strategy: strategy,
+				MaxMoves: maxMoves,
+			}
+
+			var plan *domain.RebalancePlan
+			var err error
+			if applyProposal > 0 {
+				plan, err = c.ApplyRebalance(ctx, request, applyProposal)
+			} else {
+				plan, err = c.Rebalance(ctx, request)
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(plan)
+				return nil
+			}
+
+			fmt.Printf("# Rebalance: %s\n\n", plan.Goal)
+			fmt.Printf("Strategy: %s\n", plan.Strategy)
+			fmt.Printf("Reservation: %s\n\n", plan.Reservation)
+			if len(plan.Proposals) == 0 {
+				fmt.Println(plan.Message)
+				return nil
+			}
+
+			for _, proposal := range plan.Proposals {
+				if applyProposal > 0 && proposal.Number != applyProposal {
+					continue
+				}
+
+				fmt.Printf("## Proposal %d: %s (%d move(s))\n\n", proposal.Number, proposal.Description, proposal.MoveCount)
+				fmt.Printf("Imbalance: %.0f%% → %.0f%%\n\n", proposal.ImbalanceBefore, proposal.ImbalanceAfter)
+
+				fmt.Println("| Compute | Before | After |")
+				fmt.Println("|---------|--------|-------|")
+				for _, change := range proposal.Utilization {
+					fmt.Printf("| %s | %.0f%% | %.0f%% |\n", change.Compute.Name, change.Before, change.After)
+				}
+				fmt.Println()
+
+				printMoves(proposal.Moves)
+			}
+
+			for _, warning := range plan.Warnings {
+				fmt.Printf("⚠ %s\n", warning)
+			}
+
+			if plan.Applied > 0 {
+				fmt.Printf("✓ Proposal %d applied\n", plan.Applied)
+			} else {
+				fmt.Println("Run with --apply <number> to apply a proposal")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&goal, "goal", domain.RebalanceGoalConsolidate, "Rebalance goal (consolidate, balance)")
+	cmd.Flags().StringVar(&strategy, "strategy", "", "Scoring strategy used to pick targets (default binpack for consolidate, spread for balance)")
+	cmd.Flags().IntVar(&maxMoves, "max-moves", 0, "Maximum instances moved per proposal (0 = no limit)")
+	cmd.Flags().IntVar(&applyProposal, "apply", 0, "Apply the proposal with this number as one batch")
+	addReservationFlag(cmd, &reservation)
+
+	cmd.RegisterFlagCompletionFunc("goal", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{domain.RebalanceGoalConsolidate, domain.RebalanceGoalBalance}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// printRecommendations prints purchase recommendations, with the parts of hardware builds
+func printRecommendations(recommendations []domain.Recommendation) {
+	for _, rec := range recommendations {
+		fmt.Printf("- %s: %d x %s\n", rec.Rationale, rec.Quantity, rec.Type)
+		if len(rec.Parts) > 0 {
+			fmt.Println("  - Parts (per host):")
+			for _, part := range rec.Parts {
+				raid := ""
+				if part.RaidLevel != "" {
+					raid = fmt.Sprintf(" (%s)", part.RaidLevel)
+				}
+				fmt.Printf("    - %d x %s %s [%s]%s\n", part.Quantity, part.Component.Manufacturer, part.Component.Model, part.Component.Type, raid)
+			}
+			fmt.Println("  - Resulting resources (headroom):")
+			for _, k := range sortedResourceKeys(rec.TotalResources) {
+				fmt.Printf("    - %s: %v (%+.0f)\n", k, rec.TotalResources[k], getFloatResource(rec.Headroom, k))
+			}
+		} else if len(rec.Spec) > 0 {
+			for k, v := range rec.Spec {
+				fmt.Printf("  - %s: %v\n", k, v)
+			}
+		}
+	}
+}
+
+// printRejections prints the first failing check of each compute rejected by an explain plan
+func printRejections(rejections []domain.Rejection) {
+	if len(rejections) == 0 {
+		return
+	}
+
+	fmt.Println("## Rejected Computes")
+	fmt.Println()
+	fmt.Println("| Compute | Check | Reason |")
+	fmt.Println("|---------|-------|--------|")
+	for _, rejection := range rejections {
+		fmt.Printf("| %s | %s | %s |\n", rejection.Compute.Name, rejection.Check, rejection.Reason)
+	}
+	fmt.Println()
+}
+
+// printPlacements prints the per-replica placements and topology spread of a plan
+func printPlacements(result *domain.PlanResult) {
+	if len(result.Placements) == 0 {
+		return
+	}
+
+	fmt.Println("## Placements")
+	fmt.Println()
+	fmt.Println("| Replica | Compute | Topology | Score |")
+	fmt.Println("|---------|---------|----------|-------|")
+	for _, placement := range result.Placements {
+		topology := placement.TopologyValue
+		if topology == "" {
+			topology = "-"
+		}
+		fmt.Printf("| %d | %s | %s | %.1f |\n", placement.Replica, placement.Compute.Name, topology, placement.Score)
+	}
+	fmt.Println()
+
+	if result.Spread != nil {
+		if result.Spread.Satisfied {
+			fmt.Printf("✓ Spread across %q satisfied\n", result.Spread.TopologyKey)
+		} else {
+			fmt.Printf("⚠ Spread across %q not met\n", result.Spread.TopologyKey)
+		}
+
+		values := make([]string, 0, len(result.Spread.Domains))
+		for value := range result.Spread.Domains {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			fmt.Printf("- %s: %d instance(s)\n", value, result.Spread.Domains[value])
+		}
+		fmt.Println()
+	}
+
+	if result.Message != "" {
+		fmt.Printf("%s\n\n", result.Message)
+	}
+}
+
+// sortedResourceKeys returns resource keys in alphabetical order
+func sortedResourceKeys(resources domain.Resources) []string {
+	keys := make([]string, 0, len(resources))
+	for k := range resources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getFloatResource returns a numeric resource value as float64 (0 if missing)
+func getFloatResource(resources domain.Resources, key string) float64 {
+	return resources.Get(key).Float()
+}
+
+// addReservationFlag registers the flag selecting the reservation basis
+func addReservationFlag(cmd *cobra.Command, reservation *string) {
+	cmd.Flags().StringVar(reservation, "reservation", "", "Reservation basis: min, max or p0-p100 between them (default: server setting)")
+
+	cmd.RegisterFlagCompletionFunc("reservation", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"min", "max", "p50", "p75", "p90"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// addWhatIfFlags registers the flags describing a hypothetical scenario
+func addWhatIfFlags(cmd *cobra.Command, file *string, removeComputes, removeAssignments *[]string) {
+	cmd.Flags().StringVar(file, "what-if", "", "JSON file with hypothetical computes, services and removals")
+	cmd.Flags().StringArrayVar(removeComputes, "remove-compute", nil, "Leave a compute out of the scenario (ID or name, repeatable)")
+	cmd.Flags().StringArrayVar(removeAssignments, "remove-assignment", nil, "Leave an assignment out of the scenario (ID, repeatable)")
+
+	cmd.RegisterFlagCompletionFunc("remove-compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// loadWhatIf builds a what-if scenario from a JSON file and removal flags (nil when none is given)
+func loadWhatIf(file string, removeComputes, removeAssignments []string) (*domain.WhatIf, error) {
+	whatIf := &domain.WhatIf{}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read what-if file: %w", err)
+		}
+		if err := json.Unmarshal(data, whatIf); err != nil {
+			return nil, fmt.Errorf("invalid what-if JSON: %w", err)
+		}
+	}
+
+	whatIf.RemoveComputes = append(whatIf.RemoveComputes, removeComputes...)
+	whatIf.RemoveAssignments = append(whatIf.RemoveAssignments, removeAssignments...)
+
+	if whatIf.IsEmpty() {
+		return nil, nil
+	}
+	return whatIf, nil
+}
@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func newPoolCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pool",
+		Short: "Manage storage pools built on RAID groups",
+		Long: `Manage storage pools, ZFS pools or LVM volume groups, built on the RAID groups of a compute.
+
+A pool sums the usable capacity of its RAID groups, which must be of the same storage tier.
+Volumes are carved out of it. Planning places the storage of services in the free space of
+the pool instead of the raw capacity of its RAID groups.`,
+	}
+
+	cmd.AddCommand(newPoolListCmd())
+	cmd.AddCommand(newPoolGetCmd())
+	cmd.AddCommand(newPoolCreateCmd())
+	cmd.AddCommand(newPoolDeleteCmd())
+
+	return cmd
+}
+
+func newPoolListCmd() *cobra.Command {
+	var (
+		computeID  string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List storage pools with their capacity and free space",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			c := client.New(endpoint, apiKey)
+
+			computes, err := c.ListComputes(ctx, storage.ComputeFilters{})
+			if err != nil {
+				return err
+			}
+			names := make(map[string]string, len(computes))
+			for _, compute := range computes {
+				names[compute.ID] = compute.Name
+			}
+
+			filters := storage.PoolFilters{}
+			if computeID != "" {
+				compute, err := c.ResolveCompute(ctx, computeID)
+				if err != nil {
+					return fmt.Errorf("failed to resolve compute: %w", err)
+				}
+				filters.ComputeID = compute.ID
+			}
+
+			pools, err := c.ListPools(ctx, filters)
+			if err != nil {
+				return err
+			}
+
+			// Usage is computed per compute, from its storage layout
+			usages := make([]domain.PoolUsage, 0, len(pools))
+			seen := make(map[string]bool)
+			for _, pool := range pools {
+				if seen[pool.ComputeID] {
+					continue
+				}
+				seen[pool.ComputeID] = true
+				computeUsages, err := c.GetComputePools(ctx, pool.ComputeID)
+				if err != nil {
+					return err
+				}
+				usages = append(usages, computeUsages...)
+			}
+
+			if jsonOutput {
+				printJSON(usages)
+				return nil
+			}
+
+			if len(usages) == 0 {
+				fmt.Println("No pools found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCOMPUTE\tTYPE\tRAID GROUPS\tTIER\tREDUNDANCY\tCAPACITY\tUSED\tFREE\tVOLUMES\tSTATUS")
+			for _, usage := range usages {
+				status := "ok"
+				if usage.Error != "" {
+					status = usage.Error
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s GB\t%s GB\t%s GB\t%d\t%s\n",
+					usage.Pool.Name,
+					names[usage.Pool.ComputeID],
+					usage.Pool.Type,
+					strings.Join(usage.Pool.RaidGroups, ","),
+					usage.Tier,
+					usage.Redundancy,
+					usage.Capacity,
+					usage.Used,
+					usage.Free,
+					len(usage.Volumes),
+					status,
+				)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&computeID, "compute", "", "Filter by compute (ID or name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	cmd.RegisterFlagCompletionFunc("compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newPoolGetCmd() *cobra.Command {
+	var computeID string
+
+	cmd := &cobra.Command{
+		Use:   "get <id|name>",
+		Short: "Get pool details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			pool, err := resolvePool(c, args[0], computeID)
+			if err != nil {
+				return err
+			}
+
+			printJSON(pool)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completePoolNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute of the pool (ID or name), when several computes have a pool of that name")
+
+	return cmd
+}
+
+func newPoolCreateCmd() *cobra.Command {
+	var (
+		name        string
+		computeID   string
+		poolType    string
+		raidGroups  []string
+		description string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create or update a storage pool",
+		Long: `Create a pool on RAID groups of a compute. A pool with the same name on the compute is updated.
+
+The RAID groups must exist on the compute (see 'compute storage'), be valid, share one
+storage tier and belong to no other pool. Standalone disks cannot be pooled.`,
+		Example: `  # ZFS pool on a RAID10 group
+  kubebuddy pool create --name tank --compute baremetal-prod-01 --type zfs --raid-groups nvme-array
+
+  # LVM volume group spanning two RAID groups
+  kubebuddy pool create --name vg-data --compute baremetal-prod-01 --type lvm --raid-groups array-a,array-b`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+
+			compute, err := c.ResolveCompute(context.Background(), computeID)
+			if err != nil {
+				return fmt.Errorf("failed to resolve compute: %w", err)
+			}
+
+			pool := &domain.Pool{
+				Name:        name,
+				ComputeID:   compute.ID,
+				Type:        domain.PoolType(strings.ToLower(poolType)),
+				RaidGroups:  raidGroups,
+				Description: description,
+			}
+
+			result, err := c.CreatePool(context.Background(), pool)
+			if err != nil {
+				return err
+			}
+
+			printJSON(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Pool name, unique per compute (required)")
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute ID or name (required)")
+	cmd.Flags().StringVar(&poolType, "type", "", "Pool type: zfs or lvm (required)")
+	cmd.Flags().StringSliceVar(&raidGroups, "raid-groups", nil, "RAID groups the pool is built on, comma-separated (required)")
+	cmd.Flags().StringVar(&description, "description", "", "Description")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("compute")
+	cmd.MarkFlagRequired("type")
+	cmd.MarkFlagRequired("raid-groups")
+
+	cmd.RegisterFlagCompletionFunc("compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(domain.PoolTypeZFS), string(domain.PoolTypeLVM)}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newPoolDeleteCmd() *cobra.Command {
+	var computeID string
+
+	cmd := &cobra.Command{
+		Use:   "delete <id|name>",
+		Short: "Delete a storage pool without volumes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			pool, err := resolvePool(c, args[0], computeID)
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeletePool(context.Background(), pool.ID); err != nil {
+				return err
+			}
+
+			printJSON(map[string]string{"message": "pool deleted successfully"})
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completePoolNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute of the pool (ID or name), when several computes have a pool of that name")
+
+	return cmd
+}
+
+// resolvePool gets a pool by ID or name, on the compute given by ID or name if any
+func resolvePool(c *client.Client, idOrName, computeID string) (*domain.Pool, error) {
+	if computeID != "" {
+		compute, err := c.ResolveCompute(context.Background(), computeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve compute: %w", err)
+		}
+		computeID = compute.ID
+	}
+	return c.ResolvePool(context.Background(), idOrName, computeID)
+}
+
+func completePoolNames(toComplete string) []string {
+	if apiKey == "" {
+		return nil
+	}
+
+	c := client.New(endpoint, apiKey)
+	pools, err := c.ListPools(context.Background(), storage.PoolFilters{})
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, pool := range pools {
+		completions = append(completions, pool.Name+"\t"+string(pool.Type)+" on "+strings.Join(pool.RaidGroups, ","))
+	}
+
+	return completions
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/studiowebux/kubebuddy/internal/client"
@@ -19,6 +20,12 @@ func newReportCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newReportComputeCmd())
+	cmd.AddCommand(newReportCapacityCmd())
+	cmd.AddCommand(newReportResilienceCmd())
+	cmd.AddCommand(newReportReservationsCmd())
+	cmd.AddCommand(newReportHistoryCmd())
+	cmd.AddCommand(newReportForecastCmd())
+	cmd.AddCommand(newReportTimelineCmd())
 
 	return cmd
 }
@@ -26,6 +33,7 @@ func newReportCmd() *cobra.Command {
 func newReportComputeCmd() *cobra.Command {
 	var computeID string
 	var detailedJournal bool
+	var reservation string
 
 	cmd := &cobra.Command{
 		Use:   "compute [name or id]",
@@ -36,6 +44,11 @@ func newReportComputeCmd() *cobra.Command {
 				return err
 			}
 
+			basis, err := domain.ParseReservationBasis(reservation)
+			if err != nil {
+				return err
+			}
+
 			c := client.New(endpoint, apiKey)
 
 			// If ID or name provided as argument, use it
@@ -52,9 +65,9 @@ func newReportComputeCmd() *cobra.Command {
 
 				for i, compute := range computes {
 					if i > 0 {
-						fmt.Println("\n---\n")
+						fmt.Print("\n---\n\n")
 					}
-					if err := printComputeReport(c, compute.ID, detailedJournal); err != nil {
+					if err := printComputeReport(c, compute.ID, detailedJournal, basis); err != nil {
 						return err
 					}
 				}
@@ -69,7 +82,7 @@ func newReportComputeCmd() *cobra.Command {
 			}
 
 			// Generate report for specific compute
-			return printComputeReport(c, compute.ID, detailedJournal)
+			return printComputeReport(c, compute.ID, detailedJournal, basis)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
@@ -80,16 +93,683 @@ func newReportComputeCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&detailedJournal, "journal", false, "Show detailed journal entries with full content")
+	cmd.Flags().StringVar(&reservation, "reservation", "", "Reservation basis for allocated resources (min, max or p0-p100, default max)")
 
 	return cmd
 }
 
-// storageInfo holds information about storage components
-type storageInfo struct {
-	size      float64
-	quantity  int
-	raidLevel string
-	name      string
+func newReportCapacityCmd() *cobra.Command {
+	var jsonOutput bool
+	var whatIfFile string
+	var removeComputes []string
+	var removeAssignments []string
+	var reservation string
+	var at string
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Generate capacity report for all computes",
+		Long: `Show total, allocated and available resources per compute, optionally on a what-if scenario.
+
+Only assignments running at the report instant are counted: now, or the date given with --at.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			var atTime *time.Time
+			if at != "" {
+				t, err := parseTime(at)
+				if err != nil {
+					return fmt.Errorf("invalid --at: %w", err)
+				}
+				atTime = &t
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			whatIf, err := loadWhatIf(whatIfFile, removeComputes, removeAssignments)
+			if err != nil {
+				return err
+			}
+
+			report, err := c.CapacityReport(context.Background(), whatIf, atTime)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(report)
+				return nil
+			}
+
+			printCapacityReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&at, "at", "", "Report the assignments running at this date (RFC3339 or YYYY-MM-DD, default: now)")
+	addWhatIfFlags(cmd, &whatIfFile, &removeComputes, &removeAssignments)
+	addReservationFlag(cmd, &reservation)
+
+	return cmd
+}
+
+func newReportResilienceCmd() *cobra.Command {
+	var jsonOutput bool
+	var topologyKey string
+	var strategy string
+	var whatIfFile string
+	var removeComputes []string
+	var removeAssignments []string
+	var reservation string
+
+	cmd := &cobra.Command{
+		Use:   "resilience",
+		Short: "Check whether losing any single failure domain strands services (N+1)",
+		Long: `Simulate the loss of each failure domain in turn (each compute by default, or each
+value of a topology key such as rack or zone), re-plan the displaced instances onto
+the surviving computes with the usual placement rules, and report which services
+would be stranded and the extra capacity needed to be N+1 safe.`,
+		Example: `  kubebuddy report resilience
+  kubebuddy report resilience --topology-key rack
+  kubebuddy report resilience --topology-key zone --what-if new-hosts.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			whatIf, err := loadWhatIf(whatIfFile, removeComputes, removeAssignments)
+			if err != nil {
+				return err
+			}
+
+			report, err := c.ResilienceReport(context.Background(), domain.ResilienceRequest{
+				TopologyKey: topologyKey,
+				Strategy:    strategy,
+				WhatIf:      whatIf,
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(report)
+				return nil
+			}
+
+			printResilienceReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&topologyKey, "topology-key", "", "Failure domain: host (default), region, provider or a tag key such as rack or zone")
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("Scoring strategy used to re-plan displaced instances (%s, default %s)", strings.Join(domain.ScoringStrategies(), ", "), domain.DefaultStrategy))
+	addWhatIfFlags(cmd, &whatIfFile, &removeComputes, &removeAssignments)
+	addReservationFlag(cmd, &reservation)
+
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newReportReservationsCmd() *cobra.Command {
+	var jsonOutput bool
+	var within string
+	var reservation string
+
+	cmd := &cobra.Command{
+		Use:   "reservations",
+		Short: "List capacity reservations about to expire",
+		Long: `List the reservations expiring within a window, soonest first, with the computes
+their capacity is held on. Expired reservations no longer hold capacity.`,
+		Example: `  kubebuddy report reservations
+  kubebuddy report reservations --within 72h
+  kubebuddy report reservations --within 30d --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			expiring, err := c.ExpiringReservations(context.Background(), within)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(expiring)
+				return nil
+			}
+
+			printReservationReport(expiring, within)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().StringVar(&within, "within", "7d", "Window to look ahead (e.g. 72h, 14d)")
+	addReservationFlag(cmd, &reservation)
+
+	return cmd
+}
+
+// printReservationReport prints the reservations about to expire as markdown
+func printReservationReport(expiring []domain.ExpiringReservation, within string) {
+	fmt.Printf("# Reservations Expiring Within %s\n\n", within)
+
+	if len(expiring) == 0 {
+		fmt.Println("No reservation expires in this window")
+		return
+	}
+
+	fmt.Println("| Reservation | Owner | Status | Expires | In | Resources | Held On | Shortfall |")
+	fmt.Println("|-------------|-------|--------|---------|----|-----------|---------|-----------|")
+	for _, entry := range expiring {
+		holds := make([]string, 0, len(entry.Holds))
+		for _, hold := range entry.Holds {
+			holds = append(holds, fmt.Sprintf("%s (%s)", hold.ComputeName, formatResources(hold.Resources)))
+		}
+		heldOn := "-"
+		if len(holds) > 0 {
+			heldOn = strings.Join(holds, "; ")
+		}
+		fmt.Printf("| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			entry.Name,
+			entry.Owner,
+			entry.Status,
+			entry.ExpiresAt.Format(time.RFC3339),
+			entry.ExpiresIn,
+			formatResources(entry.Resources),
+			heldOn,
+			formatResources(entry.Shortfall),
+		)
+	}
+}
+
+func newReportHistoryCmd() *cobra.Command {
+	var (
+		groupBy    string
+		since      string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show utilization over time from capacity snapshots",
+		Long: `Show the utilization of groups of computes over time, one row per capacity snapshot.
+
+Groups sum the active computes that are not hosted on another compute; group by compute to
+follow every compute on its own.`,
+		Example: `  kubebuddy report history
+  kubebuddy report history --group-by region --since 30d
+  kubebuddy report history --group-by rack --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			from, err := sinceTime(since)
+			if err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			history, err := c.CapacityHistory(context.Background(), groupBy, from, nil)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(history)
+				return nil
+			}
+
+			printCapacityHistory(history)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group computes by all (default), compute, region, provider or a tag key")
+	cmd.Flags().StringVar(&since, "since", "", "Only use snapshots taken within this window (e.g. 72h, 30d)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newReportForecastCmd() *cobra.Command {
+	var (
+		request    domain.ForecastRequest
+		method     string
+		horizon    string
+		since      string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Forecast when groups of computes will cross a utilization threshold",
+		Long: `Extrapolate the utilization history of each group of computes to estimate when it will
+cross a threshold.
+
+The linear method fits a least-squares line through the snapshots. The holt-winters method
+smooths the level and trend of the utilization and, given a season length in snapshots (e.g.
+24 for a daily cycle of hourly snapshots) and at least two seasons of history, its seasonality.`,
+		Example: `  kubebuddy report forecast
+  kubebuddy report forecast --group-by region --threshold 90
+  kubebuddy report forecast --group-by provider --method holt-winters --season 24
+  kubebuddy report forecast --resource memory --horizon 90d`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			from, err := sinceTime(since)
+			if err != nil {
+				return err
+			}
+			request.Method = domain.ForecastMethod(method)
+
+			c := client.New(endpoint, apiKey)
+			forecast, err := c.CapacityForecast(context.Background(), request, horizon, from, nil)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(forecast)
+				return nil
+			}
+
+			printCapacityForecast(forecast)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&request.GroupBy, "group-by", "", "Group computes by all (default), compute, region, provider or a tag key")
+	cmd.Flags().StringVar(&request.Resource, "resource", "", "Forecast the utilization of one resource key instead of the average")
+	cmd.Flags().Float64Var(&request.Threshold, "threshold", domain.DefaultForecastThreshold, "Utilization percentage to look for")
+	cmd.Flags().StringVar(&method, "method", string(domain.ForecastLinear), "Forecast method: linear or holt-winters")
+	cmd.Flags().IntVar(&request.SeasonLength, "season", 0, "Snapshots per season for holt-winters (e.g. 24 hourly snapshots)")
+	cmd.Flags().StringVar(&horizon, "horizon", "365d", "How far ahead to look (e.g. 90d)")
+	cmd.Flags().StringVar(&since, "since", "", "Only use snapshots taken within this window (e.g. 72h, 30d)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newReportTimelineCmd() *cobra.Command {
+	var (
+		groupBy     string
+		from        string
+		within      string
+		reservation string
+		jsonOutput  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "timeline",
+		Short: "Show utilization at every scheduled change ahead",
+		Long: `Show the utilization of groups of computes at the start of a window and after every
+scheduled change within it: assignments starting or ending, and reservations expiring.`,
+		Example: `  kubebuddy report timeline
+  kubebuddy report timeline --within 30d --group-by region
+  kubebuddy report timeline --from 2026-11-01 --within 14d --group-by compute`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			var fromTime *time.Time
+			if from != "" {
+				t, err := parseTime(from)
+				if err != nil {
+					return fmt.Errorf("invalid --from: %w", err)
+				}
+				fromTime = &t
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			timeline, err := c.CapacityTimeline(context.Background(), groupBy, fromTime, within)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(timeline)
+				return nil
+			}
+
+			printCapacityTimeline(timeline)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group computes by all (default), compute, region, provider or a tag key")
+	cmd.Flags().StringVar(&from, "from", "", "Start of the window (RFC3339 or YYYY-MM-DD, default: now)")
+	cmd.Flags().StringVar(&within, "within", "90d", "Length of the window (e.g. 14d, 72h)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	addReservationFlag(cmd, &reservation)
+
+	return cmd
+}
+
+// printCapacityTimeline prints the scheduled changes and the utilization after each of them as markdown
+func printCapacityTimeline(timeline *domain.CapacityTimeline) {
+	const layout = "2006-01-02 15:04"
+	fmt.Printf("# Capacity Timeline by %s\n\n", timeline.GroupBy)
+	fmt.Printf("From %s to %s (reservation %s)\n\n", timeline.From.Local().Format(layout), timeline.To.Local().Format(layout), timeline.Reservation)
+
+	fmt.Println("## Scheduled Changes")
+	fmt.Println()
+	if len(timeline.Events) == 0 {
+		fmt.Println("No scheduled change in this window")
+	} else {
+		fmt.Println("| Time | Change | Service | Compute | Instances |")
+		fmt.Println("|------|--------|---------|---------|-----------|")
+		for _, event := range timeline.Events {
+			subject := event.ServiceName
+			if event.Reservation != "" {
+				subject = "reservation " + event.Reservation
+			}
+			compute := event.ComputeName
+			if compute == "" {
+				compute = "-"
+			}
+			quantity := "-"
+			if event.Quantity > 0 {
+				quantity = fmt.Sprintf("%d", event.Quantity)
+			}
+			fmt.Printf("| %s | %s | %s | %s | %s |\n", event.Time.Local().Format(layout), event.Kind, subject, compute, quantity)
+		}
+	}
+
+	for _, series := range timeline.Series {
+		fmt.Printf("\n## %s\n\n", series.Group)
+		fmt.Println("| Time | Utilization | Allocated | Available |")
+		fmt.Println("|------|-------------|-----------|-----------|")
+		for _, point := range series.Points {
+			fmt.Printf("| %s | %.1f%% | %s | %s |\n",
+				point.Time.Local().Format(layout),
+				point.UtilizationPct,
+				formatResources(point.Allocated),
+				formatResources(point.Available),
+			)
+		}
+	}
+}
+
+// printCapacityHistory prints the utilization of each group over time as markdown
+func printCapacityHistory(history *domain.CapacityHistory) {
+	fmt.Printf("# Capacity History by %s\n", history.GroupBy)
+
+	if len(history.Series) == 0 {
+		fmt.Println("\nNo capacity snapshot yet")
+		return
+	}
+
+	for _, series := range history.Series {
+		fmt.Printf("\n## %s\n\n", series.Group)
+		fmt.Println("| Time | Computes | Utilization | Allocated | Total |")
+		fmt.Println("|------|----------|-------------|-----------|-------|")
+		for _, point := range series.Points {
+			fmt.Printf("| %s | %d | %.1f%% | %s | %s |\n",
+				point.Time.Local().Format(time.RFC3339),
+				point.Computes,
+				point.UtilizationPct,
+				formatResources(point.Allocated),
+				formatResources(point.Total),
+			)
+		}
+	}
+}
+
+// printCapacityForecast prints the threshold crossing of each group as markdown
+func printCapacityForecast(forecast *domain.CapacityForecast) {
+	subject := "utilization"
+	if forecast.Resource != "" {
+		subject = forecast.Resource + " utilization"
+	}
+	fmt.Printf("# Capacity Forecast by %s\n\n", forecast.GroupBy)
+	fmt.Printf("When %s reaches %.0f%% (%s, horizon %s)\n\n", subject, forecast.Threshold, forecast.Method, forecast.Horizon)
+
+	if len(forecast.Groups) == 0 {
+		fmt.Println("No capacity snapshot yet")
+		return
+	}
+
+	fmt.Println("| Group | Status | Current | Trend/Day | Crosses At | Snapshots | Details |")
+	fmt.Println("|-------|--------|---------|-----------|------------|-----------|---------|")
+	for _, group := range forecast.Groups {
+		crossesAt := "-"
+		if group.CrossesAt != nil {
+			crossesAt = group.CrossesAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("| %s | %s | %.1f%% | %+.2f | %s | %d | %s |\n",
+			group.Group,
+			group.Status,
+			group.Current,
+			group.TrendPerDay,
+			crossesAt,
+			group.Points,
+			group.Message,
+		)
+	}
+}
+
+// printResilienceReport prints the failure-domain analysis as markdown
+func printResilienceReport(report *domain.ResilienceReport) {
+	fmt.Printf("# Resilience Report: %s\n\n", report.TopologyKey)
+	if report.WhatIf {
+		fmt.Println("What-if scenario: nothing is stored")
+		fmt.Println()
+	}
+	fmt.Printf("Strategy: %s\n", report.Strategy)
+	fmt.Printf("Reservation: %s\n\n", report.Reservation)
+
+	if report.Resilient {
+		fmt.Printf("✓ %s\n\n", report.Message)
+	} else {
+		fmt.Printf("✗ %s\n\n", report.Message)
+	}
+
+	if len(report.Domains) > 0 {
+		fmt.Println("| Domain | Computes | Instances | Survives | Stranded | Shortfall |")
+		fmt.Println("|--------|----------|-----------|----------|----------|-----------|")
+		for _, failureDomain := range report.Domains {
+			names := make([]string, 0, len(failureDomain.Computes))
+			for _, compute := range failureDomain.Computes {
+				names = append(names, compute.Name)
+			}
+			stranded := 0
+			for _, entry := range failureDomain.Stranded {
+				stranded += entry.Quantity
+			}
+			survives := "✓"
+			if !failureDomain.Survives {
+				survives = "✗"
+			}
+			fmt.Printf("| %s | %s | %d | %s | %d | %s |\n",
+				failureDomain.Value,
+				strings.Join(names, ", "),
+				failureDomain.Displaced,
+				survives,
+				stranded,
+				formatResources(failureDomain.Shortfall),
+			)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Services) > 0 {
+		fmt.Println("## Stranded Services")
+		fmt.Println()
+		fmt.Println("| Service | Lost With | Max Stranded |")
+		fmt.Println("|---------|-----------|--------------|")
+		for _, service := range report.Services {
+			fmt.Printf("| %s | %s | %d |\n", service.ServiceName, strings.Join(service.Domains, ", "), service.MaxStranded)
+		}
+		fmt.Println()
+	}
+
+	if len(report.ExtraCapacity) > 0 {
+		fmt.Printf("**Extra capacity needed to be N+1 safe:** %s\n\n", formatResources(report.ExtraCapacity))
+	}
+	if len(report.Recommendations) > 0 {
+		fmt.Println("**Recommendations:**")
+		printRecommendations(report.Recommendations)
+		fmt.Println()
+	}
+
+	if len(report.Unassigned) > 0 {
+		names := make([]string, 0, len(report.Unassigned))
+		for _, compute := range report.Unassigned {
+			names = append(names, compute.Name)
+		}
+		fmt.Printf("Computes without %q (not a failure domain): %s\n", report.TopologyKey, strings.Join(names, ", "))
+	}
+}
+
+// printCapacityReport prints the capacity report as markdown
+func printCapacityReport(report *domain.CapacityReport) {
+	fmt.Println("# Capacity Report")
+	fmt.Println()
+	if report.WhatIf {
+		fmt.Println("What-if scenario: nothing is stored")
+		fmt.Println()
+	}
+	fmt.Printf("- **Computes:** %d (%d active)\n", report.TotalComputes, report.ActiveComputes)
+	fmt.Printf("- **Services:** %d\n", report.TotalServices)
+	fmt.Printf("- **Assignments:** %d\n", report.TotalAssignments)
+	fmt.Printf("- **Reservation:** %s\n", report.Reservation)
+	if report.At != nil {
+		fmt.Printf("- **At:** %s\n", report.At.Local().Format(time.RFC3339))
+	}
+	fmt.Println()
+
+	fmt.Println("| Compute | State | Utilization | Allocated | Available | Raw | Overcommit | Effective |")
+	fmt.Println("|---------|-------|-------------|-----------|-----------|-----|------------|-----------|")
+	for _, util := range report.ComputeUtilization {
+		// Without overcommit the raw capacity is the effective capacity
+		raw := util.RawResources
+		if raw == nil {
+			raw = util.TotalResources
+		}
+		fmt.Printf("| %s | %s | %.0f%% | %s | %s | %s | %s | %s |\n",
+			util.Compute.Name,
+			util.Compute.State,
+			util.UtilizationPct,
+			formatResources(util.Allocated),
+			formatResources(util.Available),
+			formatResources(raw),
+			formatRatios(util.Overcommit),
+			formatResources(util.TotalResources),
+		)
+	}
+
+	// Hypervisors: hosted computes count as allocated on their host
+	hosts := make([]domain.ComputeUtilization, 0)
+	for _, util := range report.ComputeUtilization {
+		if len(util.HostedComputes) > 0 {
+			hosts = append(hosts, util)
+		}
+	}
+	if len(hosts) > 0 {
+		fmt.Println()
+		fmt.Println("## Hosted Computes")
+		fmt.Println()
+		fmt.Println("| Host | Hosted | Carved Out | Reserved In Hosted |")
+		fmt.Println("|------|--------|------------|--------------------|")
+		for _, util := range hosts {
+			fmt.Printf("| %s | %s | %s | %s |\n",
+				util.Compute.Name,
+				strings.Join(util.HostedComputes, ", "),
+				formatResources(util.Hosted),
+				formatResources(util.HostedAllocated),
+			)
+		}
+	}
+
+	// Reservations: held capacity counts as allocated
+	held := make([]domain.ComputeUtilization, 0)
+	for _, util := range report.ComputeUtilization {
+		if len(util.Held) > 0 {
+			held = append(held, util)
+		}
+	}
+	if len(held) > 0 {
+		fmt.Println()
+		fmt.Println("## Held By Reservations")
+		fmt.Println()
+		fmt.Println("| Compute | Held |")
+		fmt.Println("|---------|------|")
+		for _, util := range held {
+			fmt.Printf("| %s | %s |\n", util.Compute.Name, formatResources(util.Held))
+		}
+	}
+
+	// Storage: pools count their volumes as allocated, on top of the storage of assigned services
+	stored := make([]domain.ComputeUtilization, 0)
+	for _, util := range report.ComputeUtilization {
+		if len(util.Storage) > 0 {
+			stored = append(stored, util)
+		}
+	}
+	if len(stored) > 0 {
+		fmt.Println()
+		fmt.Println("## Storage")
+		fmt.Println()
+		fmt.Println("| Compute | Group | Pool | Tier | Redundancy | Capacity | Allocated | Free |")
+		fmt.Println("|---------|-------|------|------|------------|----------|-----------|------|")
+		for _, util := range stored {
+			for _, group := range util.Storage {
+				pool := string(group.Pool)
+				if pool == "" {
+					pool = "-"
+				}
+				fmt.Printf("| %s | %s | %s | %s | %s | %s GB | %s GB | %s GB |\n",
+					util.Compute.Name,
+					group.Name,
+					pool,
+					group.Tier,
+					group.Redundancy,
+					group.Capacity,
+					group.Allocated,
+					group.Available(),
+				)
+			}
+		}
+	}
+}
+
+// formatResources formats resources as sorted key=value pairs
+func formatResources(resources domain.Resources) string {
+	if len(resources) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(resources))
+	for _, key := range sortedResourceKeys(resources) {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, resources[key]))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // Helper to extract float values from component specs with multiple possible keys
@@ -107,7 +787,7 @@ func getSpecFloat(specs map[string]interface{}, keys ...string) float64 {
 	return 0
 }
 
-func printComputeReport(c *client.Client, computeID string, detailedJournal bool) error {
+func printComputeReport(c *client.Client, computeID string, detailedJournal bool, basis domain.ReservationBasis) error {
 	ctx := context.Background()
 
 	// Get compute
@@ -155,6 +835,16 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 	fmt.Printf("**Provider:** %s  \n", compute.Provider)
 	fmt.Printf("**Region:** %s  \n", compute.Region)
 	fmt.Printf("**State:** %s  \n", compute.State)
+	if compute.ParentID != "" {
+		parentName := compute.ParentID
+		if parent, err := c.GetCompute(ctx, compute.ParentID); err == nil {
+			parentName = parent.Name
+		}
+		fmt.Printf("**Parent:** %s  \n", parentName)
+	}
+	if len(compute.Size) > 0 {
+		fmt.Printf("**Size:** %s  \n", formatResources(compute.Size))
+	}
 
 	// Tags
 	if len(compute.Tags) > 0 {
@@ -164,6 +854,15 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 		}
 	}
 
+	// Hosted computes
+	hosted, _ := c.ListComputes(ctx, storage.ComputeFilters{ParentID: computeID})
+	if len(hosted) > 0 {
+		fmt.Printf("\n## Hosted Computes\n\n")
+		for _, child := range hosted {
+			fmt.Printf("- **%s** (%s): %s\n", child.Name, child.Type, formatResources(child.Size))
+		}
+	}
+
 	// Hardware Components
 	if len(components) > 0 {
 		fmt.Printf("\n## Hardware Components\n\n")
@@ -220,10 +919,10 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 
 			fmt.Printf("### %s\n\n", service.Name)
 
-			// Show service max spec (used for planning)
-			if len(service.MaxSpec) > 0 {
-				fmt.Printf("**Max Resources (used for planning):**\n")
-				for k, v := range service.MaxSpec {
+			// Show the reserved spec (used for planning)
+			if reserved := basis.Spec(service); len(reserved) > 0 {
+				fmt.Printf("**Reserved Resources (%s, used for planning):**\n", basis)
+				for k, v := range reserved {
 					fmt.Printf("- %s: %v\n", k, v)
 				}
 				fmt.Println()
@@ -343,9 +1042,14 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 		var totalVRAMGB float64
 		var totalStorageGB float64
 
-		// Group storage by RAID configuration
-		raidGroups := make(map[string][]*storageInfo)
-		var nonRaidStorage []*storageInfo
+		// Storage groups with their usable capacity, as laid out by the server
+		layouts, err := c.GetComputeStorage(ctx, compute.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get storage layout: %w", err)
+		}
+		for _, layout := range layouts {
+			totalStorageGB += layout.Usable.Float()
+		}
 
 		for _, cc := range components {
 			comp, err := c.GetComponent(ctx, cc.ComponentID)
@@ -383,84 +1087,35 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 					}
 					totalVRAMGB += vramValue * float64(cc.Quantity)
 				}
-			case "storage", "nvme", "ssd", "hdd":
-				// Handle storage with RAID grouping
-				storageValue := getSpecFloat(comp.Specs, "size", "capacity_gb", "storage_gb", "capacity")
-				if storageValue > 0 {
-					si := &storageInfo{
-						size:      storageValue,
-						quantity:  cc.Quantity,
-						raidLevel: string(cc.RaidLevel),
-						name:      comp.Name,
-					}
-
-					// Group by RAID configuration
-					if cc.RaidLevel != "" && cc.RaidLevel != "none" && cc.RaidGroup != "" {
-						if raidGroups[cc.RaidGroup] == nil {
-							raidGroups[cc.RaidGroup] = make([]*storageInfo, 0)
-						}
-						raidGroups[cc.RaidGroup] = append(raidGroups[cc.RaidGroup], si)
-					} else {
-						nonRaidStorage = append(nonRaidStorage, si)
-					}
-				}
 			}
 		}
 
-		// Calculate total storage with RAID
-		for _, group := range raidGroups {
-			capacity := calculateRaidCapacity(group)
-			totalStorageGB += capacity
-		}
-		for _, si := range nonRaidStorage {
-			totalStorageGB += si.size * float64(si.quantity)
-		}
-
-		// Calculate allocated resources from assignments using service MaxSpec
+		// Calculate allocated resources from assignments using the reserved service spec
 		var allocatedCores int
 		var allocatedMemoryMB float64
 		var allocatedVRAMMB float64
 		var allocatedStorageGB float64
 
 		for _, assignment := range assignments {
-			// Fetch service to get MaxSpec
+			// Fetch service to get its reserved spec
 			service, err := c.GetService(ctx, assignment.ServiceID)
 			if err != nil {
 				continue // Skip if service not found
 			}
+			reserved := basis.Spec(service)
 
-			if cores, ok := service.MaxSpec["cores"]; ok {
-				switch v := cores.(type) {
-				case int:
-					allocatedCores += v
-				case float64:
-					allocatedCores += int(v)
-				}
-			}
-			if mem, ok := service.MaxSpec["memory"]; ok {
-				switch v := mem.(type) {
-				case int:
-					allocatedMemoryMB += float64(v)
-				case float64:
-					allocatedMemoryMB += v
-				}
-			}
-			if vram, ok := service.MaxSpec["vram"]; ok {
-				switch v := vram.(type) {
-				case int:
-					allocatedVRAMMB += float64(v)
-				case float64:
-					allocatedVRAMMB += v
-				}
-			}
-			if nvme, ok := service.MaxSpec["nvme"]; ok {
-				switch v := nvme.(type) {
-				case int:
-					allocatedStorageGB += float64(v)
-				case float64:
-					allocatedStorageGB += v
-				}
-			}
+			allocatedCores += int(reserved["cores"])
+			allocatedMemoryMB += reserved["memory"].Float()
+			allocatedVRAMMB += reserved["vram"].Float()
+			allocatedStorageGB += reserved["nvme"].Float()
+		}
+
+		// Hosted computes are carved out of this compute
+		for _, child := range hosted {
+			allocatedCores += int(child.Size["cores"])
+			allocatedMemoryMB += child.Size["memory"].Float()
+			allocatedVRAMMB += child.Size["vram"].Float()
+			allocatedStorageGB += child.Size["nvme"].Float()
 		}
 
 		// Convert totals to same units for comparison (MB for memory/vram)
@@ -496,37 +1151,61 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 			fmt.Printf("- **Storage:** %.0f GB (%.1f%% allocated)\n", totalStorageGB, utilPct)
 
 			// Show storage breakdown with RAID info
-			if len(raidGroups) > 0 || len(nonRaidStorage) > 0 {
+			if len(layouts) > 0 {
 				fmt.Printf("\n### Storage Configuration\n\n")
 
-				// Show RAID arrays
-				for groupID, group := range raidGroups {
-					capacity := calculateRaidCapacity(group)
-					raidLevel := group[0].raidLevel
-					diskCount := 0
-					for _, si := range group {
-						diskCount += si.quantity
+				for _, layout := range layouts {
+					if layout.RaidLevel == domain.RaidLevelNone {
+						fmt.Printf("**Non-RAID Storage: %s**\n", layout.Tier)
+					} else {
+						fmt.Printf("**RAID Group: %s (%s)**\n", layout.Group, layout.RaidLevel)
+					}
+					fmt.Printf("- Disks: %d\n", layout.Disks)
+					if layout.Spares > 0 {
+						fmt.Printf("- Hot Spares: %d\n", layout.Spares)
+					}
+					fmt.Printf("- Raw Capacity: %s GB\n", layout.Raw)
+					fmt.Printf("- Effective Capacity: %s GB\n", layout.Usable)
+					if layout.Parity > 0 {
+						fmt.Printf("- Parity Capacity: %s GB\n", layout.Parity)
+					}
+					if layout.Error != "" {
+						fmt.Printf("- **Invalid:** %s\n", layout.Error)
 					}
-					fmt.Printf("**RAID Group: %s (%s)**\n", groupID, raidLevel)
-					fmt.Printf("- Disks: %d\n", diskCount)
-					fmt.Printf("- Effective Capacity: %.0f GB\n", capacity)
 					fmt.Printf("- Components:\n")
-					for _, si := range group {
-						fmt.Printf("  - %dx %s (%.0f GB each)\n", si.quantity, si.name, si.size)
+					for _, member := range layout.Members {
+						role := ""
+						if member.Role == domain.RaidRoleSpare {
+							role = " (hot spare)"
+						}
+						fmt.Printf("  - %dx %s (%s GB each)%s\n", member.Quantity, member.Component, member.Size, role)
 					}
 					fmt.Println()
 				}
+			}
 
-				// Show non-RAID storage
-				if len(nonRaidStorage) > 0 {
-					fmt.Printf("**Non-RAID Storage**\n")
-					total := 0.0
-					for _, si := range nonRaidStorage {
-						capacity := si.size * float64(si.quantity)
-						fmt.Printf("- %dx %s = %.0f GB\n", si.quantity, si.name, capacity)
-						total += capacity
+			// Pools carved out of the RAID groups, with the space their volumes use
+			usages, err := c.GetComputePools(ctx, compute.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get storage pools: %w", err)
+			}
+			if len(usages) > 0 {
+				fmt.Printf("### Storage Pools\n\n")
+
+				for _, usage := range usages {
+					fmt.Printf("**Pool: %s (%s on %s)**\n", usage.Pool.Name, usage.Pool.Type, strings.Join(usage.Pool.RaidGroups, ", "))
+					fmt.Printf("- Capacity: %s GB\n", usage.Capacity)
+					fmt.Printf("- Used: %s GB\n", usage.Used)
+					fmt.Printf("- Free: %s GB\n", usage.Free)
+					if usage.Error != "" {
+						fmt.Printf("- **Invalid:** %s\n", usage.Error)
+					}
+					if len(usage.Volumes) > 0 {
+						fmt.Printf("- Volumes:\n")
+						for _, volume := range usage.Volumes {
+							fmt.Printf("  - %s (%s GB)\n", volume.Name, volume.Size)
+						}
 					}
-					fmt.Printf("- Total: %.0f GB\n", total)
 					fmt.Println()
 				}
 			}
@@ -641,101 +1320,3 @@ func printComputeReport(c *client.Client, computeID string, detailedJournal bool
 
 	return nil
 }
-
-// Calculate RAID capacity based on RAID level
-func calculateRaidCapacity(storage []*storageInfo) float64 {
-	if len(storage) == 0 {
-		return 0
-	}
-
-	raidLevel := storage[0].raidLevel
-
-	// Collect all disk sizes
-	var disks []float64
-	for _, si := range storage {
-		for i := 0; i < si.quantity; i++ {
-			disks = append(disks, si.size)
-		}
-	}
-
-	if len(disks) == 0 {
-		return 0
-	}
-
-	switch raidLevel {
-	case "raid0":
-		// RAID 0: Sum of all disks
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total
-
-	case "raid1":
-		// RAID 1: Size of smallest disk (mirroring)
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return smallest
-
-	case "raid5":
-		// RAID 5: (n-1) * smallest disk
-		if len(disks) < 3 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return float64(len(disks)-1) * smallest
-
-	case "raid6":
-		// RAID 6: (n-2) * smallest disk
-		if len(disks) < 4 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return float64(len(disks)-2) * smallest
-
-	case "raid10":
-		// RAID 10: Sum / 2 (mirrored stripes)
-		if len(disks) < 4 || len(disks)%2 != 0 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total / 2.0
-
-	default:
-		// Unknown RAID level, sum all disks
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total
-	}
-}
@@ -0,0 +1,459 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func newReservationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reservation",
+		Short: "Manage time-bounded capacity reservations",
+		Long: `Manage capacity held for an owner until it expires, e.g. 64 cores in eu for a migration.
+
+A reservation holds its resources on a target compute, or on the active computes matching
+a provider, region and tags (any compute without either). Held resources count as allocated
+for planning, assignments and reports from creation until expiry, including before the start
+date. Expired reservations release their capacity automatically.
+
+Convert a reservation to place service instances in the held capacity: their assignments are
+created and their reserved resources taken out of the reservation.`,
+	}
+
+	cmd.AddCommand(newReservationListCmd())
+	cmd.AddCommand(newReservationGetCmd())
+	cmd.AddCommand(newReservationCreateCmd())
+	cmd.AddCommand(newReservationDeleteCmd())
+	cmd.AddCommand(newReservationConvertCmd())
+
+	return cmd
+}
+
+func newReservationListCmd() *cobra.Command {
+	var (
+		owner      string
+		status     string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List reservations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			reservations, err := c.ListReservations(context.Background(), storage.ReservationFilters{Owner: owner}, status)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(reservations)
+				return nil
+			}
+
+			if len(reservations) == 0 {
+				fmt.Println("No reservations found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tOWNER\tSTATUS\tRESOURCES\tTARGET\tSTARTS\tEXPIRES")
+			for _, reservation := range reservations {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					reservation.Name,
+					reservation.Owner,
+					reservation.Status,
+					formatResources(reservation.Resources),
+					formatReservationTarget(reservation),
+					reservation.StartsAt.Format(time.RFC3339),
+					reservation.ExpiresAt.Format(time.RFC3339),
+				)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&owner, "owner", "", "Filter by owner")
+	cmd.Flags().StringVar(&status, "status", "", "Filter by status (pending, active, expired)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	cmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"pending", "active", "expired"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newReservationGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id|name>",
+		Short: "Get reservation details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			reservation, err := c.ResolveReservation(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			printJSON(reservation)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeReservationNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+func newReservationCreateCmd() *cobra.Command {
+	var (
+		name        string
+		owner       string
+		resources   string
+		computeID   string
+		provider    string
+		region      string
+		tags        string
+		startsAt    string
+		expiresAt   string
+		duration    string
+		description string
+		force       bool
+		reservation string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create or update a reservation",
+		Long: `Create a reservation. A reservation with the same name is updated.
+
+The expiry is given as a date (--expires-at) or as a duration from the start (--for).
+The start defaults to now. Creation fails when the capacity cannot be held, unless
+--force is set, in which case only what is available is held.`,
+		Example: `  # Hold 64 cores and 256 GiB in eu for the Q3 migration
+  kubebuddy reservation create --name q3-migration --owner payments \
+    --resources '{"cores":64,"memory":"256Gi"}' --region eu --starts-at 2026-07-01 --expires-at 2026-10-01
+
+  # Hold 2 GPUs on one compute for two weeks
+  kubebuddy reservation create --name ml-trial --owner research \
+    --resources '{"gpu":2}' --compute gpu-01 --for 14d`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			r := &domain.Reservation{
+				Name:        name,
+				Owner:       owner,
+				Description: description,
+			}
+
+			if err := json.Unmarshal([]byte(resources), &r.Resources); err != nil {
+				return fmt.Errorf("invalid resources JSON: %w", err)
+			}
+
+			if computeID != "" {
+				compute, err := c.ResolveCompute(context.Background(), computeID)
+				if err != nil {
+					return fmt.Errorf("failed to resolve compute: %w", err)
+				}
+				r.ComputeID = compute.ID
+			}
+			if provider != "" || region != "" || tags != "" {
+				r.Selector = &domain.ReservationSelector{
+					Provider: provider,
+					Region:   region,
+					Tags:     parseTags(tags),
+				}
+			}
+
+			if startsAt != "" {
+				start, err := parseTime(startsAt)
+				if err != nil {
+					return fmt.Errorf("invalid --starts-at: %w", err)
+				}
+				r.StartsAt = start
+			}
+
+			switch {
+			case expiresAt != "" && duration != "":
+				return fmt.Errorf("--expires-at and --for are mutually exclusive")
+			case expiresAt != "":
+				expiry, err := parseTime(expiresAt)
+				if err != nil {
+					return fmt.Errorf("invalid --expires-at: %w", err)
+				}
+				r.ExpiresAt = expiry
+			case duration != "":
+				window, err := domain.ParseWindow(duration)
+				if err != nil {
+					return fmt.Errorf("invalid --for: %w", err)
+				}
+				start := r.StartsAt
+				if start.IsZero() {
+					start = time.Now()
+					r.StartsAt = start
+				}
+				r.ExpiresAt = start.Add(window)
+			default:
+				return fmt.Errorf("--expires-at or --for is required")
+			}
+
+			result, err := c.CreateReservation(context.Background(), r, force)
+			if err != nil {
+				return err
+			}
+
+			printJSON(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Reservation name (required, unique)")
+	cmd.Flags().StringVar(&owner, "owner", "", "Team or person holding the capacity (required)")
+	cmd.Flags().StringVar(&resources, "resources", "", "Resources to hold as JSON, numbers or quantities with units (e.g. '{\"cores\":64,\"memory\":\"256Gi\"}') (required)")
+	cmd.Flags().StringVar(&computeID, "compute", "", "Hold the capacity on this compute (ID or name)")
+	cmd.Flags().StringVar(&provider, "provider", "", "Hold the capacity on computes of this provider")
+	cmd.Flags().StringVar(&region, "region", "", "Hold the capacity on computes in this region")
+	cmd.Flags().StringVar(&tags, "tags", "", "Hold the capacity on computes with these tags (key=value,...)")
+	cmd.Flags().StringVar(&startsAt, "starts-at", "", "Start date (RFC3339 or YYYY-MM-DD, default now)")
+	cmd.Flags().StringVar(&expiresAt, "expires-at", "", "Expiry date (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&duration, "for", "", "Duration from the start instead of --expires-at (e.g. 72h, 90d)")
+	cmd.Flags().StringVar(&description, "description", "", "Description")
+	cmd.Flags().BoolVar(&force, "force", false, "Create even if the capacity cannot be fully held")
+	addReservationFlag(cmd, &reservation)
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("resources")
+
+	cmd.RegisterFlagCompletionFunc("compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeProviders(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeRegions(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newReservationDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id|name>",
+		Short: "Delete a reservation, releasing its capacity",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			reservation, err := c.ResolveReservation(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeleteReservation(context.Background(), reservation.ID); err != nil {
+				return err
+			}
+
+			printJSON(map[string]string{"message": "reservation deleted successfully"})
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeReservationNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+func newReservationConvertCmd() *cobra.Command {
+	var (
+		serviceID   string
+		replicas    int
+		strategy    string
+		jsonOutput  bool
+		reservation string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "convert <id|name>",
+		Short: "Place service instances in the capacity held by a reservation",
+		Long: `Plan the instances on the reservation target (its compute, or the computes matching its
+selector) with the capacity of the reservation released, create their assignments and take
+their reserved resources out of the reservation. A reservation with nothing left is deleted.
+
+The instances must fit in the reservation for every resource key it holds. Keys it does not
+hold are taken from free capacity.`,
+		Example: `  kubebuddy reservation convert q3-migration --service postgres-db --replicas 3`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			c := client.New(endpoint, apiKey)
+			c.SetReservation(reservation)
+
+			target, err := c.ResolveReservation(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			service, err := c.ResolveService(ctx, serviceID)
+			if err != nil {
+				return fmt.Errorf("failed to resolve service: %w", err)
+			}
+
+			result, err := c.ConvertReservation(ctx, target.ID, domain.ReservationConversion{
+				ServiceID: service.ID,
+				Replicas:  replicas,
+				Strategy:  strategy,
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(result)
+				return nil
+			}
+
+			fmt.Printf("✓ %s\n\n", result.Message)
+			fmt.Printf("Consumed: %s\n", formatResources(result.Consumed))
+			if result.Reservation != nil {
+				fmt.Printf("Left: %s\n", formatResources(result.Reservation.Resources))
+			}
+			fmt.Println()
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ASSIGNMENT\tCOMPUTE\tQUANTITY")
+			names := make(map[string]string)
+			for _, placement := range result.Plan.Placements {
+				names[placement.Compute.ID] = placement.Compute.Name
+			}
+			for _, assignment := range result.Assignments {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", assignment.ID, names[assignment.ComputeID], assignment.Quantity)
+			}
+			w.Flush()
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeReservationNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().StringVar(&serviceID, "service", "", "Service ID or name (required)")
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "Number of instances to place")
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("Scoring strategy (%s, default %s)", strings.Join(domain.ScoringStrategies(), ", "), domain.DefaultStrategy))
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	addReservationFlag(cmd, &reservation)
+
+	cmd.MarkFlagRequired("service")
+
+	cmd.RegisterFlagCompletionFunc("service", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeServiceIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return domain.ScoringStrategies(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// formatReservationTarget formats the compute or selector a reservation holds capacity on
+func formatReservationTarget(reservation *domain.Reservation) string {
+	if reservation.ComputeID != "" {
+		return "compute " + reservation.ComputeID
+	}
+	if reservation.Selector == nil {
+		return "any"
+	}
+	parts := make([]string, 0)
+	if reservation.Selector.Provider != "" {
+		parts = append(parts, "provider="+reservation.Selector.Provider)
+	}
+	if reservation.Selector.Region != "" {
+		parts = append(parts, "region="+reservation.Selector.Region)
+	}
+	keys := make([]string, 0, len(reservation.Selector.Tags))
+	for key := range reservation.Selector.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"="+reservation.Selector.Tags[key])
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseTime parses an RFC3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func completeReservationNames(toComplete string) []string {
+	if apiKey == "" {
+		return nil
+	}
+
+	c := client.New(endpoint, apiKey)
+	reservations, err := c.ListReservations(context.Background(), storage.ReservationFilters{}, "")
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, reservation := range reservations {
+		completions = append(completions, reservation.Name+"\t"+reservation.Owner+" ("+string(reservation.Status)+")")
+	}
+
+	return completions
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+func newResourceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resource",
+		Short: "Inspect resource keys",
+		Long: `Inspect the resource keys known to the server.
+
+Service specs must use these keys or their aliases. Aliases are rewritten to the
+canonical key on create/update, converting values to the unit of the key
+(e.g. ram_gb=4 is stored as memory=4096).`,
+	}
+
+	cmd.AddCommand(newResourceListCmd())
+
+	return cmd
+}
+
+func newResourceListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List known resource keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			definitions, err := c.ListResources(context.Background())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(definitions)
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tUNIT\tALIASES\tDESCRIPTION\tSOURCE")
+			for _, definition := range definitions {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", definition.Key, definition.Unit, formatAliases(definition.Aliases), definition.Description, definition.Source)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// formatAliases formats aliases as name(unit) pairs
+func formatAliases(aliases []domain.ResourceAlias) string {
+	if len(aliases) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		parts = append(parts, fmt.Sprintf("%s(%s)", alias.Name, alias.Unit))
+	}
+	return strings.Join(parts, ",")
+}
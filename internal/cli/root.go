@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -49,6 +50,8 @@ Environment Variables:
 	rootCmd.AddCommand(newServiceCmd())
 	rootCmd.AddCommand(newAssignmentCmd())
 	rootCmd.AddCommand(newPlanCmd())
+	rootCmd.AddCommand(newPlanStackCmd())
+	rootCmd.AddCommand(newRebalanceCmd())
 	rootCmd.AddCommand(newJournalCmd())
 	rootCmd.AddCommand(newAPIKeyCmd())
 	rootCmd.AddCommand(newComponentCmd())
@@ -56,6 +59,13 @@ Environment Variables:
 	rootCmd.AddCommand(newDNSCmd())
 	rootCmd.AddCommand(newPortCmd())
 	rootCmd.AddCommand(newFirewallCmd())
+	rootCmd.AddCommand(newOvercommitCmd())
+	rootCmd.AddCommand(newResourceCmd())
+	rootCmd.AddCommand(newDerivationCmd())
+	rootCmd.AddCommand(newReservationCmd())
+	rootCmd.AddCommand(newPoolCmd())
+	rootCmd.AddCommand(newVolumeCmd())
+	rootCmd.AddCommand(newSnapshotCmd())
 	rootCmd.AddCommand(newReportCmd())
 
 	return rootCmd
@@ -97,6 +107,23 @@ func parseTags(tagsStr string) map[string]string {
 	return tags
 }
 
+// parseRatios parses overcommit ratios given as key=ratio pairs, comma-separated (e.g., cores=4,memory=1)
+func parseRatios(ratiosStr string) (map[string]float64, error) {
+	ratios := make(map[string]float64)
+	for _, pair := range splitTags(ratiosStr) {
+		kv := splitKeyValue(pair)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid ratio %q (use key=ratio)", pair)
+		}
+		ratio, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ratio for %s: %w", kv[0], err)
+		}
+		ratios[kv[0]] = ratio
+	}
+	return ratios, nil
+}
+
 func splitTags(s string) []string {
 	var parts []string
 	var current string
@@ -84,6 +84,7 @@ func newServiceCreateCmd() *cobra.Command {
 		minSpec   string
 		maxSpec   string
 		placement string
+		storage   string
 	)
 
 	cmd := &cobra.Command{
@@ -123,6 +124,13 @@ func newServiceCreateCmd() *cobra.Command {
 				}
 			}
 
+			// Parse storage requirements JSON
+			if storage != "" {
+				if err := json.Unmarshal([]byte(storage), &service.Storage); err != nil {
+					return fmt.Errorf("invalid storage JSON: %w", err)
+				}
+			}
+
 			c := client.New(endpoint, apiKey)
 			result, err := c.CreateService(context.Background(), service)
 			if err != nil {
@@ -135,9 +143,10 @@ func newServiceCreateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&name, "name", "", "Service name (required)")
-	cmd.Flags().StringVar(&minSpec, "min-spec", "", "Minimum resource spec as JSON (e.g. '{\"cpu\":2,\"ram_gb\":4}')")
-	cmd.Flags().StringVar(&maxSpec, "max-spec", "", "Maximum resource spec as JSON (e.g. '{\"cpu\":8,\"ram_gb\":16}')")
+	cmd.Flags().StringVar(&minSpec, "min-spec", "", "Minimum resource spec as JSON, numbers or quantities with units (e.g. '{\"cores\":2,\"memory\":\"4Gi\"}')")
+	cmd.Flags().StringVar(&maxSpec, "max-spec", "", "Maximum resource spec as JSON, numbers or quantities with units (e.g. '{\"cores\":8,\"memory\":\"16Gi\"}')")
 	cmd.Flags().StringVar(&placement, "placement", "", "Placement rules as JSON")
+	cmd.Flags().StringVar(&storage, "storage", "", "Storage requirements per instance as JSON, optionally named to be provided by volumes (e.g. '[{\"name\":\"data\",\"tier\":\"nvme\",\"size\":\"200GB\",\"redundancy\":\"mirrored\"}]')")
 	cmd.MarkFlagRequired("name")
 
 	return cmd
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage capacity snapshots",
+		Long: `Manage the capacity snapshots the server persists to follow utilization over time.
+
+The server takes a snapshot of the total, allocated and available resources of every compute
+at a regular interval (--snapshot-interval, default 1h) and keeps them for --snapshot-retention
+(default 90d). Use 'report history' and 'report forecast' to read the trends.`,
+	}
+
+	cmd.AddCommand(newSnapshotTakeCmd())
+	cmd.AddCommand(newSnapshotListCmd())
+	cmd.AddCommand(newSnapshotGetCmd())
+
+	return cmd
+}
+
+func newSnapshotTakeCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "take",
+		Short: "Take a capacity snapshot now",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			snapshot, err := c.TakeSnapshot(context.Background())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(snapshot)
+				return nil
+			}
+
+			fmt.Printf("Snapshot taken: %s (%d computes)\n", snapshot.ID, snapshot.Computes)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	var (
+		since      string
+		limit      int
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List capacity snapshots",
+		Example: `  kubebuddy snapshot list
+  kubebuddy snapshot list --since 7d`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			from, err := sinceTime(since)
+			if err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			snapshots, err := c.ListSnapshots(context.Background(), storage.SnapshotFilters{From: from, Limit: limit})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(snapshots)
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTAKEN AT\tRESERVATION\tCOMPUTES")
+			for _, snapshot := range snapshots {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\n",
+					snapshot.ID,
+					snapshot.TakenAt.Local().Format(time.RFC3339),
+					snapshot.Reservation,
+					snapshot.Computes,
+				)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only list snapshots taken within this window (e.g. 72h, 30d)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Only list the most recent snapshots")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newSnapshotGetCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "get [id]",
+		Short: "Show the compute samples of a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			snapshot, err := c.GetSnapshot(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(snapshot)
+				return nil
+			}
+
+			fmt.Printf("Snapshot %s taken at %s (reservation %s)\n\n", snapshot.ID, snapshot.TakenAt.Local().Format(time.RFC3339), snapshot.Reservation)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "COMPUTE\tPROVIDER\tREGION\tSTATE\tUTILIZATION\tALLOCATED\tTOTAL")
+			for _, sample := range snapshot.Samples {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f%%\t%s\t%s\n",
+					sample.ComputeName,
+					sample.Provider,
+					sample.Region,
+					sample.State,
+					sample.UtilizationPct,
+					formatResources(sample.Allocated),
+					formatResources(sample.Total),
+				)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// sinceTime returns the start of a window ending now (e.g. "30d"), nil when the window is empty
+func sinceTime(since string) (*time.Time, error) {
+	if since == "" {
+		return nil, nil
+	}
+	window, err := domain.ParseWindow(since)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Now().Add(-window)
+	return &from, nil
+}
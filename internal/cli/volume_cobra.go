@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/studiowebux/kubebuddy/internal/client"
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+func newVolumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volume",
+		Short: "Manage volumes carved out of storage pools",
+		Long: `Manage volumes (ZFS datasets or zvols, LVM logical volumes) allocated in a storage pool.
+
+A volume provisioned for a service names the storage requirement it provides. An instance of
+the service placed on the compute of the pool uses the volume instead of allocating the
+requirement again; the volume is already counted in the pool.`,
+	}
+
+	cmd.AddCommand(newVolumeListCmd())
+	cmd.AddCommand(newVolumeGetCmd())
+	cmd.AddCommand(newVolumeCreateCmd())
+	cmd.AddCommand(newVolumeDeleteCmd())
+
+	return cmd
+}
+
+func newVolumeListCmd() *cobra.Command {
+	var (
+		poolID     string
+		computeID  string
+		serviceID  string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List volumes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			c := client.New(endpoint, apiKey)
+
+			filters := storage.VolumeFilters{}
+			if poolID != "" {
+				pool, err := resolvePool(c, poolID, computeID)
+				if err != nil {
+					return err
+				}
+				filters.PoolID = pool.ID
+			}
+			if serviceID != "" {
+				service, err := c.ResolveService(ctx, serviceID)
+				if err != nil {
+					return fmt.Errorf("failed to resolve service: %w", err)
+				}
+				filters.ServiceID = service.ID
+			}
+
+			volumes, err := c.ListVolumes(ctx, filters)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				printJSON(volumes)
+				return nil
+			}
+
+			if len(volumes) == 0 {
+				fmt.Println("No volumes found")
+				return nil
+			}
+
+			pools, err := c.ListPools(ctx, storage.PoolFilters{})
+			if err != nil {
+				return err
+			}
+			poolNames := make(map[string]string, len(pools))
+			for _, pool := range pools {
+				poolNames[pool.ID] = pool.Name
+			}
+			services, err := c.ListServices(ctx)
+			if err != nil {
+				return err
+			}
+			serviceNames := make(map[string]string, len(services))
+			for _, service := range services {
+				serviceNames[service.ID] = service.Name
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tPOOL\tSIZE\tSERVICE\tREQUIREMENT")
+			for _, volume := range volumes {
+				service, requirement := "-", "-"
+				if volume.ServiceID != "" {
+					service = serviceNames[volume.ServiceID]
+				}
+				if volume.Requirement != "" {
+					requirement = volume.Requirement
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s GB\t%s\t%s\n",
+					volume.Name,
+					poolNames[volume.PoolID],
+					volume.Size,
+					service,
+					requirement,
+				)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&poolID, "pool", "", "Filter by pool (ID or name)")
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute of the pool (ID or name), when several computes have a pool of that name")
+	cmd.Flags().StringVar(&serviceID, "service", "", "Filter by service (ID or name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	cmd.RegisterFlagCompletionFunc("pool", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completePoolNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("service", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeServiceIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newVolumeGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id|name>",
+		Short: "Get volume details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+			volume, err := c.ResolveVolume(context.Background(), args[0], "")
+			if err != nil {
+				return err
+			}
+
+			printJSON(volume)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeVolumeNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	return cmd
+}
+
+func newVolumeCreateCmd() *cobra.Command {
+	var (
+		name        string
+		poolID      string
+		computeID   string
+		size        string
+		serviceID   string
+		requirement string
+		description string
+		force       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create or update a volume in a pool",
+		Long: `Create a volume in a pool. A volume with the same name in the pool is updated.
+
+The volume must fit in the free space of the pool, unless --force is set. A volume
+provisioned for a service with --requirement must be at least as large as the named storage
+requirement of the service.`,
+		Example: `  # Volume for the "data" storage requirement of postgres-db
+  kubebuddy volume create --name pgdata --pool tank --size 500GB \
+    --service postgres-db --requirement data
+
+  # Volume not tied to a service, e.g. backups
+  kubebuddy volume create --name backups --pool tank --size 2TB`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			c := client.New(endpoint, apiKey)
+
+			pool, err := resolvePool(c, poolID, computeID)
+			if err != nil {
+				return err
+			}
+
+			volumeSize, err := domain.ParseQuantity("storage", size)
+			if err != nil {
+				return fmt.Errorf("invalid --size: %w", err)
+			}
+
+			volume := &domain.Volume{
+				Name:        name,
+				PoolID:      pool.ID,
+				Size:        volumeSize,
+				Requirement: requirement,
+				Description: description,
+			}
+
+			if serviceID != "" {
+				service, err := c.ResolveService(ctx, serviceID)
+				if err != nil {
+					return fmt.Errorf("failed to resolve service: %w", err)
+				}
+				volume.ServiceID = service.ID
+			}
+
+			result, err := c.CreateVolume(ctx, volume, force)
+			if err != nil {
+				return err
+			}
+
+			printJSON(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Volume name, unique per pool (required)")
+	cmd.Flags().StringVar(&poolID, "pool", "", "Pool ID or name (required)")
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute of the pool (ID or name), when several computes have a pool of that name")
+	cmd.Flags().StringVar(&size, "size", "", "Size in GB or with a unit, e.g. 500GB or 2TB (required)")
+	cmd.Flags().StringVar(&serviceID, "service", "", "Service the volume is provisioned for (ID or name)")
+	cmd.Flags().StringVar(&requirement, "requirement", "", "Name of the storage requirement of the service the volume provides")
+	cmd.Flags().StringVar(&description, "description", "", "Description")
+	cmd.Flags().BoolVar(&force, "force", false, "Create even if the volume does not fit in the free space of the pool")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("pool")
+	cmd.MarkFlagRequired("size")
+
+	cmd.RegisterFlagCompletionFunc("pool", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completePoolNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("compute", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeComputeIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("service", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeServiceIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newVolumeDeleteCmd() *cobra.Command {
+	var (
+		poolID    string
+		computeID string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete <id|name>",
+		Short: "Delete a volume, freeing its space in the pool",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAPIKey(cmd); err != nil {
+				return err
+			}
+
+			c := client.New(endpoint, apiKey)
+
+			pool := ""
+			if poolID != "" {
+				resolved, err := resolvePool(c, poolID, computeID)
+				if err != nil {
+					return err
+				}
+				pool = resolved.ID
+			}
+
+			volume, err := c.ResolveVolume(context.Background(), args[0], pool)
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeleteVolume(context.Background(), volume.ID); err != nil {
+				return err
+			}
+
+			printJSON(map[string]string{"message": "volume deleted successfully"})
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeVolumeNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().StringVar(&poolID, "pool", "", "Pool of the volume (ID or name), when several pools have a volume of that name")
+	cmd.Flags().StringVar(&computeID, "compute", "", "Compute of the pool (ID or name)")
+
+	return cmd
+}
+
+func completeVolumeNames(toComplete string) []string {
+	if apiKey == "" {
+		return nil
+	}
+
+	c := client.New(endpoint, apiKey)
+	volumes, err := c.ListVolumes(context.Background(), storage.VolumeFilters{})
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, volume := range volumes {
+		completions = append(completions, volume.Name+"\t"+volume.Size.String()+" GB")
+	}
+
+	return completions
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,9 +17,10 @@ import (
 
 // Client is the HTTP client for KubeBuddy API
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	reservation string
+	httpClient  *http.Client
 }
 
 // New creates a new API client
@@ -32,6 +34,24 @@ func New(baseURL, apiKey string) *Client {
 	}
 }
 
+// SetReservation selects the reservation basis (min, max or pNN) sent with planning,
+// admission and report requests. An empty basis uses the server default.
+func (c *Client) SetReservation(basis string) {
+	c.reservation = basis
+}
+
+// withReservation appends the reservation basis to a request path
+func (c *Client) withReservation(path string) string {
+	if c.reservation == "" {
+		return path
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "reservation=" + c.reservation
+}
+
 // doRequest performs an HTTP request
 func (c *Client) doRequest(ctx context.Context, method, path string, body, result interface{}) error {
 	var reqBody io.Reader
@@ -61,6 +81,9 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body, resul
 		var errResp map[string]interface{}
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
 			if errMsg, ok := errResp["error"].(string); ok {
+				if details, ok := errResp["details"].(string); ok && details != "" {
+					return fmt.Errorf("API error (%d): %s: %s", resp.StatusCode, errMsg, details)
+				}
 				return fmt.Errorf("API error (%d): %s", resp.StatusCode, errMsg)
 			}
 		}
@@ -80,11 +103,53 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body, resul
 func (c *Client) ListComputes(ctx context.Context, filters storage.ComputeFilters) ([]*domain.Compute, error) {
 	var computes []*domain.Compute
 	path := "/api/computes"
-	// TODO: Add query parameters for filters
+	if filters.ParentID != "" {
+		path += "?parent_id=" + filters.ParentID
+	}
+	// TODO: Add query parameters for the other filters
 	err := c.doRequest(ctx, http.MethodGet, path, nil, &computes)
 	return computes, err
 }
 
+// ListComputesBySelector lists the computes whose tags and hardware facts match the selectors
+// (e.g. "generation>3,env=prod" and "cpu.manufacturer=AMD"); empty selectors match every compute
+func (c *Client) ListComputesBySelector(ctx context.Context, filters storage.ComputeFilters, tags, hardware string) ([]*domain.Compute, error) {
+	var computes []*domain.Compute
+	query := url.Values{}
+	if tags != "" {
+		query.Set("tags", tags)
+	}
+	if hardware != "" {
+		query.Set("hardware", hardware)
+	}
+	if filters.ParentID != "" {
+		query.Set("parent_id", filters.ParentID)
+	}
+	err := c.doRequest(ctx, http.MethodGet, "/api/computes?"+query.Encode(), nil, &computes)
+	return computes, err
+}
+
+// GetComputeHardware returns the hardware facts derived from the components of a compute
+func (c *Client) GetComputeHardware(ctx context.Context, id string) (domain.HardwareFacts, error) {
+	var facts domain.HardwareFacts
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/computes/%s/hardware", id), nil, &facts)
+	return facts, err
+}
+
+// GetComputeStorage returns the layout of the storage groups of a compute
+func (c *Client) GetComputeStorage(ctx context.Context, id string) ([]domain.StorageLayout, error) {
+	var layouts []domain.StorageLayout
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/computes/%s/storage", id), nil, &layouts)
+	return layouts, err
+}
+
+// GetComputePools returns the capacity and free space of the storage pools of a compute
+func (c *Client) GetComputePools(ctx context.Context, id string) ([]domain.PoolUsage, error) {
+	var usages []domain.PoolUsage
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/computes/%s/pools", id), nil, &usages)
+	return usages, err
+}
+
 func (c *Client) GetCompute(ctx context.Context, id string) (*domain.Compute, error) {
 	var compute domain.Compute
 	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/computes/%s", id), nil, &compute)
@@ -112,15 +177,23 @@ func (c *Client) ResolveCompute(ctx context.Context, idOrName string) (*domain.C
 	return c.GetComputeByName(ctx, idOrName)
 }
 
-func (c *Client) CreateCompute(ctx context.Context, compute *domain.Compute) (*domain.Compute, error) {
+func (c *Client) CreateCompute(ctx context.Context, compute *domain.Compute, force bool) (*domain.Compute, error) {
 	var result domain.Compute
-	err := c.doRequest(ctx, http.MethodPost, "/api/computes", compute, &result)
+	path := "/api/computes"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), compute, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateCompute(ctx context.Context, id string, compute *domain.Compute) (*domain.Compute, error) {
+func (c *Client) UpdateCompute(ctx context.Context, id string, compute *domain.Compute, force bool) (*domain.Compute, error) {
 	var result domain.Compute
-	err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/api/computes/%s", id), compute, &result)
+	path := fmt.Sprintf("/api/computes/%s", id)
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPut, c.withReservation(path), compute, &result)
 	return &result, err
 }
 
@@ -128,6 +201,28 @@ func (c *Client) DeleteCompute(ctx context.Context, id string) error {
 	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/computes/%s", id), nil, nil)
 }
 
+// PlanDrain returns the rehoming plan for every assignment on a compute
+func (c *Client) PlanDrain(ctx context.Context, id, strategy string) (*domain.DrainPlan, error) {
+	var result domain.DrainPlan
+	path := fmt.Sprintf("/api/computes/%s/drain", id)
+	if strategy != "" {
+		path += "?strategy=" + strategy
+	}
+	err := c.doRequest(ctx, http.MethodGet, c.withReservation(path), nil, &result)
+	return &result, err
+}
+
+// ExecuteDrain moves the assignments of a compute to their planned targets
+func (c *Client) ExecuteDrain(ctx context.Context, id, strategy string, force bool) (*domain.DrainPlan, error) {
+	var result domain.DrainPlan
+	path := fmt.Sprintf("/api/computes/%s/drain?strategy=%s", id, strategy)
+	if force {
+		path += "&force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), nil, &result)
+	return &result, err
+}
+
 // Service methods
 func (c *Client) ListServices(ctx context.Context) ([]*domain.Service, error) {
 	var services []*domain.Service
@@ -197,7 +292,7 @@ func (c *Client) CreateAssignment(ctx context.Context, assignment *domain.Assign
 	if force {
 		path += "?force=true"
 	}
-	err := c.doRequest(ctx, http.MethodPost, path, assignment, &result)
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), assignment, &result)
 	return &result, err
 }
 
@@ -208,7 +303,89 @@ func (c *Client) DeleteAssignment(ctx context.Context, id string) error {
 // Capacity planning methods
 func (c *Client) PlanCapacity(ctx context.Context, request domain.PlanRequest) (*domain.PlanResult, error) {
 	var result domain.PlanResult
-	err := c.doRequest(ctx, http.MethodPost, "/api/capacity/plan", request, &result)
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation("/api/capacity/plan"), request, &result)
+	return &result, err
+}
+
+// ApplyPlan plans a service and creates or updates the assignments of its placements in one
+// transaction
+func (c *Client) ApplyPlan(ctx context.Context, request domain.PlanRequest, force bool) (*domain.PlanResult, error) {
+	var result domain.PlanResult
+	path := "/api/capacity/plan/apply"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), request, &result)
+	return &result, err
+}
+
+// Rebalance returns rebalancing proposals over the current assignments
+func (c *Client) Rebalance(ctx context.Context, request domain.RebalanceRequest) (*domain.RebalancePlan, error) {
+	var result domain.RebalancePlan
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation("/api/capacity/rebalance"), request, &result)
+	return &result, err
+}
+
+// ApplyRebalance applies one rebalancing proposal as a single batch
+func (c *Client) ApplyRebalance(ctx context.Context, request domain.RebalanceRequest, proposal int) (*domain.RebalancePlan, error) {
+	var result domain.RebalancePlan
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(fmt.Sprintf("/api/capacity/rebalance/apply?proposal=%d", proposal)), request, &result)
+	return &result, err
+}
+
+// CapacityReport returns the capacity report, calculated on a hypothetical scenario when whatIf is set
+// CapacityReport returns the utilization of every compute, with the assignments running at the
+// instant when at is set (default now)
+func (c *Client) CapacityReport(ctx context.Context, whatIf *domain.WhatIf, at *time.Time) (*domain.CapacityReport, error) {
+	var result domain.CapacityReport
+	var err error
+	path := "/api/capacity/report"
+	if at != nil {
+		path += "?at=" + url.QueryEscape(at.UTC().Format(time.RFC3339))
+	}
+	if whatIf != nil {
+		err = c.doRequest(ctx, http.MethodPost, c.withReservation(path), whatIf, &result)
+	} else {
+		err = c.doRequest(ctx, http.MethodGet, c.withReservation(path), nil, &result)
+	}
+	return &result, err
+}
+
+// CapacityTimeline returns the utilization at every scheduled change between from (default now)
+// and from plus the window (e.g. "90d", default 90 days)
+func (c *Client) CapacityTimeline(ctx context.Context, groupBy string, from *time.Time, within string) (*domain.CapacityTimeline, error) {
+	var result domain.CapacityTimeline
+	query := snapshotQuery(from, nil)
+	if groupBy != "" {
+		query.Set("group_by", groupBy)
+	}
+	if within != "" {
+		query.Set("within", within)
+	}
+	err := c.doRequest(ctx, http.MethodGet, c.withReservation("/api/capacity/timeline?"+query.Encode()), nil, &result)
+	return &result, err
+}
+
+// ResilienceReport simulates the loss of each failure domain and reports stranded services
+func (c *Client) ResilienceReport(ctx context.Context, request domain.ResilienceRequest) (*domain.ResilienceReport, error) {
+	var result domain.ResilienceReport
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation("/api/capacity/resilience"), request, &result)
+	return &result, err
+}
+
+func (c *Client) PlanStack(ctx context.Context, request domain.StackPlanRequest) (*domain.StackPlanResult, error) {
+	var result domain.StackPlanResult
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation("/api/capacity/plan-stack"), request, &result)
+	return &result, err
+}
+
+func (c *Client) ApplyStack(ctx context.Context, request domain.StackPlanRequest, force bool) (*domain.StackPlanResult, error) {
+	var result domain.StackPlanResult
+	path := "/api/capacity/plan-stack/apply"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), request, &result)
 	return &result, err
 }
 
@@ -313,14 +490,26 @@ func (c *Client) DeleteComponent(ctx context.Context, id string) error {
 }
 
 // Component assignment methods
-func (c *Client) AssignComponent(ctx context.Context, assignment *domain.ComputeComponent) (*domain.ComputeComponent, error) {
+// AssignComponent assigns a component to a compute. RAID groups that cannot be built are
+// rejected unless force is set.
+func (c *Client) AssignComponent(ctx context.Context, assignment *domain.ComputeComponent, force bool) (*domain.ComputeComponent, error) {
 	var result domain.ComputeComponent
-	err := c.doRequest(ctx, http.MethodPost, "/api/component-assignments", assignment, &result)
+	path := "/api/component-assignments"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, path, assignment, &result)
 	return &result, err
 }
 
-func (c *Client) UnassignComponent(ctx context.Context, id string) error {
-	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/component-assignments/%s", id), nil, nil)
+// UnassignComponent removes a component from a compute. Removing disks a storage pool is built
+// on is rejected unless force is set.
+func (c *Client) UnassignComponent(ctx context.Context, id string, force bool) error {
+	path := fmt.Sprintf("/api/component-assignments/%s", id)
+	if force {
+		path += "?force=true"
+	}
+	return c.doRequest(ctx, http.MethodDelete, path, nil, nil)
 }
 
 func (c *Client) ListComponentAssignments(ctx context.Context, filters storage.ComputeComponentFilters) ([]*domain.ComputeComponent, error) {
@@ -525,3 +714,394 @@ func (c *Client) AssignFirewallRule(ctx context.Context, assignment *domain.Comp
 func (c *Client) UnassignFirewallRule(ctx context.Context, id string) error {
 	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/firewall-assignments/%s", id), nil, nil)
 }
+
+// Resource methods
+func (c *Client) ListResources(ctx context.Context) ([]domain.ResourceDefinition, error) {
+	var definitions []domain.ResourceDefinition
+	err := c.doRequest(ctx, http.MethodGet, "/api/resources", nil, &definitions)
+	return definitions, err
+}
+
+// Overcommit policy methods
+func (c *Client) ListOvercommitPolicies(ctx context.Context) ([]*domain.OvercommitPolicy, error) {
+	var policies []*domain.OvercommitPolicy
+	err := c.doRequest(ctx, http.MethodGet, "/api/overcommit-policies", nil, &policies)
+	return policies, err
+}
+
+func (c *Client) GetOvercommitPolicy(ctx context.Context, id string) (*domain.OvercommitPolicy, error) {
+	var policy domain.OvercommitPolicy
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/overcommit-policies/%s", id), nil, &policy)
+	return &policy, err
+}
+
+// ResolveOvercommitPolicy gets an overcommit policy by ID or name
+func (c *Client) ResolveOvercommitPolicy(ctx context.Context, idOrName string) (*domain.OvercommitPolicy, error) {
+	policies, err := c.ListOvercommitPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.ID == idOrName || policy.Name == idOrName {
+			return policy, nil
+		}
+	}
+	return nil, fmt.Errorf("overcommit policy not found: %s", idOrName)
+}
+
+func (c *Client) CreateOvercommitPolicy(ctx context.Context, policy *domain.OvercommitPolicy) (*domain.OvercommitPolicy, error) {
+	var result domain.OvercommitPolicy
+	err := c.doRequest(ctx, http.MethodPost, "/api/overcommit-policies", policy, &result)
+	return &result, err
+}
+
+func (c *Client) UpdateOvercommitPolicy(ctx context.Context, id string, policy *domain.OvercommitPolicy) (*domain.OvercommitPolicy, error) {
+	var result domain.OvercommitPolicy
+	err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/api/overcommit-policies/%s", id), policy, &result)
+	return &result, err
+}
+
+func (c *Client) DeleteOvercommitPolicy(ctx context.Context, id string) error {
+	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/overcommit-policies/%s", id), nil, nil)
+}
+
+
+// Derivation rule methods
+func (c *Client) ListDerivationRules(ctx context.Context) ([]*domain.DerivationRule, error) {
+	var rules []*domain.DerivationRule
+	err := c.doRequest(ctx, http.MethodGet, "/api/derivation-rules", nil, &rules)
+	return rules, err
+}
+
+func (c *Client) GetDerivationRule(ctx context.Context, id string) (*domain.DerivationRule, error) {
+	var rule domain.DerivationRule
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/derivation-rules/%s", id), nil, &rule)
+	return &rule, err
+}
+
+// ResolveDerivationRule gets a derivation rule by ID or name
+func (c *Client) ResolveDerivationRule(ctx context.Context, idOrName string) (*domain.DerivationRule, error) {
+	rules, err := c.ListDerivationRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.ID == idOrName || rule.Name == idOrName {
+			return rule, nil
+		}
+	}
+	return nil, fmt.Errorf("derivation rule not found: %s", idOrName)
+}
+
+func (c *Client) CreateDerivationRule(ctx context.Context, rule *domain.DerivationRule) (*domain.DerivationRule, error) {
+	var result domain.DerivationRule
+	err := c.doRequest(ctx, http.MethodPost, "/api/derivation-rules", rule, &result)
+	return &result, err
+}
+
+func (c *Client) UpdateDerivationRule(ctx context.Context, id string, rule *domain.DerivationRule) (*domain.DerivationRule, error) {
+	var result domain.DerivationRule
+	err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/api/derivation-rules/%s", id), rule, &result)
+	return &result, err
+}
+
+func (c *Client) DeleteDerivationRule(ctx context.Context, id string) error {
+	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/derivation-rules/%s", id), nil, nil)
+}
+
+// Reservation methods
+func (c *Client) ListReservations(ctx context.Context, filters storage.ReservationFilters, status string) ([]*domain.Reservation, error) {
+	var reservations []*domain.Reservation
+	query := url.Values{}
+	if filters.Owner != "" {
+		query.Set("owner", filters.Owner)
+	}
+	if filters.ComputeID != "" {
+		query.Set("compute_id", filters.ComputeID)
+	}
+	if status != "" {
+		query.Set("status", status)
+	}
+	path := "/api/reservations"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	err := c.doRequest(ctx, http.MethodGet, path, nil, &reservations)
+	return reservations, err
+}
+
+func (c *Client) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/reservations/%s", id), nil, &reservation)
+	return &reservation, err
+}
+
+// ResolveReservation gets a reservation by ID or name
+func (c *Client) ResolveReservation(ctx context.Context, idOrName string) (*domain.Reservation, error) {
+	reservations, err := c.ListReservations(ctx, storage.ReservationFilters{}, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, reservation := range reservations {
+		if reservation.ID == idOrName || reservation.Name == idOrName {
+			return reservation, nil
+		}
+	}
+	return nil, fmt.Errorf("reservation not found: %s", idOrName)
+}
+
+func (c *Client) CreateReservation(ctx context.Context, reservation *domain.Reservation, force bool) (*domain.Reservation, error) {
+	var result domain.Reservation
+	path := "/api/reservations"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(path), reservation, &result)
+	return &result, err
+}
+
+func (c *Client) UpdateReservation(ctx context.Context, id string, reservation *domain.Reservation, force bool) (*domain.Reservation, error) {
+	var result domain.Reservation
+	path := fmt.Sprintf("/api/reservations/%s", id)
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPut, c.withReservation(path), reservation, &result)
+	return &result, err
+}
+
+func (c *Client) DeleteReservation(ctx context.Context, id string) error {
+	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/reservations/%s", id), nil, nil)
+}
+
+// ConvertReservation places instances of a service in the capacity held by a reservation
+func (c *Client) ConvertReservation(ctx context.Context, id string, conversion domain.ReservationConversion) (*domain.ConversionResult, error) {
+	var result domain.ConversionResult
+	err := c.doRequest(ctx, http.MethodPost, c.withReservation(fmt.Sprintf("/api/reservations/%s/convert", id)), conversion, &result)
+	return &result, err
+}
+
+// ExpiringReservations returns the reservations expiring within the window (e.g. "72h" or "14d")
+func (c *Client) ExpiringReservations(ctx context.Context, within string) ([]domain.ExpiringReservation, error) {
+	var result []domain.ExpiringReservation
+	path := "/api/reports/reservations"
+	if within != "" {
+		path += "?within=" + url.QueryEscape(within)
+	}
+	err := c.doRequest(ctx, http.MethodGet, c.withReservation(path), nil, &result)
+	return result, err
+}
+
+// Storage pool methods
+func (c *Client) ListPools(ctx context.Context, filters storage.PoolFilters) ([]*domain.Pool, error) {
+	var pools []*domain.Pool
+	path := "/api/pools"
+	if filters.ComputeID != "" {
+		path += "?compute_id=" + url.QueryEscape(filters.ComputeID)
+	}
+	err := c.doRequest(ctx, http.MethodGet, path, nil, &pools)
+	return pools, err
+}
+
+func (c *Client) GetPool(ctx context.Context, id string) (*domain.Pool, error) {
+	var pool domain.Pool
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/pools/%s", id), nil, &pool)
+	return &pool, err
+}
+
+// ResolvePool gets a pool by ID or name; names are unique per compute, so a computeID narrows
+// the search
+func (c *Client) ResolvePool(ctx context.Context, idOrName, computeID string) (*domain.Pool, error) {
+	pools, err := c.ListPools(ctx, storage.PoolFilters{ComputeID: computeID})
+	if err != nil {
+		return nil, err
+	}
+	var found *domain.Pool
+	for _, pool := range pools {
+		if pool.ID == idOrName {
+			return pool, nil
+		}
+		if pool.Name == idOrName {
+			if found != nil {
+				return nil, fmt.Errorf("several computes have a pool named %s, set the compute", idOrName)
+			}
+			found = pool
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("pool not found: %s", idOrName)
+	}
+	return found, nil
+}
+
+func (c *Client) CreatePool(ctx context.Context, pool *domain.Pool) (*domain.Pool, error) {
+	var result domain.Pool
+	err := c.doRequest(ctx, http.MethodPost, "/api/pools", pool, &result)
+	return &result, err
+}
+
+func (c *Client) UpdatePool(ctx context.Context, id string, pool *domain.Pool) (*domain.Pool, error) {
+	var result domain.Pool
+	err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/api/pools/%s", id), pool, &result)
+	return &result, err
+}
+
+func (c *Client) DeletePool(ctx context.Context, id string) error {
+	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/pools/%s", id), nil, nil)
+}
+
+// Volume methods
+func (c *Client) ListVolumes(ctx context.Context, filters storage.VolumeFilters) ([]*domain.Volume, error) {
+	var volumes []*domain.Volume
+	query := url.Values{}
+	if filters.PoolID != "" {
+		query.Set("pool_id", filters.PoolID)
+	}
+	if filters.ServiceID != "" {
+		query.Set("service_id", filters.ServiceID)
+	}
+	path := "/api/volumes"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	err := c.doRequest(ctx, http.MethodGet, path, nil, &volumes)
+	return volumes, err
+}
+
+func (c *Client) GetVolume(ctx context.Context, id string) (*domain.Volume, error) {
+	var volume domain.Volume
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/volumes/%s", id), nil, &volume)
+	return &volume, err
+}
+
+// ResolveVolume gets a volume by ID or name; names are unique per pool, so a poolID narrows the
+// search
+func (c *Client) ResolveVolume(ctx context.Context, idOrName, poolID string) (*domain.Volume, error) {
+	volumes, err := c.ListVolumes(ctx, storage.VolumeFilters{PoolID: poolID})
+	if err != nil {
+		return nil, err
+	}
+	var found *domain.Volume
+	for _, volume := range volumes {
+		if volume.ID == idOrName {
+			return volume, nil
+		}
+		if volume.Name == idOrName {
+			if found != nil {
+				return nil, fmt.Errorf("several pools have a volume named %s, set the pool", idOrName)
+			}
+			found = volume
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("volume not found: %s", idOrName)
+	}
+	return found, nil
+}
+
+func (c *Client) CreateVolume(ctx context.Context, volume *domain.Volume, force bool) (*domain.Volume, error) {
+	var result domain.Volume
+	path := "/api/volumes"
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPost, path, volume, &result)
+	return &result, err
+}
+
+func (c *Client) UpdateVolume(ctx context.Context, id string, volume *domain.Volume, force bool) (*domain.Volume, error) {
+	var result domain.Volume
+	path := fmt.Sprintf("/api/volumes/%s", id)
+	if force {
+		path += "?force=true"
+	}
+	err := c.doRequest(ctx, http.MethodPut, path, volume, &result)
+	return &result, err
+}
+
+func (c *Client) DeleteVolume(ctx context.Context, id string) error {
+	return c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/volumes/%s", id), nil, nil)
+}
+
+// Capacity snapshot methods
+
+// TakeSnapshot persists the current utilization of every compute
+func (c *Client) TakeSnapshot(ctx context.Context) (*domain.CapacitySnapshot, error) {
+	var result domain.CapacitySnapshot
+	err := c.doRequest(ctx, http.MethodPost, "/api/capacity/snapshots", nil, &result)
+	return &result, err
+}
+
+// ListSnapshots returns the snapshots taken in the filtered time range, oldest first
+func (c *Client) ListSnapshots(ctx context.Context, filters storage.SnapshotFilters) ([]*domain.CapacitySnapshot, error) {
+	var result []*domain.CapacitySnapshot
+	query := snapshotQuery(filters.From, filters.To)
+	if filters.ComputeID != "" {
+		query.Set("compute_id", filters.ComputeID)
+	}
+	if filters.Samples {
+		query.Set("samples", "true")
+	}
+	if filters.Limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", filters.Limit))
+	}
+	err := c.doRequest(ctx, http.MethodGet, "/api/capacity/snapshots?"+query.Encode(), nil, &result)
+	return result, err
+}
+
+// GetSnapshot returns a snapshot with its compute samples
+func (c *Client) GetSnapshot(ctx context.Context, id string) (*domain.CapacitySnapshot, error) {
+	var result domain.CapacitySnapshot
+	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/capacity/snapshots/%s", id), nil, &result)
+	return &result, err
+}
+
+// CapacityHistory returns the utilization over time grouped by region, provider, a tag key, all or compute
+func (c *Client) CapacityHistory(ctx context.Context, groupBy string, from, to *time.Time) (*domain.CapacityHistory, error) {
+	var result domain.CapacityHistory
+	query := snapshotQuery(from, to)
+	if groupBy != "" {
+		query.Set("group_by", groupBy)
+	}
+	err := c.doRequest(ctx, http.MethodGet, "/api/capacity/history?"+query.Encode(), nil, &result)
+	return &result, err
+}
+
+// CapacityForecast estimates when each group will cross the utilization threshold. The horizon
+// is a window such as "90d" and defaults to a year when empty.
+func (c *Client) CapacityForecast(ctx context.Context, request domain.ForecastRequest, horizon string, from, to *time.Time) (*domain.CapacityForecast, error) {
+	var result domain.CapacityForecast
+	query := snapshotQuery(from, to)
+	if request.GroupBy != "" {
+		query.Set("group_by", request.GroupBy)
+	}
+	if request.Resource != "" {
+		query.Set("resource", request.Resource)
+	}
+	if request.Threshold != 0 {
+		query.Set("threshold", fmt.Sprintf("%g", request.Threshold))
+	}
+	if request.Method != "" {
+		query.Set("method", string(request.Method))
+	}
+	if request.SeasonLength != 0 {
+		query.Set("season", fmt.Sprintf("%d", request.SeasonLength))
+	}
+	if horizon != "" {
+		query.Set("horizon", horizon)
+	}
+	err := c.doRequest(ctx, http.MethodGet, "/api/capacity/forecast?"+query.Encode(), nil, &result)
+	return &result, err
+}
+
+// snapshotQuery encodes the time range of snapshot queries
+func snapshotQuery(from, to *time.Time) url.Values {
+	query := url.Values{}
+	if from != nil {
+		query.Set("from", from.UTC().Format(time.RFC3339))
+	}
+	if to != nil {
+		query.Set("to", to.UTC().Format(time.RFC3339))
+	}
+	return query
+}
@@ -11,49 +11,20 @@ type Assignment struct {
 	ComputeID string    `json:"compute_id"`
 	Quantity  int       `json:"quantity"`
 	Notes     string    `json:"notes,omitempty"`
+	Schedule            // Optional start and end, for planned deployments and temporary workloads
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// CanFitResources checks if required resources can fit within available resources
-func CanFitResources(required Resources, available Resources) bool {
-	for key, reqValue := range required {
-		availValue, exists := available[key]
-		if !exists {
-			return false
-		}
-
-		// Compare numeric values
-		switch req := reqValue.(type) {
-		case int:
-			if avail, ok := availValue.(int); ok {
-				if req > avail {
-					return false
-				}
-			} else if avail, ok := availValue.(float64); ok {
-				if float64(req) > avail {
-					return false
-				}
-			} else {
-				return false
-			}
-		case float64:
-			if avail, ok := availValue.(float64); ok {
-				if req > avail {
-					return false
-				}
-			} else if avail, ok := availValue.(int); ok {
-				if req > float64(avail) {
-					return false
-				}
-			} else {
-				return false
-			}
-		default:
-			// For non-numeric values, just check existence
-			continue
-		}
+// assignmentQuantity returns the number of instances an assignment represents (0 means 1)
+func assignmentQuantity(assignment *Assignment) int {
+	if assignment.Quantity == 0 {
+		return 1
 	}
+	return assignment.Quantity
+}
 
-	return true
+// CanFitResources checks if required resources can fit within available resources
+func CanFitResources(required Resources, available Resources) bool {
+	return required.Fits(available)
 }
@@ -1,8 +1,6 @@
 package domain
 
-import (
-	"time"
-)
+import "time"
 
 // ComponentType represents the type of component
 type ComponentType string
@@ -36,11 +34,22 @@ type RaidLevel string
 
 const (
 	RaidLevelNone  RaidLevel = "none"
-	RaidLevel0     RaidLevel = "raid0"  // Striping - Total = sum of disks
+	RaidLevel0     RaidLevel = "raid0"  // Striping - Total = n * smallest
 	RaidLevel1     RaidLevel = "raid1"  // Mirroring - Total = smallest disk
 	RaidLevel5     RaidLevel = "raid5"  // Striping with parity - Total = (n-1) * smallest
 	RaidLevel6     RaidLevel = "raid6"  // Striping with double parity - Total = (n-2) * smallest
-	RaidLevel10    RaidLevel = "raid10" // Mirrored stripes - Total = sum / 2
+	RaidLevel10    RaidLevel = "raid10" // Mirrored stripes - Total = n/2 * smallest
+	RaidLevel50    RaidLevel = "raid50" // Striped RAID5 spans - Total = (n-spans) * smallest
+	RaidLevel60    RaidLevel = "raid60" // Striped RAID6 spans - Total = (n-2*spans) * smallest
+	RaidLevelJBOD  RaidLevel = "jbod"   // Concatenated disks - Total = sum of disks
+)
+
+// RaidRole is the role of the disks of an assignment within their RAID group
+type RaidRole string
+
+const (
+	RaidRoleMember RaidRole = "member" // Holds data (default)
+	RaidRoleSpare  RaidRole = "spare"  // Hot spare, rebuilds a failed member; adds no capacity
 )
 
 // ComputeComponent represents a component assigned to a compute resource
@@ -54,240 +63,18 @@ type ComputeComponent struct {
 	Notes       string    `json:"notes,omitempty"`       // Installation notes
 	RaidLevel   RaidLevel `json:"raid_level,omitempty"`  // RAID configuration for storage
 	RaidGroup   string    `json:"raid_group,omitempty"`  // Group ID for RAID arrays
+	RaidRole    RaidRole  `json:"raid_role,omitempty"`   // Role in the RAID group (member or spare)
+	RaidSpans   int       `json:"raid_spans,omitempty"`  // Spans of a RAID50 or RAID60 group (default 2)
 	CreatedAt   time.Time `json:"created_at"`
 }
 
-// GetTotalResources calculates total resources from assigned components
-func (c *Compute) GetTotalResourcesFromComponents(components []*Component, assignments []*ComputeComponent) Resources {
-	resources := make(Resources)
-
-	// Group storage assignments by RAID group for special handling
-	raidGroups := make(map[string][]*storageAssignment)
-	var nonRaidStorage []*storageAssignment
-
-	for _, assignment := range assignments {
-		if assignment.ComputeID != c.ID {
-			continue
-		}
-
-		// Find the component
-		var component *Component
-		for _, comp := range components {
-			if comp.ID == assignment.ComponentID {
-				component = comp
-				break
-			}
-		}
-
-		if component == nil {
-			continue
-		}
-
-		// Aggregate resources based on component type
-		quantity := assignment.Quantity
-		compType := string(component.Type)
-
-		switch compType {
-		case "cpu":
-			// Aggregate CPU cores/threads - try multiple field names
-			coresValue := getSpecFloat(component.Specs, "threads", "thread_count", "cores", "core_count")
-			if coresValue > 0 {
-				existing := getFloatValue(resources, "cores")
-				resources["cores"] = int(existing + (coresValue * float64(quantity)))
-			}
-		case "ram", "memory":
-			// Aggregate RAM capacity - try multiple field names
-			// Fields ending in _gb are in GB, others are in MB
-			memValue := 0.0
-			if val := getSpecFloat(component.Specs, "capacity_gb", "size_gb", "memory_gb"); val > 0 {
-				memValue = val * 1024 // Convert GB to MB
-			} else if val := getSpecFloat(component.Specs, "memory", "size"); val > 0 {
-				memValue = val // Already in MB
-			}
-			if memValue > 0 {
-				existing := getFloatValue(resources, "memory")
-				resources["memory"] = int(existing + (memValue * float64(quantity)))
-			}
-		case "storage", "nvme", "ssd", "hdd":
-			// Handle storage with RAID support
-			storageValue := getSpecFloat(component.Specs, "size", "capacity_gb", "storage_gb", "capacity")
-			if storageValue > 0 {
-				sa := &storageAssignment{
-					size:        storageValue,
-					quantity:    quantity,
-					storageType: string(component.Type), // Track storage type
-				}
-
-				// Group by RAID configuration
-				if assignment.RaidLevel != "" && assignment.RaidLevel != RaidLevelNone && assignment.RaidGroup != "" {
-					// Part of a RAID array
-					if raidGroups[assignment.RaidGroup] == nil {
-						raidGroups[assignment.RaidGroup] = make([]*storageAssignment, 0)
-					}
-					sa.raidLevel = assignment.RaidLevel
-					raidGroups[assignment.RaidGroup] = append(raidGroups[assignment.RaidGroup], sa)
-				} else {
-					// Non-RAID storage
-					nonRaidStorage = append(nonRaidStorage, sa)
-				}
-			}
-		case "gpu":
-			// Count GPUs and aggregate VRAM
-			existing := getFloatValue(resources, "gpu")
-			resources["gpu"] = int(existing) + quantity
-
-			// Aggregate VRAM - try multiple field names
-			// Fields ending in _gb are in GB, others are in MB
-			vramValue := 0.0
-			if val := getSpecFloat(component.Specs, "vram_gb", "memory_gb", "video_memory_gb"); val > 0 {
-				vramValue = val * 1024 // Convert GB to MB
-			} else if val := getSpecFloat(component.Specs, "vram", "memory"); val > 0 {
-				vramValue = val // Already in MB
-			}
-			if vramValue > 0 {
-				existing := getFloatValue(resources, "vram")
-				resources["vram"] = int(existing + (vramValue * float64(quantity)))
-			}
-		case "nic":
-			// Aggregate network bandwidth
-			if speedGbps, ok := component.Specs["speed_gbps"].(float64); ok {
-				existing := getFloatValue(resources, "bandwidth_gbps")
-				resources["bandwidth_gbps"] = existing + (speedGbps * float64(quantity))
-			}
-		}
-	}
-
-	// Calculate storage by type with RAID support
-	storageByType := make(map[string]float64)
-
-	// Calculate RAID arrays
-	for _, group := range raidGroups {
-		capacity := calculateRaidCapacity(group)
-		// Use storage type from first assignment in group (all should be same type in RAID)
-		if len(group) > 0 {
-			storageType := group[0].storageType
-			storageByType[storageType] += capacity
-		}
-	}
-
-	// Add non-RAID storage
-	for _, sa := range nonRaidStorage {
-		storageByType[sa.storageType] += sa.size * float64(sa.quantity)
-	}
-
-	// Add to resources by type
-	for storageType, capacity := range storageByType {
-		if capacity > 0 {
-			resources[storageType] = int(capacity)
-		}
-	}
-
-	return resources
-}
-
-type storageAssignment struct {
-	size        float64
-	quantity    int
-	raidLevel   RaidLevel
-	storageType string
-}
-
-func calculateRaidCapacity(assignments []*storageAssignment) float64 {
-	if len(assignments) == 0 {
-		return 0
-	}
-
-	// All assignments in a group should have the same RAID level
-	raidLevel := assignments[0].raidLevel
-
-	// Collect all disk sizes
-	var disks []float64
-	for _, sa := range assignments {
-		for i := 0; i < sa.quantity; i++ {
-			disks = append(disks, sa.size)
-		}
-	}
-
-	if len(disks) == 0 {
-		return 0
-	}
-
-	switch raidLevel {
-	case RaidLevel0:
-		// RAID 0: Sum of all disks
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total
-
-	case RaidLevel1:
-		// RAID 1: Size of smallest disk (mirroring)
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return smallest
-
-	case RaidLevel5:
-		// RAID 5: (n-1) * smallest disk
-		if len(disks) < 3 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return float64(len(disks)-1) * smallest
-
-	case RaidLevel6:
-		// RAID 6: (n-2) * smallest disk
-		if len(disks) < 4 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		smallest := disks[0]
-		for _, size := range disks {
-			if size < smallest {
-				smallest = size
-			}
-		}
-		return float64(len(disks)-2) * smallest
-
-	case RaidLevel10:
-		// RAID 10: Sum / 2 (mirrored stripes)
-		if len(disks) < 4 || len(disks)%2 != 0 {
-			total := 0.0
-			for _, size := range disks {
-				total += size
-			}
-			return total
-		}
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total / 2.0
-
-	default:
-		// Unknown RAID level, sum all disks
-		total := 0.0
-		for _, size := range disks {
-			total += size
-		}
-		return total
+// GetTotalResourcesFromComponents calculates total resources from assigned components using
+// the derivation rules. nil rules use DefaultDerivationRules.
+func (c *Compute) GetTotalResourcesFromComponents(components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) Resources {
+	if rules == nil {
+		rules = DefaultDerivationRules()
 	}
+	return applyDerivationRules(c.ID, components, assignments, rules)
 }
 
 // Helper to extract float values from component specs with multiple possible keys
@@ -307,15 +94,7 @@ func getSpecFloat(specs map[string]interface{}, keys ...string) float64 {
 
 // Helper to safely get float value from resources
 func getFloatValue(resources Resources, key string) float64 {
-	if val, ok := resources[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return float64(v)
-		case float64:
-			return v
-		}
-	}
-	return 0
+	return resources.Get(key).Float()
 }
 
 // ComponentTypes returns all valid component types
@@ -20,9 +20,10 @@ const (
 	ComputeStateDecommissioned ComputeState = "decommissioned"
 )
 
-// Resources represents dynamic resource attributes as key-value pairs
-// Examples: {"cpu": 8, "ram_gb": 32, "nvme_gb": 500, "bandwidth_mbps": 1000}
-type Resources map[string]interface{}
+// Resources represents dynamic resource attributes as key-value pairs, each quantity in the
+// canonical unit of its key (see UnitForKey)
+// Examples: {"cores": 8, "memory": 32768, "nvme": 500, "bandwidth_gbps": 10}
+type Resources map[string]Quantity
 
 // Compute represents a compute resource (baremetal, VPS, or VM)
 type Compute struct {
@@ -42,19 +43,44 @@ type Compute struct {
 	ContractEndDate  *time.Time `json:"contract_end_date,omitempty"`
 	NextRenewalDate  *time.Time `json:"next_renewal_date,omitempty"`
 
+	// Overcommit ratios per resource key (e.g. {"cores": 4.0}), overriding overcommit policies
+	Overcommit map[string]float64 `json:"overcommit,omitempty"`
+
+	// Hierarchy fields: a VM or VPS hosted on a baremetal hypervisor
+	ParentID string    `json:"parent_id,omitempty"` // Baremetal compute hosting this one
+	Size     Resources `json:"size,omitempty"`      // Resources carved out of the parent, used instead of components
+
 	// Resources is computed from components and NOT persisted to database
 	// Use GetTotalResourcesFromComponents to populate this field
 	Resources Resources              `json:"-"`
+
+	// Facts holds the hardware facts derived from components, not persisted
+	Facts HardwareFacts `json:"-"`
+
+	// StorageGroups holds the RAID groups and standalone disks derived from components, not persisted
+	StorageGroups []StorageGroup `json:"-"`
+
+	// RawResources holds the physical resources once ApplyOvercommit scaled Resources
+	RawResources Resources `json:"-"`
+	// AppliedOvercommit holds the ratios resolved by ApplyOvercommit
+	AppliedOvercommit map[string]float64 `json:"-"`
+	// Hosted holds the resources carved out by hosted computes, set by RollUpHosted
+	Hosted Resources `json:"-"`
+	// Held holds the resources of capacity reservations, set by HoldReservations
+	Held Resources `json:"-"`
 }
 
 // GetAllocatedResources calculates total allocated resources from assignments
-// Uses service MaxSpec for each assignment, multiplied by assignment quantity
-func (c *Compute) GetAllocatedResources(assignments []*Assignment, services map[string]*Service) Resources {
-	allocated := make(Resources)
+// Uses the service spec reserved under the basis for each assignment, multiplied by assignment quantity.
+// Resources carved out by hosted computes and held by reservations count as allocated, and so do
+// the volumes of storage pools and the storage requirements of the services, on the tier of the
+// storage group they are allocated on.
+func (c *Compute) GetAllocatedResources(assignments []*Assignment, services map[string]*Service, basis ReservationBasis) Resources {
+	allocated := c.Hosted.Add(c.Held).Add(c.storageAllocated(assignments, services))
 
 	for _, assignment := range assignments {
 		if assignment.ComputeID == c.ID {
-			// Look up service to get MaxSpec
+			// Look up service to get its reserved spec
 			service, ok := services[assignment.ServiceID]
 			if !ok {
 				continue // Skip if service not found
@@ -65,31 +91,8 @@ func (c *Compute) GetAllocatedResources(assignments []*Assignment, services map[
 				quantity = 1
 			}
 
-			// Add MaxSpec resources to allocated, multiplied by quantity
-			for key, value := range service.MaxSpec {
-				if existing, ok := allocated[key]; ok {
-					// Sum numeric values
-					switch v := value.(type) {
-					case int:
-						if e, ok := existing.(int); ok {
-							allocated[key] = e + (v * quantity)
-						}
-					case float64:
-						if e, ok := existing.(float64); ok {
-							allocated[key] = e + (v * float64(quantity))
-						}
-					}
-				} else {
-					switch v := value.(type) {
-					case int:
-						allocated[key] = v * quantity
-					case float64:
-						allocated[key] = v * float64(quantity)
-					default:
-						allocated[key] = value
-					}
-				}
-			}
+			// Add reserved resources to allocated, multiplied by quantity
+			allocated = allocated.Add(basis.Spec(service).Scale(float64(quantity)))
 		}
 	}
 
@@ -98,33 +101,26 @@ func (c *Compute) GetAllocatedResources(assignments []*Assignment, services map[
 
 // GetAvailableResources calculates available resources
 func (c *Compute) GetAvailableResources(allocated Resources) Resources {
-	available := make(Resources)
-
-	for key, totalValue := range c.Resources {
-		if alloc, ok := allocated[key]; ok {
-			// Subtract allocated from total, handling type mismatches
-			switch t := totalValue.(type) {
-			case int:
-				switch a := alloc.(type) {
-				case int:
-					available[key] = t - a
-				case float64:
-					available[key] = t - int(a)
-				}
-			case float64:
-				switch a := alloc.(type) {
-				case int:
-					available[key] = t - float64(a)
-				case float64:
-					available[key] = t - a
-				}
-			}
-		} else {
-			available[key] = totalValue
+	return c.Resources.Sub(allocated)
+}
+
+// TopologyValue returns the compute's value for a topology key.
+// "host", "region" and "provider" resolve to compute fields, any other key is looked up in tags.
+// A hosted compute shares the host of its parent.
+func (c *Compute) TopologyValue(key string) (string, bool) {
+	switch key {
+	case TopologyKeyHost:
+		if c.ParentID != "" {
+			return c.ParentID, true
 		}
+		return c.ID, true
+	case TopologyKeyRegion:
+		return c.Region, c.Region != ""
+	case TopologyKeyProvider:
+		return c.Provider, c.Provider != ""
 	}
-
-	return available
+	value, ok := c.Tags[key]
+	return value, ok
 }
 
 // MatchesTags checks if compute has all required tags
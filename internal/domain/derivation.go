@@ -0,0 +1,308 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Aggregation combines the values a derivation rule reads from the components of a compute
+type Aggregation string
+
+const (
+	AggregationSum   Aggregation = "sum"   // Spec value x quantity, summed over the components
+	AggregationCount Aggregation = "count" // Component quantity, no spec field needed
+	AggregationMax   Aggregation = "max"   // Largest spec value
+	AggregationMin   Aggregation = "min"   // Smallest spec value
+	AggregationRAID  Aggregation = "raid"  // Spec value x quantity, RAID groups count their usable capacity
+)
+
+// DerivationRule derives a compute resource from a spec field of its components.
+// For one component, only the first rule (lowest priority, then name) of a resource whose
+// spec field is set applies, so fallbacks such as capacity_gb then memory are separate rules.
+type DerivationRule struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	ComponentType string      `json:"component_type"`        // Component type the rule applies to (cpu, ram, fpga...)
+	SpecFields    []string    `json:"spec_fields,omitempty"` // First numeric spec field present is read (not used by count)
+	Unit          string      `json:"unit,omitempty"`        // Unit of the spec field, converted to the unit of the resource (empty: already in it)
+	Resource      string      `json:"resource"`              // Target resource key
+	Aggregation   Aggregation `json:"aggregation"`
+	Priority      int         `json:"priority"`
+	Description   string      `json:"description,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// Validate checks the rule fields
+func (r *DerivationRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.ComponentType == "" {
+		return fmt.Errorf("component_type is required")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("resource is required")
+	}
+
+	switch r.Aggregation {
+	case AggregationSum, AggregationMax, AggregationMin, AggregationRAID:
+		if len(r.SpecFields) == 0 {
+			return fmt.Errorf("spec_fields is required for %s aggregation", r.Aggregation)
+		}
+	case AggregationCount:
+	default:
+		return fmt.Errorf("unknown aggregation %q (use sum, count, max, min or raid)", r.Aggregation)
+	}
+
+	if _, err := r.factor(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// factor converts a spec value in the rule unit to the canonical unit of the resource
+func (r *DerivationRule) factor() (float64, error) {
+	if r.Unit == "" {
+		return 1, nil
+	}
+	unit, ok := units[r.Unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", r.Unit)
+	}
+	target := UnitForKey(r.Resource)
+	if unit.Dimension != target.Dimension {
+		return 0, fmt.Errorf("unit %s is not a %s unit, %s is measured in %s", r.Unit, target.Dimension, r.Resource, target.Name)
+	}
+	return unit.Factor / target.Factor, nil
+}
+
+// value reads the spec value of a component in the unit of the resource. ok is false when
+// no spec field of the rule is set.
+func (r *DerivationRule) value(component *Component) (float64, bool) {
+	if r.Aggregation == AggregationCount {
+		return 1, true
+	}
+	value := getSpecFloat(component.Specs, r.SpecFields...)
+	if value <= 0 {
+		return 0, false
+	}
+	factor, err := r.factor()
+	if err != nil {
+		return 0, false
+	}
+	return value * factor, true
+}
+
+// SortDerivationRules orders rules by priority, then name
+func SortDerivationRules(rules []*DerivationRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+}
+
+// ResourceDefinitions returns a definition for every rule target that is not a built-in
+// resource key, so services may request it
+func ResourceDefinitions(rules []*DerivationRule) []ResourceDefinition {
+	builtin := make(map[string]bool)
+	for _, definition := range builtinResources {
+		builtin[definition.Key] = true
+	}
+
+	byKey := make(map[string]*ResourceDefinition)
+	keys := make([]string, 0)
+	for _, rule := range rules {
+		if builtin[rule.Resource] {
+			continue
+		}
+		definition, ok := byKey[rule.Resource]
+		if !ok {
+			unit := UnitForKey(rule.Resource)
+			definition = &ResourceDefinition{
+				Key:         rule.Resource,
+				Unit:        unit.Name,
+				Dimension:   unit.Dimension,
+				Description: rule.Description,
+			}
+			byKey[rule.Resource] = definition
+			keys = append(keys, rule.Resource)
+		}
+		if rule.Aggregation == AggregationRAID {
+			definition.Storage = true
+		}
+		source := fmt.Sprintf("%s components (rule %s)", rule.ComponentType, rule.Name)
+		if definition.Source == "" {
+			definition.Source = source
+		} else {
+			definition.Source += ", " + source
+		}
+	}
+
+	definitions := make([]ResourceDefinition, 0, len(keys))
+	for _, key := range keys {
+		definitions = append(definitions, *byKey[key])
+	}
+	return definitions
+}
+
+// DefaultDerivationRules returns the rules matching the built-in derivation of components:
+// cpu threads to cores, ram capacity to memory, gpu count and vram, storage capacity per
+// storage type (RAID aware) and nic speed to bandwidth_gbps
+func DefaultDerivationRules() []*DerivationRule {
+	rules := []*DerivationRule{
+		{Name: "cpu-cores", ComponentType: "cpu", SpecFields: []string{"threads", "thread_count", "cores", "core_count"}, Resource: "cores", Aggregation: AggregationSum, Priority: 100, Description: "CPU threads"},
+		{Name: "ram-capacity-gb", ComponentType: "ram", SpecFields: []string{"capacity_gb", "size_gb", "memory_gb"}, Unit: UnitGiB.Name, Resource: "memory", Aggregation: AggregationSum, Priority: 100, Description: "RAM capacity in GB"},
+		{Name: "ram-capacity-mb", ComponentType: "ram", SpecFields: []string{"memory", "size"}, Unit: UnitMiB.Name, Resource: "memory", Aggregation: AggregationSum, Priority: 110, Description: "RAM capacity in MB"},
+		{Name: "memory-capacity-gb", ComponentType: "memory", SpecFields: []string{"capacity_gb", "size_gb", "memory_gb"}, Unit: UnitGiB.Name, Resource: "memory", Aggregation: AggregationSum, Priority: 100, Description: "RAM capacity in GB"},
+		{Name: "memory-capacity-mb", ComponentType: "memory", SpecFields: []string{"memory", "size"}, Unit: UnitMiB.Name, Resource: "memory", Aggregation: AggregationSum, Priority: 110, Description: "RAM capacity in MB"},
+		{Name: "gpu-count", ComponentType: "gpu", Resource: "gpu", Aggregation: AggregationCount, Priority: 100, Description: "Number of GPUs"},
+		{Name: "gpu-vram-gb", ComponentType: "gpu", SpecFields: []string{"vram_gb", "memory_gb", "video_memory_gb"}, Unit: UnitGiB.Name, Resource: "vram", Aggregation: AggregationSum, Priority: 100, Description: "GPU memory in GB"},
+		{Name: "gpu-vram-mb", ComponentType: "gpu", SpecFields: []string{"vram", "memory"}, Unit: UnitMiB.Name, Resource: "vram", Aggregation: AggregationSum, Priority: 110, Description: "GPU memory in MB"},
+		{Name: "nic-bandwidth", ComponentType: "nic", SpecFields: []string{"speed_gbps"}, Unit: UnitGbps.Name, Resource: "bandwidth_gbps", Aggregation: AggregationSum, Priority: 100, Description: "NIC speed"},
+	}
+	for _, storageType := range []string{"storage", "nvme", "ssd", "hdd"} {
+		rules = append(rules, &DerivationRule{
+			Name:          storageType + "-capacity",
+			ComponentType: storageType,
+			SpecFields:    []string{"size", "capacity_gb", "storage_gb", "capacity"},
+			Unit:          UnitGB.Name,
+			Resource:      storageType,
+			Aggregation:   AggregationRAID,
+			Priority:      100,
+			Description:   "Storage capacity in GB, RAID aware",
+		})
+	}
+	SortDerivationRules(rules)
+	return rules
+}
+
+// applyDerivationRules applies the rules to the components assigned to a compute
+func applyDerivationRules(computeID string, components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) Resources {
+	resources, _ := deriveFromComponents(computeID, components, assignments, rules)
+	return resources
+}
+
+// deriveFromComponents applies the rules to the components assigned to a compute and returns
+// its resources and the layout of the storage groups the raid aggregation found
+func deriveFromComponents(computeID string, components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) (Resources, []StorageLayout) {
+	sorted := make([]*DerivationRule, len(rules))
+	copy(sorted, rules)
+	SortDerivationRules(sorted)
+
+	byID := make(map[string]*Component, len(components))
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	totals := make(map[string]float64)
+	extremes := make(map[string]float64)
+
+	// Group storage assignments by RAID group for special handling
+	raidGroups := make(map[string][]*storageAssignment)
+	raidOrder := make([]string, 0)
+	var nonRaidStorage []*storageAssignment
+
+	for _, assignment := range assignments {
+		if assignment.ComputeID != computeID {
+			continue
+		}
+		component, ok := byID[assignment.ComponentID]
+		if !ok {
+			continue
+		}
+		quantity := float64(assignment.Quantity)
+
+		applied := make(map[string]bool)
+		for _, rule := range sorted {
+			if rule.ComponentType != string(component.Type) || applied[rule.Resource] {
+				continue
+			}
+			value, ok := rule.value(component)
+			if !ok {
+				continue
+			}
+			applied[rule.Resource] = true
+
+			switch rule.Aggregation {
+			case AggregationSum:
+				totals[rule.Resource] += value * quantity
+			case AggregationCount:
+				totals[rule.Resource] += quantity
+			case AggregationMax:
+				if current, seen := extremes[rule.Resource]; !seen || value > current {
+					extremes[rule.Resource] = value
+				}
+			case AggregationMin:
+				if current, seen := extremes[rule.Resource]; !seen || value < current {
+					extremes[rule.Resource] = value
+				}
+			case AggregationRAID:
+				sa := &storageAssignment{
+					component:   component,
+					size:        value,
+					quantity:    assignment.Quantity,
+					storageType: rule.Resource,
+					role:        assignment.RaidRole,
+				}
+
+				// Group by RAID configuration
+				if assignment.RaidLevel != "" && assignment.RaidLevel != RaidLevelNone && assignment.RaidGroup != "" {
+					if _, seen := raidGroups[assignment.RaidGroup]; !seen {
+						raidOrder = append(raidOrder, assignment.RaidGroup)
+					}
+					sa.raidLevel = assignment.RaidLevel
+					sa.spans = assignment.RaidSpans
+					raidGroups[assignment.RaidGroup] = append(raidGroups[assignment.RaidGroup], sa)
+				} else {
+					sa.role = RaidRoleMember
+					nonRaidStorage = append(nonRaidStorage, sa)
+				}
+			}
+		}
+	}
+
+	// RAID groups count their usable capacity; a group that cannot be built counts none
+	layouts := make([]StorageLayout, 0, len(raidOrder))
+	for _, group := range raidOrder {
+		layout := raidLayout(group, raidGroups[group])
+		totals[layout.Tier] += layout.Usable.Float()
+		layouts = append(layouts, layout)
+	}
+
+	// Standalone disks form one unprotected group per storage type
+	standalone := make(map[string][]*storageAssignment)
+	standaloneOrder := make([]string, 0)
+	for _, sa := range nonRaidStorage {
+		if _, seen := standalone[sa.storageType]; !seen {
+			standaloneOrder = append(standaloneOrder, sa.storageType)
+		}
+		standalone[sa.storageType] = append(standalone[sa.storageType], sa)
+	}
+	for _, storageType := range standaloneOrder {
+		layout := standaloneLayout(storageType, standalone[storageType])
+		totals[storageType] += layout.Usable.Float()
+		layouts = append(layouts, layout)
+	}
+	for key, value := range extremes {
+		totals[key] += value
+	}
+
+	resources := make(Resources)
+	for key, value := range totals {
+		if value <= 0 {
+			continue
+		}
+		// Counts and bytes are whole numbers, bandwidth keeps its fraction (e.g. 2.5 Gbps)
+		if UnitForKey(key).Dimension != DimensionBandwidth {
+			value = math.Trunc(value)
+		}
+		resources[key] = Quantity(value)
+	}
+
+	return resources, layouts
+}
@@ -0,0 +1,196 @@
+package domain
+
+import "fmt"
+
+// Move relocates instances of an assignment to another compute
+type Move struct {
+	AssignmentID string   `json:"assignment_id"` // Assignment on the source compute
+	ServiceID    string   `json:"service_id"`
+	ServiceName  string   `json:"service_name"`
+	From         *Compute `json:"from"`
+	To           *Compute `json:"to"`
+	Quantity     int      `json:"quantity"` // Instances moved
+}
+
+// UnmovableAssignment is an assignment (or part of it) that has no valid target
+type UnmovableAssignment struct {
+	AssignmentID string `json:"assignment_id"`
+	ServiceID    string `json:"service_id"`
+	ServiceName  string `json:"service_name"`
+	Quantity     int    `json:"quantity"` // Instances left on the compute
+	Reason       string `json:"reason"`
+}
+
+// DrainPlan describes where the assignments of a compute go before it is taken out of service
+type DrainPlan struct {
+	Compute   *Compute              `json:"compute"`
+	Strategy  string                `json:"strategy"`
+	Feasible  bool                  `json:"feasible"` // Every instance has a target
+	Moves     []Move                `json:"moves"`
+	Unmovable []UnmovableAssignment `json:"unmovable,omitempty"`
+	Executed  bool                  `json:"executed"`
+	Warnings  []string              `json:"warnings,omitempty"`
+	Message   string                `json:"message,omitempty"`
+	// Spec each instance reserves on its target (min, max or pNN)
+	Reservation ReservationBasis `json:"reservation"`
+}
+
+// PlanDrain computes a rehoming plan for every assignment on a compute and the computes it hosts.
+// Targets are chosen with the scoring strategy among the other active computes, following placement
+// rules and capacity. Instances that cannot be placed are listed as unmovable.
+func (cp *CapacityPlanner) PlanDrain(computeID string, strategyName string) (*DrainPlan, error) {
+	var drained *Compute
+	for _, compute := range cp.computes {
+		if compute.ID == computeID {
+			drained = compute
+			break
+		}
+	}
+	if drained == nil {
+		return nil, fmt.Errorf("compute %s not found", computeID)
+	}
+
+	// Computes hosted on the drained compute go down with it
+	lost := []*Compute{drained}
+	others := make([]*Compute, 0, len(cp.computes))
+	for _, compute := range cp.computes {
+		switch {
+		case compute.ID == computeID:
+		case compute.ParentID == computeID:
+			lost = append(lost, compute)
+		default:
+			others = append(others, compute)
+		}
+	}
+
+	strategy, err := GetScoringStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build services map for resource calculation
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
+
+	plan := &DrainPlan{
+		Compute:     drained,
+		Strategy:    strategy.Name(),
+		Reservation: cp.reservation,
+	}
+	plan.Moves, plan.Unmovable = cp.planEvacuation(lost, others, strategy, servicesMap)
+
+	plan.Feasible = len(plan.Unmovable) == 0
+	if plan.Feasible {
+		plan.Message = fmt.Sprintf("all %d move(s) planned", len(plan.Moves))
+	} else {
+		plan.Message = fmt.Sprintf("%d assignment(s) cannot be fully moved", len(plan.Unmovable))
+	}
+
+	return plan, nil
+}
+
+// planEvacuation plans moves for every assignment on the drained computes onto the target computes.
+// Instances that cannot be placed are returned as unmovable.
+func (cp *CapacityPlanner) planEvacuation(drained []*Compute, others []*Compute, strategy ScoringStrategy, servicesMap map[string]*Service) ([]Move, []UnmovableAssignment) {
+	sources := make(map[string]*Compute, len(drained))
+	for _, compute := range drained {
+		sources[compute.ID] = compute
+	}
+
+	// Plan against the other computes, without the instances being drained
+	pending := make([]*Assignment, 0)
+	working := make([]*Assignment, 0, len(cp.assignments))
+	for _, assignment := range cp.assignments {
+		if _, ok := sources[assignment.ComputeID]; ok {
+			pending = append(pending, assignment)
+		} else {
+			working = append(working, assignment)
+		}
+	}
+
+	targets := NewCapacityPlanner(others, cp.services, working)
+	targets.SetComponents(cp.components)
+	targets.SetOvercommitPolicies(cp.policies)
+	targets.SetReservation(cp.reservation)
+
+	moves := make([]Move, 0)
+	var unmovable []UnmovableAssignment
+
+	// Service affinity may require another drained service to move first,
+	// so retry the remaining assignments while progress is made
+	remaining := make(map[string]int)
+	for _, assignment := range pending {
+		remaining[assignment.ID] = assignmentQuantity(assignment)
+	}
+	for progress := true; progress; {
+		progress = false
+		for _, assignment := range pending {
+			if remaining[assignment.ID] == 0 {
+				continue
+			}
+			service, ok := servicesMap[assignment.ServiceID]
+			if !ok {
+				continue
+			}
+
+			request := PlanRequest{ServiceID: service.ID, Strategy: strategy.Name()}
+			placements, _, next := targets.placeReplicas(service, request, strategy, remaining[assignment.ID], working, servicesMap)
+			if len(placements) == 0 {
+				continue
+			}
+
+			working = next
+			remaining[assignment.ID] -= len(placements)
+			moves = appendMoves(moves, assignment, service, sources[assignment.ComputeID], placements)
+			progress = true
+		}
+	}
+
+	for _, assignment := range pending {
+		if remaining[assignment.ID] == 0 {
+			continue
+		}
+		entry := UnmovableAssignment{
+			AssignmentID: assignment.ID,
+			ServiceID:    assignment.ServiceID,
+			Quantity:     remaining[assignment.ID],
+		}
+		if service, ok := servicesMap[assignment.ServiceID]; ok {
+			entry.ServiceName = service.Name
+			entry.Reason = fmt.Sprintf("no compute satisfies placement rules and capacity for %d of %d instance(s)",
+				remaining[assignment.ID], assignmentQuantity(assignment))
+		} else {
+			entry.Reason = "service not found"
+		}
+		unmovable = append(unmovable, entry)
+	}
+
+	return moves, unmovable
+}
+
+// appendMoves groups replica placements of an assignment into one move per target compute
+func appendMoves(moves []Move, assignment *Assignment, service *Service, from *Compute, placements []Placement) []Move {
+	for _, placement := range placements {
+		merged := false
+		for i := range moves {
+			if moves[i].AssignmentID == assignment.ID && moves[i].To.ID == placement.Compute.ID {
+				moves[i].Quantity++
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			moves = append(moves, Move{
+				AssignmentID: assignment.ID,
+				ServiceID:    service.ID,
+				ServiceName:  service.Name,
+				From:         from,
+				To:           placement.Compute,
+				Quantity:     1,
+			})
+		}
+	}
+	return moves
+}
@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ForecastMethod is the model used to extrapolate utilization
+type ForecastMethod string
+
+const (
+	ForecastLinear      ForecastMethod = "linear"       // Least-squares line through the history
+	ForecastHoltWinters ForecastMethod = "holt-winters" // Additive triple exponential smoothing, trend only without enough seasons
+)
+
+// Holt-Winters smoothing factors for the level, the trend and the season
+const (
+	holtWintersAlpha = 0.5
+	holtWintersBeta  = 0.1
+	holtWintersGamma = 0.3
+)
+
+// DefaultForecastThreshold is the utilization percentage forecasts look for by default
+const DefaultForecastThreshold = 80.0
+
+// DefaultForecastHorizon is how far ahead forecasts look by default
+const DefaultForecastHorizon = 365 * 24 * time.Hour
+
+// ForecastRequest asks when groups of computes will cross a utilization threshold
+type ForecastRequest struct {
+	GroupBy      string         `json:"group_by,omitempty"`      // all (default), compute, region, provider or a tag key
+	Resource     string         `json:"resource,omitempty"`      // Forecast the utilization of one resource key instead of the average
+	Threshold    float64        `json:"threshold,omitempty"`     // Utilization percentage (default 80)
+	Method       ForecastMethod `json:"method,omitempty"`        // linear (default) or holt-winters
+	SeasonLength int            `json:"season_length,omitempty"` // Snapshots per season for holt-winters (e.g. 24 hourly snapshots)
+	Horizon      time.Duration  `json:"-"`                       // How far ahead to look (default 365 days)
+}
+
+// CapacityForecast tells, per group, when utilization is expected to cross the threshold
+type CapacityForecast struct {
+	GroupBy   string          `json:"group_by"`
+	Resource  string          `json:"resource,omitempty"`
+	Method    ForecastMethod  `json:"method"`
+	Threshold float64         `json:"threshold"`
+	Horizon   string          `json:"horizon"`
+	Groups    []GroupForecast `json:"groups"`
+}
+
+// Forecast outcomes of a group
+const (
+	ForecastAbove        = "above"             // Already at or above the threshold
+	ForecastCrossing     = "crossing"          // Expected to cross within the horizon
+	ForecastNotCrossing  = "not-crossing"      // Not expected to cross within the horizon
+	ForecastInsufficient = "insufficient-data" // Fewer than two snapshots
+)
+
+// GroupForecast is the forecast of one group
+type GroupForecast struct {
+	Group       string     `json:"group"`
+	Status      string     `json:"status"`
+	Points      int        `json:"points"`               // Snapshots the forecast is based on
+	Current     float64    `json:"current"`              // Last utilization percentage
+	TrendPerDay float64    `json:"trend_per_day"`        // Utilization points gained per day
+	Seasonal    bool       `json:"seasonal,omitempty"`   // Holt-Winters used a seasonal component
+	CrossesAt   *time.Time `json:"crosses_at,omitempty"` // Expected time the threshold is crossed
+	DaysUntil   *float64   `json:"days_until,omitempty"` // Days from the last snapshot to the crossing
+	Message     string     `json:"message,omitempty"`
+}
+
+// Validate checks the forecast request and fills in the defaults
+func (r *ForecastRequest) Validate() error {
+	if r.Method == "" {
+		r.Method = ForecastLinear
+	}
+	if r.Method != ForecastLinear && r.Method != ForecastHoltWinters {
+		return fmt.Errorf("unknown forecast method %q (use linear or holt-winters)", r.Method)
+	}
+	if r.Threshold == 0 {
+		r.Threshold = DefaultForecastThreshold
+	}
+	if r.Threshold < 0 || r.Threshold > 100 {
+		return fmt.Errorf("threshold must be between 0 and 100")
+	}
+	if r.SeasonLength < 0 {
+		return fmt.Errorf("season_length must not be negative")
+	}
+	if r.Horizon == 0 {
+		r.Horizon = DefaultForecastHorizon
+	}
+	if r.Horizon < 0 {
+		return fmt.Errorf("horizon must not be negative")
+	}
+	return nil
+}
+
+// ForecastCapacity extrapolates the utilization of every series of the history. Snapshots are
+// expected at a regular interval; Holt-Winters steps forward by the average interval.
+func ForecastCapacity(history *CapacityHistory, request ForecastRequest) (*CapacityForecast, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	forecast := &CapacityForecast{
+		GroupBy:   history.GroupBy,
+		Resource:  request.Resource,
+		Method:    request.Method,
+		Threshold: request.Threshold,
+		Horizon:   formatDays(request.Horizon),
+		Groups:    make([]GroupForecast, 0, len(history.Series)),
+	}
+
+	for _, series := range history.Series {
+		times := make([]time.Time, 0, len(series.Points))
+		values := make([]float64, 0, len(series.Points))
+		for i := range series.Points {
+			value, ok := series.Points[i].utilization(request.Resource)
+			if !ok {
+				continue
+			}
+			times = append(times, series.Points[i].Time)
+			values = append(values, value)
+		}
+
+		group := GroupForecast{Group: series.Group, Points: len(values)}
+		if len(values) > 0 {
+			group.Current = values[len(values)-1]
+		}
+
+		switch {
+		case len(values) < 2 || !times[len(times)-1].After(times[0]):
+			group.Status = ForecastInsufficient
+			group.Message = "at least two snapshots at different times are needed"
+		case request.Method == ForecastHoltWinters:
+			forecastHoltWinters(&group, times, values, request)
+		default:
+			forecastLinear(&group, times, values, request)
+		}
+
+		forecast.Groups = append(forecast.Groups, group)
+	}
+
+	return forecast, nil
+}
+
+// forecastLinear fits a least-squares line through the utilization and solves it for the threshold
+func forecastLinear(group *GroupForecast, times []time.Time, values []float64, request ForecastRequest) {
+	first := times[0]
+	n := float64(len(values))
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := times[i].Sub(first).Hours() / 24
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		group.Status = ForecastInsufficient
+		group.Message = "snapshots must be taken at different times"
+		return
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	group.TrendPerDay = slope
+
+	last := times[len(times)-1]
+	if group.Current >= request.Threshold {
+		group.setAbove(request.Threshold)
+		return
+	}
+	if slope <= 0 {
+		group.setNotCrossing(request)
+		return
+	}
+
+	// Days from the last snapshot until the fitted line reaches the threshold
+	lastX := last.Sub(first).Hours() / 24
+	days := math.Max(0, (request.Threshold-intercept)/slope-lastX)
+	group.setCrossing(last, days, request)
+}
+
+// forecastHoltWinters smooths the level, trend and, with at least two seasons of snapshots, the
+// season of the utilization, then steps forward until the threshold is crossed
+func forecastHoltWinters(group *GroupForecast, times []time.Time, values []float64, request ForecastRequest) {
+	n := len(values)
+	last := times[n-1]
+	step := last.Sub(times[0]) / time.Duration(n-1)
+	if step <= 0 {
+		group.Status = ForecastInsufficient
+		group.Message = "snapshots must be taken at different times"
+		return
+	}
+	stepDays := step.Hours() / 24
+
+	season := request.SeasonLength
+	seasonal := season >= 2 && n >= 2*season
+
+	var level, trend float64
+	start := 1 // First point not used to initialise the level, trend and season indices
+	seasons := make([]float64, season)
+	if seasonal {
+		var firstMean, secondMean float64
+		for i := 0; i < season; i++ {
+			firstMean += values[i]
+			secondMean += values[season+i]
+		}
+		firstMean /= float64(season)
+		secondMean /= float64(season)
+
+		level = firstMean
+		trend = (secondMean - firstMean) / float64(season)
+		for i := 0; i < season; i++ {
+			seasons[i] = values[i] - firstMean
+		}
+		start = season
+	} else {
+		level = values[0]
+		trend = values[1] - values[0]
+	}
+
+	for i := start; i < n; i++ {
+		seasonIndex := 0.0
+		if seasonal {
+			seasonIndex = seasons[i%season]
+		}
+		previousLevel := level
+		level = holtWintersAlpha*(values[i]-seasonIndex) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-previousLevel) + (1-holtWintersBeta)*trend
+		if seasonal {
+			seasons[i%season] = holtWintersGamma*(values[i]-level) + (1-holtWintersGamma)*seasons[i%season]
+		}
+	}
+
+	group.Seasonal = seasonal
+	group.TrendPerDay = trend / stepDays
+	if !seasonal && request.SeasonLength >= 2 {
+		group.Message = fmt.Sprintf("fewer than two seasons of %d snapshots, trend only", request.SeasonLength)
+	}
+
+	if group.Current >= request.Threshold {
+		group.setAbove(request.Threshold)
+		return
+	}
+
+	steps := int(request.Horizon / step)
+	for h := 1; h <= steps; h++ {
+		value := level + float64(h)*trend
+		if seasonal {
+			value += seasons[(n-1+h)%season]
+		}
+		if value >= request.Threshold {
+			group.setCrossing(last, float64(h)*stepDays, request)
+			return
+		}
+	}
+	group.setNotCrossing(request)
+}
+
+func (g *GroupForecast) setAbove(threshold float64) {
+	g.Status = ForecastAbove
+	g.Message = joinMessage(g.Message, fmt.Sprintf("already at %.1f%%, above the %.0f%% threshold", g.Current, threshold))
+}
+
+func (g *GroupForecast) setNotCrossing(request ForecastRequest) {
+	g.Status = ForecastNotCrossing
+	g.Message = joinMessage(g.Message, fmt.Sprintf("not expected to reach %.0f%% within %s", request.Threshold, formatDays(request.Horizon)))
+}
+
+func (g *GroupForecast) setCrossing(last time.Time, days float64, request ForecastRequest) {
+	if days*24*float64(time.Hour) > float64(request.Horizon) {
+		g.setNotCrossing(request)
+		return
+	}
+	crossesAt := last.Add(time.Duration(days * 24 * float64(time.Hour)))
+	g.Status = ForecastCrossing
+	g.CrossesAt = &crossesAt
+	g.DaysUntil = &days
+	g.Message = joinMessage(g.Message, fmt.Sprintf("expected to reach %.0f%% in %.1f days", request.Threshold, days))
+}
+
+// formatDays formats a duration in days
+func formatDays(duration time.Duration) string {
+	return fmt.Sprintf("%g days", math.Round(duration.Hours()/24*10)/10)
+}
+
+func joinMessage(first, second string) string {
+	if first == "" {
+		return second
+	}
+	return first + "; " + second
+}
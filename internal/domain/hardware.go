@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HardwareFacts are facts derived from the components assigned to a compute, keyed by
+// <component type>.<field>: manufacturer, model, name and every spec field, e.g.
+// cpu.manufacturer, gpu.model or gpu.vram_gb. A key holds one value per distinct component,
+// and component.type lists the component types.
+type HardwareFacts map[string][]string
+
+// FactComponentType is the fact listing the types of the assigned components
+const FactComponentType = "component.type"
+
+// add records a value under the key once
+func (f HardwareFacts) add(key, value string) {
+	if value == "" {
+		return
+	}
+	for _, existing := range f[key] {
+		if existing == value {
+			return
+		}
+	}
+	f[key] = append(f[key], value)
+}
+
+// Keys returns the fact keys in alphabetical order
+func (f HardwareFacts) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetHardwareFactsFromComponents derives the hardware facts of the components assigned to the compute
+func (c *Compute) GetHardwareFactsFromComponents(components []*Component, assignments []*ComputeComponent) HardwareFacts {
+	byID := make(map[string]*Component, len(components))
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	facts := make(HardwareFacts)
+	for _, assignment := range assignments {
+		if assignment.ComputeID != c.ID {
+			continue
+		}
+		component, ok := byID[assignment.ComponentID]
+		if !ok {
+			continue
+		}
+
+		prefix := string(component.Type) + "."
+		facts.add(FactComponentType, string(component.Type))
+		facts.add(prefix+"manufacturer", component.Manufacturer)
+		facts.add(prefix+"model", component.Model)
+		facts.add(prefix+"name", component.Name)
+		for field, value := range component.Specs {
+			facts.add(prefix+field, factValue(value))
+		}
+	}
+
+	for key := range facts {
+		sort.Strings(facts[key])
+	}
+	return facts
+}
+
+// factValue formats a spec value as a fact; numbers use the shortest representation
+func factValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// InheritHardwareFacts gives hosted computes without components of their own the hardware
+// facts of their parent, since they run on its hardware
+func InheritHardwareFacts(computes []*Compute) {
+	byID := make(map[string]*Compute, len(computes))
+	for _, compute := range computes {
+		byID[compute.ID] = compute
+	}
+
+	for _, compute := range computes {
+		if compute.ParentID == "" || len(compute.Facts) > 0 {
+			continue
+		}
+		if parent, ok := byID[compute.ParentID]; ok {
+			compute.Facts = parent.Facts
+		}
+	}
+}
+
+// MatchesFacts checks if the selector matches the hardware facts: a label or an In expression
+// matches when any value of the fact does, a NotIn expression when none does
+func (ts *TagSelector) MatchesFacts(facts HardwareFacts) bool {
+	for key, value := range ts.MatchLabels {
+		if !containsString(facts[key], value) {
+			return false
+		}
+	}
+
+	for _, expr := range ts.MatchExpressions {
+		if !expr.matchValues(facts[expr.Key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectorOperators are the operators of the selector syntax; longer tokens come first so
+// that ">=" is not read as ">"
+var selectorOperators = []struct {
+	token    string
+	operator Operator
+}{
+	{">=", OperatorGte},
+	{"<=", OperatorLte},
+	{"!=", OperatorNotIn},
+	{"=~", OperatorRegex},
+	{">", OperatorGt},
+	{"<", OperatorLt},
+	{"=", OperatorIn},
+}
+
+// ParseSelector parses a comma-separated selector: key=value, key!=value, key>n, key>=n, key<n,
+// key<=n, key=~regex, key (exists) and !key (does not exist). A key=value term whose value
+// holds *, ? or [ is a glob pattern. A value in double quotes may hold commas, e.g.
+// name=~"^r[0-9]{1,3}$".
+func ParseSelector(selector string) (TagSelector, error) {
+	var parsed TagSelector
+	for _, term := range splitSelector(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		index := strings.IndexAny(term, "!=<>")
+		switch {
+		case index < 0:
+			parsed.MatchExpressions = append(parsed.MatchExpressions, Expression{
+				Key:      term,
+				Operator: OperatorExists,
+			})
+			continue
+		case index == 0 && strings.IndexAny(term[1:], "!=<>") < 0:
+			parsed.MatchExpressions = append(parsed.MatchExpressions, Expression{
+				Key:      strings.TrimSpace(term[1:]),
+				Operator: OperatorDoesNotExist,
+			})
+			continue
+		}
+
+		key, rest := strings.TrimSpace(term[:index]), term[index:]
+		found := false
+		for _, op := range selectorOperators {
+			if !strings.HasPrefix(rest, op.token) {
+				continue
+			}
+			found = true
+			value := unquoteValue(strings.TrimSpace(rest[len(op.token):]))
+
+			switch {
+			case op.operator != OperatorIn:
+				parsed.MatchExpressions = append(parsed.MatchExpressions, Expression{
+					Key:      key,
+					Operator: op.operator,
+					Values:   []string{value},
+				})
+			case strings.ContainsAny(value, "*?["):
+				parsed.MatchExpressions = append(parsed.MatchExpressions, Expression{
+					Key:      key,
+					Operator: OperatorGlob,
+					Values:   []string{value},
+				})
+			default:
+				if parsed.MatchLabels == nil {
+					parsed.MatchLabels = make(map[string]string)
+				}
+				parsed.MatchLabels[key] = value
+			}
+			break
+		}
+		if !found {
+			return parsed, fmt.Errorf("invalid selector %q: malformed term %q", selector, term)
+		}
+	}
+
+	if err := parsed.Validate(); err != nil {
+		return parsed, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+
+	return parsed, nil
+}
+
+// splitSelector splits a selector on the commas outside double quotes
+func splitSelector(selector string) []string {
+	terms := make([]string, 0)
+	start := 0
+	quoted := false
+	for i, r := range selector {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			terms = append(terms, selector[start:i])
+			start = i + 1
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+// unquoteValue removes the double quotes around a selector value; the value is kept as written
+// inside them, backslashes included
+func unquoteValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,77 @@
+package domain
+
+import "fmt"
+
+// CanHostComputes reports whether VMs and VPS can be placed on the compute: a baremetal
+// compute that is not hosted itself
+func (c *Compute) CanHostComputes() bool {
+	return c.Type == ComputeTypeBaremetal && c.ParentID == ""
+}
+
+// ApplySize replaces the resources of a hosted compute by its size. Call before ApplyOvercommit.
+func (c *Compute) ApplySize() {
+	if len(c.Size) == 0 {
+		return
+	}
+	c.Resources = c.Size.Clone()
+	c.RawResources = nil
+}
+
+// Footprint returns the resources the compute carves out of its parent: its physical
+// resources, before its own overcommit
+func (c *Compute) Footprint() Resources {
+	if c.RawResources != nil {
+		return c.RawResources
+	}
+	return c.Resources
+}
+
+// ValidateParent checks that the compute can be hosted on the parent: only VMs and VPS are
+// hosted, on a baremetal compute that is not hosted itself
+func (c *Compute) ValidateParent(parent *Compute) error {
+	if parent == nil {
+		return nil
+	}
+	if c.Type != ComputeTypeVM && c.Type != ComputeTypeVPS {
+		return fmt.Errorf("only vm and vps computes can have a parent, %s is %s", c.Name, c.Type)
+	}
+	if parent.ID == c.ID {
+		return fmt.Errorf("compute %s cannot be its own parent", c.Name)
+	}
+	if !parent.CanHostComputes() {
+		return fmt.Errorf("parent %s must be a baremetal compute without a parent", parent.Name)
+	}
+	return nil
+}
+
+// RollUpHosted sets the Hosted resources of every compute to the sum of the footprints of the
+// computes it hosts. Resources must already be populated, with overcommit applied.
+func RollUpHosted(computes []*Compute) {
+	byID := make(map[string]*Compute, len(computes))
+	for _, compute := range computes {
+		compute.Hosted = nil
+		byID[compute.ID] = compute
+	}
+
+	for _, compute := range computes {
+		if compute.ParentID == "" {
+			continue
+		}
+		parent, ok := byID[compute.ParentID]
+		if !ok {
+			continue
+		}
+		parent.Hosted = parent.Hosted.Add(compute.Footprint())
+	}
+}
+
+// HostedComputes returns the computes hosted on the compute with the given ID
+func HostedComputes(computes []*Compute, parentID string) []*Compute {
+	hosted := make([]*Compute, 0)
+	for _, compute := range computes {
+		if compute.ParentID == parentID {
+			hosted = append(hosted, compute)
+		}
+	}
+	return hosted
+}
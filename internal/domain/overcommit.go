@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// OvercommitPolicy sets default overcommit ratios for every compute matching its tags.
+// A ratio of 4.0 for "cores" lets services reserve four times the physical cores,
+// a ratio below 1.0 keeps headroom free.
+type OvercommitPolicy struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Tags        map[string]string  `json:"tags"`   // Computes must have all tags (empty matches every compute)
+	Ratios      map[string]float64 `json:"ratios"` // Resource key to ratio
+	Priority    int                `json:"priority"`
+	Description string             `json:"description,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// Validate checks the policy fields
+func (p *OvercommitPolicy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Ratios) == 0 {
+		return fmt.Errorf("at least one ratio is required")
+	}
+	return ValidateOvercommit(p.Ratios)
+}
+
+// ValidateOvercommit checks that every ratio is positive
+func ValidateOvercommit(ratios map[string]float64) error {
+	for key, ratio := range ratios {
+		if ratio <= 0 {
+			return fmt.Errorf("overcommit ratio for %s must be greater than 0, got %g", key, ratio)
+		}
+	}
+	return nil
+}
+
+// EffectiveOvercommit resolves the overcommit ratio of every resource key of the compute.
+// The compute's own ratio wins, then the first matching policy (lowest priority, then name).
+// Keys without a ratio are left out and count as 1.0.
+func (c *Compute) EffectiveOvercommit(policies []*OvercommitPolicy) map[string]float64 {
+	ratios := make(map[string]float64)
+
+	sorted := make([]*OvercommitPolicy, len(policies))
+	copy(sorted, policies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if !c.MatchesTags(sorted[i].Tags) {
+			continue
+		}
+		for key, ratio := range sorted[i].Ratios {
+			ratios[key] = ratio
+		}
+	}
+
+	for key, ratio := range c.Overcommit {
+		ratios[key] = ratio
+	}
+
+	return ratios
+}
+
+// ApplyOvercommit keeps the physical resources in RawResources and scales Resources to the
+// effective capacity. Resources must already be populated from components.
+func (c *Compute) ApplyOvercommit(policies []*OvercommitPolicy) {
+	if c.RawResources != nil {
+		c.Resources = c.RawResources
+	}
+	c.RawResources = c.Resources
+	c.AppliedOvercommit = c.EffectiveOvercommit(policies)
+
+	if len(c.AppliedOvercommit) == 0 {
+		return
+	}
+
+	effective := make(Resources, len(c.Resources))
+	for key, value := range c.Resources {
+		ratio, ok := c.AppliedOvercommit[key]
+		if !ok {
+			effective[key] = value
+			continue
+		}
+		// Whole quantities (cores, MiB) stay whole
+		scaled := value * Quantity(ratio)
+		if value.IsWhole() {
+			scaled = Quantity(math.Floor(float64(scaled)))
+		}
+		effective[key] = scaled
+	}
+	c.Resources = effective
+}
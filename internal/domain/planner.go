@@ -1,9 +1,22 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // PlanRequest represents a capacity planning request
 type PlanRequest struct {
 	ServiceID   string      `json:"service_id"`
+	Replicas    int         `json:"replicas,omitempty"` // Number of instances to place (default 1)
+	Strategy    string      `json:"strategy,omitempty"` // Scoring strategy name (default balanced)
 	Constraints Constraints `json:"constraints,omitempty"`
+	WhatIf      *WhatIf     `json:"what_if,omitempty"` // Plan against hypothetical changes instead of the stored data
+	AsVM        bool        `json:"as_vm,omitempty"`   // Place a new VM per replica, sized to the reserved spec, on a baremetal host
+	Explain     bool        `json:"explain,omitempty"` // Report why each rejected compute was not a candidate
+	// Window the placement needs capacity for; only assignments running during it are counted
+	Schedule
 }
 
 // Constraints defines optional filters for capacity planning
@@ -18,9 +31,20 @@ type Constraints struct {
 // PlanResult contains the result of capacity planning
 type PlanResult struct {
 	Feasible        bool              `json:"feasible"`
+	Strategy        string            `json:"strategy,omitempty"` // Scoring strategy used to rank candidates
 	Candidates      []Candidate       `json:"candidates,omitempty"`
+	Placements      []Placement       `json:"placements,omitempty"` // One entry per requested replica
+	Spread          *SpreadResult     `json:"spread,omitempty"`     // Set when the service has a topology key
 	Recommendations []Recommendation  `json:"recommendations,omitempty"`
 	Message         string            `json:"message,omitempty"`
+	WhatIf          bool              `json:"what_if,omitempty"` // Plan was calculated on a hypothetical scenario
+	Reservation     ReservationBasis  `json:"reservation"`       // Spec each instance reserves (min, max or pNN)
+	VMSpec          Resources         `json:"vm_spec,omitempty"` // Size of each new VM, set for as_vm plans
+	Rejections      []Rejection       `json:"rejections,omitempty"` // Set for explain plans
+	Applied         bool              `json:"applied,omitempty"`
+	// Assignments created or updated when the plan is applied
+	Assignments []*Assignment `json:"assignments,omitempty"`
+	Schedule                          // Window planned for, given to the planned assignments
 }
 
 // Candidate represents a compute resource that can accommodate the service
@@ -29,6 +53,50 @@ type Candidate struct {
 	UtilizationAfter float64  `json:"utilization_after"` // 0.0-1.0
 	AvailableAfter  Resources `json:"available_after"`
 	Score           float64   `json:"score"` // Higher is better fit
+	ScoreTerms      []ScoreTerm `json:"score_terms,omitempty"` // Contribution of each scoring term to Score
+}
+
+// ScoreTerm is the contribution of a single scoring term to a candidate score
+type ScoreTerm struct {
+	Term  string  `json:"term"`  // Strategy name or e.g. "preferredAffinity[0]"
+	Delta float64 `json:"delta"` // Amount added to (or subtracted from) the score
+}
+
+// Rejection checks, in the order they are evaluated
+const (
+	RejectState     = "state"     // Compute is not active
+	RejectVMHost    = "vm-host"   // Compute cannot host new VMs
+	RejectCompute   = "compute"   // Another compute was requested
+	RejectProvider  = "provider"  // Provider constraint
+	RejectRegion    = "region"    // Region constraint
+	RejectTags      = "tags"      // Tag constraint
+	RejectPlacement = "placement" // Placement rules of the service (affinity, topology, spreadMax)
+	RejectResources = "resources" // Reserved spec does not fit the available resources
+	RejectStorage   = "storage"   // Storage requirements do not fit a storage group of the tier and redundancy
+	RejectBuffer    = "buffer"    // Placement would leave less than the minimum buffer
+)
+
+// Rejection explains why a compute is not a candidate: the first check it fails
+type Rejection struct {
+	Compute   *Compute  `json:"compute"`
+	Check     string    `json:"check"`
+	Reason    string    `json:"reason"`
+	Shortfall Resources `json:"shortfall,omitempty"` // Missing quantity per resource key, for resources rejections
+}
+
+// Placement is the compute selected for a single replica of a multi-replica plan
+type Placement struct {
+	Replica       int      `json:"replica"` // 1-based replica index
+	Compute       *Compute `json:"compute"`
+	TopologyValue string   `json:"topology_value,omitempty"` // Value of the topology tag on the compute
+	Score         float64  `json:"score"`
+}
+
+// SpreadResult describes how replicas are distributed across topology domains
+type SpreadResult struct {
+	TopologyKey string         `json:"topology_key"`
+	Domains     map[string]int `json:"domains"`   // Instances per topology value, including existing assignments
+	Satisfied   bool           `json:"satisfied"` // True when every planned replica landed on a distinct value
 }
 
 // Recommendation suggests what to purchase if no capacity is available
@@ -37,6 +105,11 @@ type Recommendation struct {
 	Spec      Resources   `json:"spec"`
 	Quantity  int         `json:"quantity"`
 	Rationale string      `json:"rationale"`
+
+	// Hardware build from the component catalog (per host)
+	Parts          []RecommendedPart `json:"parts,omitempty"`
+	TotalResources Resources         `json:"total_resources,omitempty"` // Derived resources of the build
+	Headroom       Resources         `json:"headroom,omitempty"`        // Total resources minus the required spec
 }
 
 // CapacityPlanner handles capacity planning logic
@@ -44,6 +117,12 @@ type CapacityPlanner struct {
 	computes    []*Compute
 	services    []*Service
 	assignments []*Assignment
+	components  []*Component        // Catalog used for hardware build recommendations
+	rules       []*DerivationRule   // Derive resources of recommended builds and hypothetical computes (nil: defaults)
+	policies    []*OvercommitPolicy // Applied to hypothetical computes of what-if scenarios
+	reservation ReservationBasis    // Spec each instance reserves (default max)
+	held        []*Reservation      // Reservations held on the computes, held again on what-if copies
+	heldAt      time.Time
 }
 
 // NewCapacityPlanner creates a new capacity planner
@@ -52,11 +131,51 @@ func NewCapacityPlanner(computes []*Compute, services []*Service, assignments []
 		computes:    computes,
 		services:    services,
 		assignments: assignments,
+		reservation: DefaultReservation,
 	}
 }
 
+// SetComponents sets the component catalog used to recommend hardware builds
+func (cp *CapacityPlanner) SetComponents(components []*Component) {
+	cp.components = components
+}
+
+// SetDerivationRules sets the rules deriving resources from components
+func (cp *CapacityPlanner) SetDerivationRules(rules []*DerivationRule) {
+	cp.rules = rules
+}
+
+// SetReservation sets the spec each service instance reserves, for allocations and fit checks alike
+func (cp *CapacityPlanner) SetReservation(basis ReservationBasis) {
+	cp.reservation = basis
+}
+
+// Reservation returns the reservation basis used by the planner
+func (cp *CapacityPlanner) Reservation() ReservationBasis {
+	return cp.reservation
+}
+
+// SetOvercommitPolicies sets the overcommit policies applied to hypothetical computes
+func (cp *CapacityPlanner) SetOvercommitPolicies(policies []*OvercommitPolicy) {
+	cp.policies = policies
+}
+
 // Plan evaluates capacity for a service
 func (cp *CapacityPlanner) Plan(request PlanRequest) (*PlanResult, error) {
+	if !request.WhatIf.IsEmpty() {
+		planner, err := cp.WithWhatIf(request.WhatIf)
+		if err != nil {
+			return nil, err
+		}
+
+		request.WhatIf = nil
+		result, err := planner.Plan(request)
+		if result != nil {
+			result.WhatIf = true
+		}
+		return result, err
+	}
+
 	// Find the service
 	var service *Service
 	for _, s := range cp.services {
@@ -68,205 +187,127 @@ func (cp *CapacityPlanner) Plan(request PlanRequest) (*PlanResult, error) {
 
 	if service == nil {
 		return &PlanResult{
-			Feasible: false,
-			Message:  "service not found",
+			Feasible:    false,
+			Message:     "service not found",
+			Reservation: cp.reservation,
 		}, nil
 	}
 
-	// Filter compute resources
-	candidates := make([]Candidate, 0)
+	replicas := request.Replicas
+	if replicas <= 0 {
+		replicas = 1
+	}
 
-	for _, compute := range cp.computes {
-		// Skip inactive compute
-		if compute.State != ComputeStateActive {
-			continue
-		}
+	strategy, err := GetScoringStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
 
-		// Apply constraint filters
-		if request.Constraints.ComputeID != "" && compute.ID != request.Constraints.ComputeID {
-			continue
-		}
-		if request.Constraints.Provider != "" && compute.Provider != request.Constraints.Provider {
-			continue
-		}
-		if request.Constraints.Region != "" && compute.Region != request.Constraints.Region {
-			continue
-		}
-		if len(request.Constraints.Tags) > 0 && !compute.MatchesTags(request.Constraints.Tags) {
-			continue
-		}
+	// Build services map for resource calculation
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
 
-		// Check placement rules (skip if specific compute requested)
-		if request.Constraints.ComputeID == "" && !service.CanPlaceOn(compute, cp.assignments) {
-			continue
+	// Only count the assignments running during the window of the plan
+	assignments := ScheduledAssignments(cp.assignments, request.Schedule)
+
+	candidates := cp.rankCandidates(service, request, strategy, assignments, servicesMap)
+
+	if len(candidates) == 0 {
+		// No candidates found, generate recommendations
+		recommendations := cp.generateRecommendations(service, replicas)
+
+		result := &PlanResult{
+			Feasible:        false,
+			Strategy:        strategy.Name(),
+			Recommendations: recommendations,
+			Message:         "no suitable compute resources found, recommendations generated",
+			Reservation:     cp.reservation,
+			Schedule:        request.Schedule,
+		}
+		if request.Explain {
+			result.Rejections = cp.explainRejections(service, request, assignments, servicesMap)
 		}
+		return result, nil
+	}
 
-		// Build services map for resource calculation
-		servicesMap := make(map[string]*Service)
-		for _, svc := range cp.services {
-			servicesMap[svc.ID] = svc
+	placements, spread, working := cp.placeReplicas(service, request, strategy, replicas, assignments, servicesMap)
+
+	result := &PlanResult{
+		Feasible:   len(placements) == replicas,
+		Strategy:   strategy.Name(),
+		Candidates: candidates,
+		Placements: placements,
+		Spread:      spread,
+		Message:     "found suitable compute resources",
+		Reservation: cp.reservation,
+		Schedule:    request.Schedule,
+	}
+	if request.AsVM {
+		result.VMSpec = cp.reservation.Spec(service)
+		result.Message = "found hosts for new VMs sized to the reserved spec"
+	}
+	if request.Explain {
+		// Explain the replica that could not be placed, with the replicas placed before it
+		// counted, or else why the computes left out of the candidates were rejected
+		if len(placements) < replicas {
+			result.Rejections = cp.explainRejections(service, request, working, servicesMap)
+		} else {
+			result.Rejections = cp.explainRejections(service, request, assignments, servicesMap)
 		}
+	}
 
-		// Calculate available resources
-		allocated := compute.GetAllocatedResources(cp.assignments, servicesMap)
-		available := compute.GetAvailableResources(allocated)
+	if !result.Feasible {
+		result.Recommendations = cp.generateRecommendations(service, replicas-len(placements))
+		result.Message = fmt.Sprintf("only %d of %d replicas could be placed, recommendations generated", len(placements), replicas)
+		return result, nil
+	}
 
-		// Check if service min spec fits
-		if !CanFitResources(service.MinSpec, available) {
-			continue
-		}
+	if spread != nil && !spread.Satisfied {
+		result.Message = fmt.Sprintf("found suitable compute resources, but topology spread on %q not met: %d replicas placed across %d distinct values",
+			spread.TopologyKey, replicas, distinctTopologyValues(placements))
+	}
 
-		// Apply buffer constraint
-		if request.Constraints.MinBuffer > 0 {
-			// Check if placing this service would leave enough buffer
-			tempAllocated := make(Resources)
-			for k, v := range allocated {
-				tempAllocated[k] = v
-			}
-			for k, v := range service.MinSpec {
-				if existing, ok := tempAllocated[k]; ok {
-					// Handle type conversions for both int and float64
-					switch e := existing.(type) {
-					case int:
-						switch val := v.(type) {
-						case int:
-							tempAllocated[k] = e + val
-						case float64:
-							tempAllocated[k] = e + int(val)
-						}
-					case float64:
-						switch val := v.(type) {
-						case int:
-							tempAllocated[k] = e + float64(val)
-						case float64:
-							tempAllocated[k] = e + val
-						}
-					}
-				} else {
-					tempAllocated[k] = v
-				}
-			}
+	return result, nil
+}
 
-			// Calculate utilization after placement
-			totalUtilization := 0.0
-			resourceCount := 0
-			for key, total := range compute.Resources {
-				if alloc, ok := tempAllocated[key]; ok {
-					switch t := total.(type) {
-					case int:
-						if t > 0 {
-							switch a := alloc.(type) {
-							case int:
-								totalUtilization += float64(a) / float64(t)
-								resourceCount++
-							case float64:
-								totalUtilization += a / float64(t)
-								resourceCount++
-							}
-						}
-					case float64:
-						if t > 0 {
-							switch a := alloc.(type) {
-							case int:
-								totalUtilization += float64(a) / t
-								resourceCount++
-							case float64:
-								totalUtilization += a / t
-								resourceCount++
-							}
-						}
-					}
-				}
-			}
+// rankCandidates returns the computes able to host one more instance of the service,
+// sorted by the strategy score (highest first). Assignments are passed explicitly so that replicas
+// already planned in the same request are counted as allocated.
+func (cp *CapacityPlanner) rankCandidates(service *Service, request PlanRequest, strategy ScoringStrategy, assignments []*Assignment, servicesMap map[string]*Service) []Candidate {
+	// Filter compute resources
+	candidates := make([]Candidate, 0)
 
-			avgUtilization := 0.0
-			if resourceCount > 0 {
-				avgUtilization = totalUtilization / float64(resourceCount)
-			}
+	// Resources one more instance reserves
+	reserved := cp.reservation.Spec(service)
 
-			if avgUtilization > (1.0 - request.Constraints.MinBuffer) {
-				continue
-			}
+	for _, compute := range cp.computes {
+		allocated, available, rejection := cp.checkCandidate(service, compute, request, reserved, assignments, servicesMap)
+		if rejection != nil {
+			continue
 		}
 
-		// Calculate score (prefer lower utilization for better headroom)
+		// Calculate utilization after placement for scoring
 		totalUtilization := 0.0
 		resourceCount := 0
 		availableAfter := make(Resources)
+		utilizationByKey := make(map[string]float64)
 
 		for key, total := range compute.Resources {
-			allocAfter := allocated[key]
-			if minReq, ok := service.MinSpec[key]; ok {
-				// Add min spec to allocated
-				switch total.(type) {
-				case int:
-					currentAlloc := 0
-					// Handle type mismatches in allocated
-					switch a := allocAfter.(type) {
-					case int:
-						currentAlloc = a
-					case float64:
-						currentAlloc = int(a)
-					}
-					// Handle both int and float64 from JSON in minReq
-					switch m := minReq.(type) {
-					case int:
-						allocAfter = currentAlloc + m
-					case float64:
-						allocAfter = currentAlloc + int(m)
-					}
-				case float64:
-					currentAlloc := 0.0
-					// Handle type mismatches in allocated
-					switch a := allocAfter.(type) {
-					case int:
-						currentAlloc = float64(a)
-					case float64:
-						currentAlloc = a
-					}
-					// Handle both int and float64 from JSON in minReq
-					switch m := minReq.(type) {
-					case int:
-						allocAfter = currentAlloc + float64(m)
-					case float64:
-						allocAfter = currentAlloc + m
-					}
-				}
+			alloc, allocatedKey := allocated[key]
+			req, reservedKey := reserved[key]
+			if total <= 0 || (!allocatedKey && !reservedKey) {
+				continue
 			}
 
-			// Calculate utilization
-			switch totalVal := total.(type) {
-			case int:
-				if totalVal > 0 {
-					switch a := allocAfter.(type) {
-					case int:
-						util := float64(a) / float64(totalVal)
-						totalUtilization += util
-						resourceCount++
-						availableAfter[key] = totalVal - a
-					case float64:
-						util := a / float64(totalVal)
-						totalUtilization += util
-						resourceCount++
-						availableAfter[key] = totalVal - int(a)
-					}
-				}
-			case float64:
-				if totalVal > 0 {
-					switch a := allocAfter.(type) {
-					case int:
-						util := float64(a) / totalVal
-						totalUtilization += util
-						resourceCount++
-						availableAfter[key] = totalVal - float64(a)
-					case float64:
-						util := a / totalVal
-						totalUtilization += util
-						resourceCount++
-						availableAfter[key] = totalVal - a
-					}
-				}
-			}
+			// Add reserved spec to allocated
+			allocAfter := alloc + req
+			util := float64(allocAfter / total)
+			totalUtilization += util
+			utilizationByKey[key] = util
+			resourceCount++
+			availableAfter[key] = total - allocAfter
 		}
 
 		avgUtilization := 0.0
@@ -274,16 +315,38 @@ func (cp *CapacityPlanner) Plan(request PlanRequest) (*PlanResult, error) {
 			avgUtilization = totalUtilization / float64(resourceCount)
 		}
 
-		// Score: prefer balanced utilization (not too empty, not too full)
-		// Ideal target is around 60-70% utilization
-		targetUtilization := 0.65
-		score := 100.0 - (100.0 * abs(avgUtilization-targetUtilization))
+		// Score with the selected strategy
+		score := strategy.Score(ScoreInput{
+			Service:          service,
+			Compute:          compute,
+			Reserved:         reserved,
+			Available:        available,
+			AvailableAfter:   availableAfter,
+			Utilization:      utilizationByKey,
+			UtilizationAfter: avgUtilization,
+		})
+		scoreTerms := []ScoreTerm{{Term: strategy.Name(), Delta: score}}
+
+		// Apply weighted preferred placement terms (never exclude a compute)
+		for i, term := range service.Placement.PreferredAffinity {
+			if term.Matches(compute, assignments, cp.computes, servicesMap) {
+				score += float64(term.Weight)
+				scoreTerms = append(scoreTerms, ScoreTerm{Term: fmt.Sprintf("preferredAffinity[%d]", i), Delta: float64(term.Weight)})
+			}
+		}
+		for i, term := range service.Placement.PreferredAntiAffinity {
+			if term.Matches(compute, assignments, cp.computes, servicesMap) {
+				score -= float64(term.Weight)
+				scoreTerms = append(scoreTerms, ScoreTerm{Term: fmt.Sprintf("preferredAntiAffinity[%d]", i), Delta: -float64(term.Weight)})
+			}
+		}
 
 		candidates = append(candidates, Candidate{
 			Compute:          compute,
 			UtilizationAfter: avgUtilization,
 			AvailableAfter:   availableAfter,
 			Score:            score,
+			ScoreTerms:       scoreTerms,
 		})
 	}
 
@@ -296,33 +359,261 @@ func (cp *CapacityPlanner) Plan(request PlanRequest) (*PlanResult, error) {
 		}
 	}
 
-	if len(candidates) > 0 {
-		return &PlanResult{
-			Feasible:   true,
-			Candidates: candidates,
-			Message:    "found suitable compute resources",
-		}, nil
+	return candidates
+}
+
+// checkCandidate runs the checks a compute must pass to host one more instance of the service
+// and returns its allocated and available resources, or the first check it fails
+func (cp *CapacityPlanner) checkCandidate(service *Service, compute *Compute, request PlanRequest, reserved Resources, assignments []*Assignment, servicesMap map[string]*Service) (Resources, Resources, *Rejection) {
+	reject := func(check, reason string) (Resources, Resources, *Rejection) {
+		return nil, nil, &Rejection{Compute: compute, Check: check, Reason: reason}
+	}
+
+	// Skip inactive compute
+	if compute.State != ComputeStateActive {
+		return reject(RejectState, fmt.Sprintf("compute is %s", compute.State))
+	}
+
+	// New VMs are carved out of baremetal hosts
+	if request.AsVM && !compute.CanHostComputes() {
+		return reject(RejectVMHost, "only baremetal computes without a parent host new VMs")
+	}
+
+	// Apply constraint filters
+	if request.Constraints.ComputeID != "" && compute.ID != request.Constraints.ComputeID {
+		return reject(RejectCompute, "another compute was requested")
+	}
+	if request.Constraints.Provider != "" && compute.Provider != request.Constraints.Provider {
+		return reject(RejectProvider, fmt.Sprintf("provider %s is not %s", compute.Provider, request.Constraints.Provider))
+	}
+	if request.Constraints.Region != "" && compute.Region != request.Constraints.Region {
+		return reject(RejectRegion, fmt.Sprintf("region %s is not %s", compute.Region, request.Constraints.Region))
+	}
+	if len(request.Constraints.Tags) > 0 && !compute.MatchesTags(request.Constraints.Tags) {
+		return reject(RejectTags, "compute tags do not match the tag constraint")
+	}
+
+	// Check placement rules (skip if specific compute requested)
+	if request.Constraints.ComputeID == "" {
+		if err := service.CheckPlacement(compute, assignments, cp.computes, servicesMap); err != nil {
+			return reject(RejectPlacement, err.Error())
+		}
+	}
+
+	// Calculate available resources
+	allocated := compute.GetAllocatedResources(assignments, servicesMap, cp.reservation)
+	available := compute.GetAvailableResources(allocated)
+
+	// Check if the reserved spec fits
+	if !CanFitResources(reserved, available) {
+		shortfall := reserved.Shortfall(available)
+		parts := make([]string, 0, len(shortfall))
+		for _, key := range shortfall.Keys() {
+			parts = append(parts, fmt.Sprintf("%s by %s", key, shortfall[key]))
+		}
+		return nil, nil, &Rejection{
+			Compute:   compute,
+			Check:     RejectResources,
+			Reason:    "short of " + strings.Join(parts, ", "),
+			Shortfall: shortfall,
+		}
+	}
+
+	// Check that each storage requirement fits a storage group of its tier and redundancy
+	storage, err := compute.CheckStorage(service, 1, assignments, servicesMap)
+	if err != nil {
+		return reject(RejectStorage, err.Error())
+	}
+
+	// The storage requirements also take tier capacity that specs may claim (e.g. nvme in min_spec)
+	if required := reserved.Add(storage); !CanFitResources(required, available) {
+		shortfall := required.Shortfall(available)
+		parts := make([]string, 0, len(shortfall))
+		for _, key := range shortfall.Keys() {
+			parts = append(parts, fmt.Sprintf("%s by %s", key, shortfall[key]))
+		}
+		return nil, nil, &Rejection{
+			Compute:   compute,
+			Check:     RejectStorage,
+			Reason:    "storage requirements short of " + strings.Join(parts, ", "),
+			Shortfall: shortfall,
+		}
+	}
+
+	// Apply buffer constraint
+	if request.Constraints.MinBuffer > 0 {
+		// Check if placing this service would leave enough buffer
+		tempAllocated := allocated.Add(reserved)
+
+		// Calculate utilization after placement
+		totalUtilization := 0.0
+		resourceCount := 0
+		for key, total := range compute.Resources {
+			if alloc, ok := tempAllocated[key]; ok && total > 0 {
+				totalUtilization += float64(alloc / total)
+				resourceCount++
+			}
+		}
+
+		avgUtilization := 0.0
+		if resourceCount > 0 {
+			avgUtilization = totalUtilization / float64(resourceCount)
+		}
+
+		if avgUtilization > (1.0 - request.Constraints.MinBuffer) {
+			return reject(RejectBuffer, fmt.Sprintf("utilization after placement %.1f%% leaves less than the %.0f%% buffer",
+				avgUtilization*100, request.Constraints.MinBuffer*100))
+		}
+	}
+
+	return allocated, available, nil
+}
+
+// explainRejections returns the first failing check of every compute that cannot host one
+// more instance of the service, including computes where spreadMax is reached when a specific
+// compute was requested
+func (cp *CapacityPlanner) explainRejections(service *Service, request PlanRequest, assignments []*Assignment, servicesMap map[string]*Service) []Rejection {
+	reserved := cp.reservation.Spec(service)
+
+	rejections := make([]Rejection, 0)
+	for _, compute := range cp.computes {
+		_, _, rejection := cp.checkCandidate(service, compute, request, reserved, assignments, servicesMap)
+		if rejection == nil && service.Placement.SpreadMax > 0 && service.instancesOn(compute.ID, assignments) >= service.Placement.SpreadMax {
+			rejection = &Rejection{
+				Compute: compute,
+				Check:   RejectPlacement,
+				Reason:  fmt.Sprintf("spreadMax %d reached on compute", service.Placement.SpreadMax),
+			}
+		}
+		if rejection != nil {
+			rejections = append(rejections, *rejection)
+		}
+	}
+
+	return rejections
+}
+
+// placeReplicas selects a compute for each replica, starting from the given assignments.
+// After every pick the replica is added to a working copy of the assignments so capacity and
+// SpreadMax account for it; the working copy is returned so later plans can build on it.
+// When the service has a topology key, the topology value with the fewest instances is preferred.
+func (cp *CapacityPlanner) placeReplicas(service *Service, request PlanRequest, strategy ScoringStrategy, replicas int, base []*Assignment, servicesMap map[string]*Service) ([]Placement, *SpreadResult, []*Assignment) {
+	topologyKey := service.Placement.TopologyKey
+
+	working := make([]*Assignment, len(base))
+	copy(working, base)
+
+	// Count existing instances of the service per topology value
+	var spread *SpreadResult
+	if topologyKey != "" {
+		spread = &SpreadResult{
+			TopologyKey: topologyKey,
+			Domains:     make(map[string]int),
+		}
+		for _, assignment := range base {
+			if assignment.ServiceID != service.ID {
+				continue
+			}
+			for _, compute := range cp.computes {
+				if compute.ID == assignment.ComputeID {
+					if value, ok := compute.TopologyValue(topologyKey); ok {
+						spread.Domains[value] += assignmentQuantity(assignment)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	placements := make([]Placement, 0, replicas)
+
+	for i := 0; i < replicas; i++ {
+		candidates := cp.rankCandidates(service, request, strategy, working, servicesMap)
+
+		var best *Candidate
+		for j := range candidates {
+			candidate := &candidates[j]
+
+			// SpreadMax applies even when a specific compute was requested
+			if service.Placement.SpreadMax > 0 && service.instancesOn(candidate.Compute.ID, working) >= service.Placement.SpreadMax {
+				continue
+			}
+
+			if best == nil {
+				best = candidate
+				continue
+			}
+
+			// Candidates are sorted by score, so only a less used topology value can win
+			if spread != nil && spread.Domains[topologyValue(candidate.Compute, topologyKey)] < spread.Domains[topologyValue(best.Compute, topologyKey)] {
+				best = candidate
+			}
+		}
+
+		if best == nil {
+			break
+		}
+
+		placement := Placement{
+			Replica: i + 1,
+			Compute: best.Compute,
+			Score:   best.Score,
+		}
+		if spread != nil {
+			placement.TopologyValue = topologyValue(best.Compute, topologyKey)
+			spread.Domains[placement.TopologyValue]++
+		}
+		placements = append(placements, placement)
+
+		working = append(working, &Assignment{
+			ServiceID: service.ID,
+			ComputeID: best.Compute.ID,
+			Quantity:  1,
+		})
 	}
 
-	// No candidates found, generate recommendations
-	recommendations := cp.generateRecommendations(service)
+	if spread != nil {
+		spread.Satisfied = len(placements) == replicas && distinctTopologyValues(placements) == replicas
+	}
 
-	return &PlanResult{
-		Feasible:        false,
-		Recommendations: recommendations,
-		Message:         "no suitable compute resources found, recommendations generated",
-	}, nil
+	return placements, spread, working
 }
 
-func (cp *CapacityPlanner) generateRecommendations(service *Service) []Recommendation {
+// topologyValue returns the compute's topology value, or an empty string when unset
+func topologyValue(compute *Compute, key string) string {
+	value, _ := compute.TopologyValue(key)
+	return value
+}
+
+// distinctTopologyValues counts the distinct topology values used by placements
+func distinctTopologyValues(placements []Placement) int {
+	values := make(map[string]bool)
+	for _, p := range placements {
+		values[p.TopologyValue] = true
+	}
+	return len(values)
+}
+
+// generateRecommendations suggests what to purchase to host the replicas that could not be placed
+func (cp *CapacityPlanner) generateRecommendations(service *Service, unplaced int) []Recommendation {
 	recommendations := make([]Recommendation, 0)
 
-	// Recommend based on max spec (user already set their desired max)
-	spec := make(Resources)
-	for key, value := range service.MaxSpec {
-		spec[key] = value
+	if unplaced < 1 {
+		unplaced = 1
 	}
 
+	// Hosts needed when SpreadMax limits instances per compute
+	hosts := 1
+	perHost := unplaced
+	if service.Placement.SpreadMax > 0 && unplaced > service.Placement.SpreadMax {
+		perHost = service.Placement.SpreadMax
+		hosts = (unplaced + perHost - 1) / perHost
+	}
+
+	// Recommend based on the reserved spec, as used for placement, and the storage requirements
+	instance := cp.reservation.Spec(service).Add(service.StorageSpec())
+	spec := instance.Scale(float64(perHost))
+
 	// Check affinity for preferred type
 	preferredType := ComputeTypeBaremetal // Default to baremetal
 	for _, selector := range service.Placement.Affinity {
@@ -336,11 +627,20 @@ func (cp *CapacityPlanner) generateRecommendations(service *Service) []Recommend
 		}
 	}
 
+	// Baremetal hosts can be built from the component catalog
+	if preferredType == ComputeTypeBaremetal {
+		// The build may split the instances of each host over several smaller hosts
+		if build := cp.recommendBuild(instance, perHost, service.mirroredTiers()); build != nil {
+			build.Quantity *= hosts
+			recommendations = append(recommendations, *build)
+		}
+	}
+
 	recommendations = append(recommendations, Recommendation{
 		Type:      preferredType,
 		Spec:      spec,
-		Quantity:  1,
-		Rationale: "based on service max spec",
+		Quantity:  hosts,
+		Rationale: fmt.Sprintf("based on service spec reserved under %s", cp.reservation),
 	})
 
 	return recommendations
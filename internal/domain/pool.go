@@ -0,0 +1,313 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PoolType is the storage stack a pool is built with
+type PoolType string
+
+const (
+	PoolTypeZFS PoolType = "zfs" // ZFS pool
+	PoolTypeLVM PoolType = "lvm" // LVM volume group
+)
+
+// Pool is a storage pool, such as a ZFS pool or an LVM volume group, built on RAID groups of a
+// compute. Volumes are carved out of it.
+type Pool struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"` // Unique per compute (e.g. tank, vg-data)
+	ComputeID   string    `json:"compute_id"`
+	Type        PoolType  `json:"type"`
+	RaidGroups  []string  `json:"raid_groups"` // RAID groups of the compute the pool is built on
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate checks the pool fields
+func (p *Pool) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.ComputeID == "" {
+		return fmt.Errorf("compute_id is required")
+	}
+	switch p.Type {
+	case PoolTypeZFS, PoolTypeLVM:
+	default:
+		return fmt.Errorf("unknown pool type %q (use zfs or lvm)", p.Type)
+	}
+	if len(p.RaidGroups) == 0 {
+		return fmt.Errorf("raid_groups is required")
+	}
+	seen := make(map[string]bool)
+	for _, group := range p.RaidGroups {
+		if group == "" {
+			return fmt.Errorf("raid_groups: empty group")
+		}
+		if seen[group] {
+			return fmt.Errorf("raid_groups: %s is listed twice", group)
+		}
+		seen[group] = true
+	}
+	return nil
+}
+
+// Volume is storage carved out of a pool: a ZFS dataset or zvol, or an LVM logical volume.
+// A volume provisioned for a service names the storage requirement it provides.
+type Volume struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"` // Unique per pool
+	PoolID      string    `json:"pool_id"`
+	Size        Quantity  `json:"size"`                  // In GB
+	ServiceID   string    `json:"service_id,omitempty"`  // Service the volume is provisioned for
+	Requirement string    `json:"requirement,omitempty"` // Name of the storage requirement of the service it provides
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON accepts the size in GB or as a quantity string with a unit, e.g. "2TB"
+func (v *Volume) UnmarshalJSON(data []byte) error {
+	type volume Volume
+	var raw struct {
+		volume
+		Size json.RawMessage `json:"size"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	size, err := parseStorageSize(raw.Size)
+	if err != nil {
+		return err
+	}
+	*v = Volume(raw.volume)
+	v.Size = size
+	return nil
+}
+
+// Validate checks the volume fields
+func (v *Volume) Validate() error {
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if v.PoolID == "" {
+		return fmt.Errorf("pool_id is required")
+	}
+	if v.Size <= 0 {
+		return fmt.Errorf("size must be positive")
+	}
+	if v.Requirement != "" && v.ServiceID == "" {
+		return fmt.Errorf("requirement needs a service_id")
+	}
+	return nil
+}
+
+// CheckRequirement returns an error when the service has no storage requirement named after
+// the requirement of the volume, or the volume is smaller than it
+func (v *Volume) CheckRequirement(service *Service) error {
+	if v.Requirement == "" {
+		return nil
+	}
+	for _, requirement := range service.Storage {
+		if requirement.Name != v.Requirement {
+			continue
+		}
+		if v.Size < requirement.Size {
+			return fmt.Errorf("volume of %s GB is smaller than storage requirement %s (%s GB)", v.Size, requirement.Name, requirement.Size)
+		}
+		return nil
+	}
+	return fmt.Errorf("service %s has no storage requirement named %s", service.Name, v.Requirement)
+}
+
+// PoolUsage is the capacity of a pool and the space its volumes use
+type PoolUsage struct {
+	Pool       *Pool      `json:"pool"`
+	Tier       string     `json:"tier"`
+	Redundancy Redundancy `json:"redundancy"` // Least redundant RAID group of the pool
+	Disks      int        `json:"disks"`
+	Capacity   Quantity   `json:"capacity"` // Usable capacity of its RAID groups in GB
+	Used       Quantity   `json:"used"`     // Sum of its volumes in GB
+	Free       Quantity   `json:"free"`
+	Volumes    []*Volume  `json:"volumes"`
+	Error      string     `json:"error,omitempty"` // Why part of the pool is missing, e.g. a RAID group that cannot be built
+}
+
+// BuildPoolUsage sums the usable capacity of the RAID groups of the pool, from the storage
+// layout of its compute, and the volumes carved out of it
+func BuildPoolUsage(pool *Pool, layouts []StorageLayout, volumes []*Volume) PoolUsage {
+	usage := PoolUsage{
+		Pool:       pool,
+		Redundancy: RedundancyMirrored,
+		Volumes:    make([]*Volume, 0),
+	}
+
+	for _, name := range pool.RaidGroups {
+		layout, ok := findRaidLayout(layouts, name)
+		switch {
+		case !ok:
+			usage.setError(fmt.Sprintf("raid group %s not found", name))
+			continue
+		case layout.Error != "":
+			usage.setError(fmt.Sprintf("raid group %s: %s", name, layout.Error))
+			continue
+		case usage.Tier != "" && layout.Tier != usage.Tier:
+			usage.setError(fmt.Sprintf("raid group %s is %s, the pool is %s", name, layout.Tier, usage.Tier))
+			continue
+		}
+
+		usage.Tier = layout.Tier
+		usage.Disks += layout.Disks
+		usage.Capacity += layout.Usable
+		if redundancy := layout.RaidLevel.Redundancy(); redundancy.rank() < usage.Redundancy.rank() {
+			usage.Redundancy = redundancy
+		}
+	}
+	if usage.Tier == "" {
+		usage.Redundancy = RedundancyNone
+	}
+
+	for _, volume := range volumes {
+		if volume.PoolID == pool.ID {
+			usage.Volumes = append(usage.Volumes, volume)
+			usage.Used += volume.Size
+		}
+	}
+	usage.Free = usage.Capacity - usage.Used
+
+	return usage
+}
+
+func (u *PoolUsage) setError(message string) {
+	if u.Error == "" {
+		u.Error = message
+	}
+}
+
+// CheckPool returns an error when the pool cannot be built on the storage layout of its
+// compute: a RAID group is missing, cannot be built, has another tier than the others or
+// already belongs to another pool of the compute
+func CheckPool(pool *Pool, layouts []StorageLayout, pools []*Pool) error {
+	for _, other := range pools {
+		if other.ID == pool.ID || other.ComputeID != pool.ComputeID {
+			continue
+		}
+		for _, group := range pool.RaidGroups {
+			if containsString(other.RaidGroups, group) {
+				return fmt.Errorf("raid group %s already belongs to pool %s", group, other.Name)
+			}
+		}
+	}
+
+	if usage := BuildPoolUsage(pool, layouts, nil); usage.Error != "" {
+		return fmt.Errorf("%s", usage.Error)
+	}
+	return nil
+}
+
+// CheckPoolsKept returns an error when a change of the storage layout of a compute, from before
+// to after, breaks one of its pools: a RAID group the pool is built on can no longer be built, or
+// the pool no longer holds its volumes
+func CheckPoolsKept(pools []*Pool, before, after []StorageLayout, volumes []*Volume) error {
+	for _, pool := range pools {
+		previous := BuildPoolUsage(pool, before, volumes)
+		next := BuildPoolUsage(pool, after, volumes)
+		if next.Error != "" && previous.Error == "" {
+			return fmt.Errorf("pool %s: %s", pool.Name, next.Error)
+		}
+		if next.Free < 0 && next.Free < previous.Free {
+			return fmt.Errorf("pool %s would have %s GB for %s GB of volumes", pool.Name, next.Capacity, next.Used)
+		}
+	}
+	return nil
+}
+
+// CheckVolume returns an error when the volume does not fit in the free space of the pool; a
+// volume already in the pool may grow into its own space
+func (u PoolUsage) CheckVolume(volume *Volume) error {
+	free := u.Free
+	for _, existing := range u.Volumes {
+		if existing.ID == volume.ID {
+			free += existing.Size
+		}
+	}
+	if volume.Size > free {
+		return fmt.Errorf("pool %s has %s GB free, volume needs %s GB", u.Pool.Name, free, volume.Size)
+	}
+	return nil
+}
+
+// PoolStorageGroups replaces the RAID groups that pools are built on with one storage group per
+// pool, its volumes already allocated, so that planning uses the free space of the pool
+func PoolStorageGroups(groups []StorageGroup, pools []*Pool, volumes []*Volume) []StorageGroup {
+	if len(pools) == 0 {
+		return groups
+	}
+
+	pooled := make(map[string]bool)
+	for _, pool := range pools {
+		for _, group := range pool.RaidGroups {
+			pooled[group] = true
+		}
+	}
+
+	result := make([]StorageGroup, 0, len(groups))
+	for _, group := range groups {
+		if group.RaidLevel == RaidLevelNone || !pooled[group.Name] {
+			result = append(result, group)
+		}
+	}
+
+	for _, pool := range pools {
+		var merged *StorageGroup
+		for _, group := range groups {
+			if group.RaidLevel == RaidLevelNone || !containsString(pool.RaidGroups, group.Name) {
+				continue
+			}
+			if merged == nil {
+				merged = &StorageGroup{
+					Name:       pool.Name,
+					Tier:       group.Tier,
+					RaidLevel:  group.RaidLevel,
+					Redundancy: group.Redundancy,
+					Pool:       pool.Type,
+				}
+			} else if group.RaidLevel != merged.RaidLevel {
+				merged.RaidLevel = ""
+			}
+			if group.Redundancy.rank() < merged.Redundancy.rank() {
+				merged.Redundancy = group.Redundancy
+			}
+			merged.Disks += group.Disks
+			merged.Capacity += group.Capacity
+		}
+		if merged == nil {
+			continue
+		}
+
+		for _, volume := range volumes {
+			if volume.PoolID == pool.ID {
+				merged.Volumes = append(merged.Volumes, volume)
+				merged.Allocated += volume.Size
+			}
+		}
+		result = append(result, *merged)
+	}
+
+	return result
+}
+
+// findRaidLayout returns the layout of a RAID group, leaving out standalone disks
+func findRaidLayout(layouts []StorageLayout, group string) (StorageLayout, bool) {
+	for _, layout := range layouts {
+		if layout.Group == group && layout.RaidLevel != RaidLevelNone {
+			return layout, true
+		}
+	}
+	return StorageLayout{}, false
+}
@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a resource amount expressed in the canonical unit of its resource key
+// (e.g. memory in MiB, storage in GB, bandwidth_gbps in Gbps, cores as a count).
+type Quantity float64
+
+// Resource dimensions
+const (
+	DimensionCount     = "count"     // Cores, GPUs and other countable resources
+	DimensionBytes     = "bytes"     // Memory and storage
+	DimensionBandwidth = "bandwidth" // Network throughput in bits per second
+)
+
+// Unit is the canonical unit a resource key is stored in
+type Unit struct {
+	Name      string  `json:"name"`      // e.g. "MiB", "GB", "Gbps", "count"
+	Dimension string  `json:"dimension"` // count, bytes or bandwidth
+	Factor    float64 `json:"factor"`    // Size of one unit in the base unit of the dimension (1, byte, bit/s)
+}
+
+// Canonical units
+var (
+	UnitCount = Unit{Name: "count", Dimension: DimensionCount, Factor: 1}
+	UnitMiB   = Unit{Name: "MiB", Dimension: DimensionBytes, Factor: 1 << 20}
+	UnitGiB   = Unit{Name: "GiB", Dimension: DimensionBytes, Factor: 1 << 30}
+	UnitTiB   = Unit{Name: "TiB", Dimension: DimensionBytes, Factor: 1 << 40}
+	UnitGB    = Unit{Name: "GB", Dimension: DimensionBytes, Factor: 1e9}
+	UnitTB    = Unit{Name: "TB", Dimension: DimensionBytes, Factor: 1e12}
+	UnitMbps  = Unit{Name: "Mbps", Dimension: DimensionBandwidth, Factor: 1e6}
+	UnitGbps  = Unit{Name: "Gbps", Dimension: DimensionBandwidth, Factor: 1e9}
+)
+
+// unitSuffixes maps the suffixes accepted when parsing a quantity to their size in the base unit
+var unitSuffixes = map[string]map[string]float64{
+	DimensionCount: {
+		"":  1,
+		"m": 1e-3,
+		"k": 1e3,
+	},
+	DimensionBytes: {
+		"B": 1,
+		"K": 1e3, "KB": 1e3, "M": 1e6, "MB": 1e6, "G": 1e9, "GB": 1e9,
+		"T": 1e12, "TB": 1e12, "P": 1e15, "PB": 1e15,
+		"Ki": 1 << 10, "KiB": 1 << 10, "Mi": 1 << 20, "MiB": 1 << 20, "Gi": 1 << 30, "GiB": 1 << 30,
+		"Ti": 1 << 40, "TiB": 1 << 40, "Pi": 1 << 50, "PiB": 1 << 50,
+	},
+	DimensionBandwidth: {
+		"bps": 1, "kbps": 1e3, "mbps": 1e6, "gbps": 1e9, "tbps": 1e12,
+	},
+}
+
+// UnitForKey returns the canonical unit of a resource key. Built-in keys and aliases use the
+// unit of the resource registry, other keys their suffix: _mbps and _gbps are bandwidth, _mb is MiB, _gb and _tb are GiB and TiB for memory
+// keys (containing "ram" or "mem") and GB and TB otherwise. Any other key is a count.
+func UnitForKey(key string) Unit {
+	if unit, ok := builtinUnit(key); ok {
+		return unit
+	}
+
+	memory := strings.Contains(key, "ram") || strings.Contains(key, "mem")
+	switch {
+	case strings.HasSuffix(key, "_gbps"):
+		return UnitGbps
+	case strings.HasSuffix(key, "_mbps"):
+		return UnitMbps
+	case strings.HasSuffix(key, "_mb"):
+		return UnitMiB
+	case strings.HasSuffix(key, "_gb") && memory:
+		return UnitGiB
+	case strings.HasSuffix(key, "_gb"):
+		return UnitGB
+	case strings.HasSuffix(key, "_tb") && memory:
+		return UnitTiB
+	case strings.HasSuffix(key, "_tb"):
+		return UnitTB
+	}
+	return UnitCount
+}
+
+var quantityPattern = regexp.MustCompile(`^([+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?)\s*([A-Za-z]*)$`)
+
+// ParseQuantity parses a quantity such as "4Gi", "500m", "2TB" or "10Gbps" for a resource key
+// and converts it to the canonical unit of the key. A plain number is already canonical.
+func ParseQuantity(key, value string) (Quantity, error) {
+	match := quantityPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid quantity %q for %s", value, key)
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q for %s: %w", value, key, err)
+	}
+
+	suffix := match[2]
+	if suffix == "" {
+		return Quantity(number), nil
+	}
+
+	unit := UnitForKey(key)
+	lookup := suffix
+	if unit.Dimension == DimensionBandwidth {
+		lookup = strings.ToLower(suffix)
+	}
+	size, ok := unitSuffixes[unit.Dimension][lookup]
+	if !ok {
+		return 0, fmt.Errorf("unit %q is not a %s unit, %s is measured in %s", suffix, unit.Dimension, key, unit.Name)
+	}
+
+	return Quantity(number * size / unit.Factor), nil
+}
+
+// IsWhole reports whether the quantity has no fractional part
+func (q Quantity) IsWhole() bool {
+	return q == Quantity(math.Trunc(float64(q)))
+}
+
+// String formats the quantity without exponent (3456000, not 3.456e+06)
+func (q Quantity) String() string {
+	return strconv.FormatFloat(float64(q), 'f', -1, 64)
+}
+
+// Float returns the quantity as a float64
+func (q Quantity) Float() float64 {
+	return float64(q)
+}
+
+// Get returns the quantity of a key, 0 when missing
+func (r Resources) Get(key string) Quantity {
+	return r[key]
+}
+
+// Clone returns a copy of the resources
+func (r Resources) Clone() Resources {
+	clone := make(Resources, len(r))
+	for key, value := range r {
+		clone[key] = value
+	}
+	return clone
+}
+
+// Add returns the sum of both resources, per key
+func (r Resources) Add(other Resources) Resources {
+	sum := r.Clone()
+	for key, value := range other {
+		sum[key] += value
+	}
+	return sum
+}
+
+// Sub returns r minus other, for the keys of r
+func (r Resources) Sub(other Resources) Resources {
+	difference := r.Clone()
+	for key := range difference {
+		difference[key] -= other[key]
+	}
+	return difference
+}
+
+// Scale returns every quantity multiplied by factor
+func (r Resources) Scale(factor float64) Resources {
+	scaled := make(Resources, len(r))
+	for key, value := range r {
+		scaled[key] = value * Quantity(factor)
+	}
+	return scaled
+}
+
+// Fits reports whether every required quantity is available. A key missing from
+// available never fits.
+func (r Resources) Fits(available Resources) bool {
+	for key, required := range r {
+		value, ok := available[key]
+		if !ok || required > value {
+			return false
+		}
+	}
+	return true
+}
+
+// Shortfall returns, for every required quantity that is not available, how much is missing.
+// A key missing from available is missing entirely.
+func (r Resources) Shortfall(available Resources) Resources {
+	shortfall := make(Resources)
+	for key, required := range r {
+		if value := available[key]; required > value {
+			shortfall[key] = required - value
+		}
+	}
+	return shortfall
+}
+
+// Keys returns the resource keys in alphabetical order
+func (r Resources) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for key := range r {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UnmarshalJSON accepts numbers in the canonical unit of each key, or quantity strings with a unit
+func (r *Resources) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*r = nil
+		return nil
+	}
+
+	resources := make(Resources, len(raw))
+	for key, value := range raw {
+		var number float64
+		if err := json.Unmarshal(value, &number); err == nil {
+			resources[key] = Quantity(number)
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return fmt.Errorf("resource %s must be a number or a quantity string such as \"4Gi\"", key)
+		}
+		quantity, err := ParseQuantity(key, text)
+		if err != nil {
+			return err
+		}
+		resources[key] = quantity
+	}
+
+	*r = resources
+	return nil
+}
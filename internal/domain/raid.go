@@ -0,0 +1,322 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultRaidSpans is the number of spans of a RAID50 or RAID60 group that does not set it
+const DefaultRaidSpans = 2
+
+// RaidLevels returns the RAID levels of a RAID group
+func RaidLevels() []RaidLevel {
+	return []RaidLevel{RaidLevel0, RaidLevel1, RaidLevel5, RaidLevel6, RaidLevel10, RaidLevel50, RaidLevel60, RaidLevelJBOD}
+}
+
+// ParseRaidLevel converts a numeric or named RAID level (5, raid5, jbod, none) to its canonical form
+func ParseRaidLevel(level string) (RaidLevel, error) {
+	normalized := strings.ToLower(strings.TrimSpace(level))
+	if normalized == "" || normalized == string(RaidLevelNone) {
+		return RaidLevelNone, nil
+	}
+	for _, known := range RaidLevels() {
+		if normalized == string(known) || "raid"+normalized == string(known) {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("unknown RAID level %q (use 0, 1, 5, 6, 10, 50, 60, jbod or none)", level)
+}
+
+// spanned checks if the level stripes over several parity spans
+func (l RaidLevel) spanned() bool {
+	return l == RaidLevel50 || l == RaidLevel60
+}
+
+// ValidateRaid checks the RAID fields of a component assignment on their own; the group as a
+// whole is checked by CheckRaidGroup
+func (cc *ComputeComponent) ValidateRaid() error {
+	if _, err := ParseRaidLevel(string(cc.RaidLevel)); err != nil {
+		return err
+	}
+	switch cc.RaidRole {
+	case "", RaidRoleMember, RaidRoleSpare:
+	default:
+		return fmt.Errorf("unknown RAID role %q (use member or spare)", cc.RaidRole)
+	}
+
+	raid := cc.RaidLevel != "" && cc.RaidLevel != RaidLevelNone
+	switch {
+	case raid && cc.RaidGroup == "":
+		return fmt.Errorf("raid_group is required with a RAID level")
+	case !raid && cc.RaidGroup != "":
+		return fmt.Errorf("raid_level is required with a raid_group")
+	case cc.RaidRole == RaidRoleSpare && !raid:
+		return fmt.Errorf("hot spares must belong to a RAID group")
+	case cc.RaidRole == RaidRoleSpare && cc.RaidLevel.Redundancy() == RedundancyNone:
+		return fmt.Errorf("%s groups have no redundancy to rebuild with a hot spare", cc.RaidLevel)
+	case cc.RaidSpans < 0:
+		return fmt.Errorf("raid_spans must not be negative")
+	case cc.RaidSpans > 0 && !cc.RaidLevel.spanned():
+		return fmt.Errorf("raid_spans only applies to raid50 and raid60")
+	}
+	return nil
+}
+
+// CheckRaidGroup returns an error when the RAID group of the assignment cannot be built once the
+// assignment is added to the components assigned to the compute
+func (c *Compute) CheckRaidGroup(assignment *ComputeComponent, components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) error {
+	if assignment.RaidGroup == "" || assignment.RaidLevel == "" || assignment.RaidLevel == RaidLevelNone {
+		return nil
+	}
+
+	candidate := make([]*ComputeComponent, 0, len(assignments)+1)
+	candidate = append(candidate, assignments...)
+	candidate = append(candidate, assignment)
+
+	for _, layout := range c.GetStorageLayoutFromComponents(components, candidate, rules) {
+		if layout.Group != assignment.RaidGroup || layout.RaidLevel == RaidLevelNone {
+			continue
+		}
+		if layout.Error != "" {
+			return fmt.Errorf("raid group %s: %s", layout.Group, layout.Error)
+		}
+		return nil
+	}
+	return fmt.Errorf("component has no storage capacity under the derivation rules, only storage disks can be in a RAID group")
+}
+
+// StorageLayout describes a storage group of a compute: a RAID group, or the standalone disks of
+// one tier, with the raw capacity of its members and how much of it is usable
+type StorageLayout struct {
+	Group     string              `json:"group"` // RAID group, or the tier for standalone disks
+	Tier      string              `json:"tier"`
+	RaidLevel RaidLevel           `json:"raid_level"`
+	Spans     int                 `json:"spans,omitempty"` // RAID50 and RAID60
+	Disks     int                 `json:"disks"`           // Members, hot spares excluded
+	Spares    int                 `json:"spares,omitempty"`
+	Raw       Quantity            `json:"raw"`              // Capacity of the members in GB
+	Usable    Quantity            `json:"usable"`           // Capacity volumes can use in GB
+	Parity    Quantity            `json:"parity"`           // Capacity holding parity or mirror copies in GB
+	Unused    Quantity            `json:"unused,omitempty"` // Capacity of members larger than the smallest one
+	Spare     Quantity            `json:"spare,omitempty"`  // Capacity of the hot spares in GB
+	Members   []StorageLayoutDisk `json:"members"`
+	Error     string              `json:"error,omitempty"` // Why the group cannot be built; it then has no usable capacity
+}
+
+// StorageLayoutDisk is a component assigned to a storage group
+type StorageLayoutDisk struct {
+	ComponentID string   `json:"component_id"`
+	Component   string   `json:"component"`
+	Quantity    int      `json:"quantity"`
+	Size        Quantity `json:"size"` // Per disk in GB
+	Role        RaidRole `json:"role"`
+}
+
+// GetStorageLayoutFromComponents returns the layout of the storage groups of the components
+// assigned to the compute, using the derivation rules with raid aggregation. nil rules use
+// DefaultDerivationRules.
+func (c *Compute) GetStorageLayoutFromComponents(components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) []StorageLayout {
+	if rules == nil {
+		rules = DefaultDerivationRules()
+	}
+	_, layouts := deriveFromComponents(c.ID, components, assignments, rules)
+	return layouts
+}
+
+type storageAssignment struct {
+	component   *Component
+	size        float64
+	quantity    int
+	raidLevel   RaidLevel
+	storageType string
+	spans       int
+	role        RaidRole
+}
+
+// standaloneLayout lays out standalone disks of one storage type, all of their capacity usable
+func standaloneLayout(storageType string, disks []*storageAssignment) StorageLayout {
+	layout := StorageLayout{
+		Group:     storageType,
+		Tier:      storageType,
+		RaidLevel: RaidLevelNone,
+		Members:   make([]StorageLayoutDisk, 0, len(disks)),
+	}
+	raw := 0.0
+	for _, sa := range disks {
+		layout.Members = append(layout.Members, sa.disk())
+		layout.Disks += sa.quantity
+		raw += sa.size * float64(sa.quantity)
+	}
+	layout.Raw = Quantity(raw)
+	layout.Usable = layout.Raw
+	return layout
+}
+
+// raidLayout lays out a RAID group. Every member counts as the smallest one, the remainder of
+// larger members is unused. A group that cannot be built gets an error and no usable capacity.
+func raidLayout(group string, members []*storageAssignment) StorageLayout {
+	layout := StorageLayout{
+		Group:     group,
+		Tier:      members[0].storageType,
+		RaidLevel: members[0].raidLevel,
+		Members:   make([]StorageLayoutDisk, 0, len(members)),
+	}
+
+	levels := make(map[string]bool)
+	tiers := make(map[string]bool)
+	spans := make(map[int]bool)
+	var disks, spares []float64
+	for _, sa := range members {
+		layout.Members = append(layout.Members, sa.disk())
+		levels[string(sa.raidLevel)] = true
+		tiers[sa.storageType] = true
+		if sa.raidLevel.spanned() {
+			span := sa.spans
+			if span == 0 {
+				span = DefaultRaidSpans
+			}
+			spans[span] = true
+			layout.Spans = span
+		}
+		for i := 0; i < sa.quantity; i++ {
+			if sa.role == RaidRoleSpare {
+				spares = append(spares, sa.size)
+			} else {
+				disks = append(disks, sa.size)
+			}
+		}
+	}
+
+	raw, smallest := 0.0, 0.0
+	for i, size := range disks {
+		raw += size
+		if i == 0 || size < smallest {
+			smallest = size
+		}
+	}
+	spare := 0.0
+	for _, size := range spares {
+		spare += size
+	}
+	layout.Disks = len(disks)
+	layout.Spares = len(spares)
+	layout.Raw = Quantity(raw)
+	layout.Spare = Quantity(spare)
+
+	if err := checkRaidGroup(layout.RaidLevel, layout.Spans, disks, spares, smallest, levels, tiers, spans); err != nil {
+		layout.Error = err.Error()
+		return layout
+	}
+
+	n := float64(len(disks))
+	usable, parity := 0.0, 0.0
+	switch layout.RaidLevel {
+	case RaidLevel0:
+		usable = n * smallest
+	case RaidLevel1:
+		usable, parity = smallest, (n-1)*smallest
+	case RaidLevel5:
+		usable, parity = (n-1)*smallest, smallest
+	case RaidLevel6:
+		usable, parity = (n-2)*smallest, 2*smallest
+	case RaidLevel10:
+		usable, parity = n/2*smallest, n/2*smallest
+	case RaidLevel50:
+		s := float64(layout.Spans)
+		usable, parity = (n-s)*smallest, s*smallest
+	case RaidLevel60:
+		s := float64(layout.Spans)
+		usable, parity = (n-2*s)*smallest, 2*s*smallest
+	case RaidLevelJBOD:
+		usable = raw
+	}
+	layout.Usable = Quantity(usable)
+	layout.Parity = Quantity(parity)
+	layout.Unused = Quantity(raw - usable - parity)
+	return layout
+}
+
+// checkRaidGroup returns why a RAID group cannot be built from its member and spare disks
+func checkRaidGroup(level RaidLevel, spans int, disks, spares []float64, smallest float64, levels, tiers map[string]bool, spanCounts map[int]bool) error {
+	if len(levels) > 1 {
+		return fmt.Errorf("mixed RAID levels (%s)", joinKeys(levels))
+	}
+	if len(tiers) > 1 {
+		return fmt.Errorf("mixed storage types (%s)", joinKeys(tiers))
+	}
+	if len(spanCounts) > 1 {
+		return fmt.Errorf("members disagree on the number of spans")
+	}
+
+	n := len(disks)
+	switch level {
+	case RaidLevel0, RaidLevel1:
+		if n < 2 {
+			return fmt.Errorf("%s needs at least 2 disks, has %d", level, n)
+		}
+	case RaidLevel5:
+		if n < 3 {
+			return fmt.Errorf("raid5 needs at least 3 disks, has %d", n)
+		}
+	case RaidLevel6:
+		if n < 4 {
+			return fmt.Errorf("raid6 needs at least 4 disks, has %d", n)
+		}
+	case RaidLevel10:
+		if n < 4 || n%2 != 0 {
+			return fmt.Errorf("raid10 needs an even number of disks, at least 4, has %d", n)
+		}
+	case RaidLevel50, RaidLevel60:
+		perSpan := 3
+		if level == RaidLevel60 {
+			perSpan = 4
+		}
+		if spans < 2 {
+			return fmt.Errorf("%s needs at least 2 spans, has %d", level, spans)
+		}
+		if n%spans != 0 || n/spans < perSpan {
+			return fmt.Errorf("%s with %d spans needs a multiple of %d disks, at least %d per span, has %d", level, spans, spans, perSpan, n)
+		}
+	case RaidLevelJBOD:
+		if n < 1 {
+			return fmt.Errorf("jbod needs at least 1 disk")
+		}
+	default:
+		return fmt.Errorf("unknown RAID level %q", level)
+	}
+
+	if len(spares) > 0 && level.Redundancy() == RedundancyNone {
+		return fmt.Errorf("%s groups have no redundancy to rebuild with a hot spare", level)
+	}
+	for _, size := range spares {
+		if size < smallest {
+			return fmt.Errorf("hot spare of %s GB is smaller than the smallest member (%s GB)", Quantity(size), Quantity(smallest))
+		}
+	}
+	return nil
+}
+
+// disk describes the storage assignment as a layout member
+func (sa *storageAssignment) disk() StorageLayoutDisk {
+	role := sa.role
+	if role == "" {
+		role = RaidRoleMember
+	}
+	return StorageLayoutDisk{
+		ComponentID: sa.component.ID,
+		Component:   sa.component.Name,
+		Quantity:    sa.quantity,
+		Size:        Quantity(sa.size),
+		Role:        role,
+	}
+}
+
+func joinKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,371 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Rebalance goals
+const (
+	RebalanceGoalConsolidate = "consolidate" // Free whole computes by moving their assignments onto others
+	RebalanceGoalBalance     = "balance"     // Even out utilization across computes
+)
+
+// RebalanceRequest asks for rebalancing proposals over the current assignments
+type RebalanceRequest struct {
+	Goal     string `json:"goal,omitempty"`      // consolidate (default) or balance
+	Strategy string `json:"strategy,omitempty"`  // Target scoring (default binpack for consolidate, spread for balance)
+	MaxMoves int    `json:"max_moves,omitempty"` // Maximum instances moved per proposal (0 = no limit)
+}
+
+// RebalancePlan contains the proposals of a rebalance analysis
+type RebalancePlan struct {
+	Goal      string              `json:"goal"`
+	Strategy  string              `json:"strategy"`
+	Proposals []RebalanceProposal `json:"proposals"`
+	Applied   int                 `json:"applied,omitempty"` // Number of the proposal that was applied
+	Warnings  []string            `json:"warnings,omitempty"`
+	Message   string              `json:"message,omitempty"`
+	// Spec each instance reserves (min, max or pNN)
+	Reservation ReservationBasis `json:"reservation"`
+}
+
+// RebalanceProposal is a set of moves that can be applied as one batch
+type RebalanceProposal struct {
+	Number        int                 `json:"number"` // 1-based, used to apply the proposal
+	Description   string              `json:"description"`
+	Moves         []Move              `json:"moves"`
+	MoveCount     int                 `json:"move_count"` // Instances moved
+	FreedComputes []*Compute          `json:"freed_computes,omitempty"`
+	Utilization   []UtilizationChange `json:"utilization"` // Computes whose utilization changes
+	// Difference between the most and least utilized active computes, in percent
+	ImbalanceBefore float64 `json:"imbalance_before"`
+	ImbalanceAfter  float64 `json:"imbalance_after"`
+}
+
+// UtilizationChange is the utilization of a compute before and after a proposal, in percent
+type UtilizationChange struct {
+	Compute *Compute `json:"compute"`
+	Before  float64  `json:"before"`
+	After   float64  `json:"after"`
+}
+
+// Rebalance analyzes the current assignments and proposes moves that respect placement rules,
+// SpreadMax and capacity. Consolidation proposes, per compute, the moves that free it entirely
+// (fewest moves first). Balancing proposes one set of moves from the most to the least
+// utilized computes, stopping when no move reduces the imbalance.
+func (cp *CapacityPlanner) Rebalance(request RebalanceRequest) (*RebalancePlan, error) {
+	goal := request.Goal
+	if goal == "" {
+		goal = RebalanceGoalConsolidate
+	}
+
+	strategyName := request.Strategy
+	if strategyName == "" {
+		if goal == RebalanceGoalBalance {
+			strategyName = StrategySpread
+		} else {
+			strategyName = StrategyBinPack
+		}
+	}
+	strategy, err := GetScoringStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build services map for resource calculation
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
+
+	plan := &RebalancePlan{
+		Goal:        goal,
+		Strategy:    strategy.Name(),
+		Proposals:   make([]RebalanceProposal, 0),
+		Reservation: cp.reservation,
+	}
+
+	switch goal {
+	case RebalanceGoalConsolidate:
+		plan.Proposals = cp.consolidationProposals(strategy, request.MaxMoves, servicesMap)
+	case RebalanceGoalBalance:
+		if proposal := cp.balanceProposal(strategy, request.MaxMoves, servicesMap); proposal != nil {
+			plan.Proposals = append(plan.Proposals, *proposal)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rebalance goal %q (use %s or %s)", goal, RebalanceGoalConsolidate, RebalanceGoalBalance)
+	}
+
+	for i := range plan.Proposals {
+		plan.Proposals[i].Number = i + 1
+	}
+
+	if len(plan.Proposals) == 0 {
+		plan.Message = "no rebalancing moves found"
+	} else {
+		plan.Message = fmt.Sprintf("%d proposal(s)", len(plan.Proposals))
+	}
+
+	return plan, nil
+}
+
+// consolidationProposals proposes, for each used compute, the moves that empty it onto the other
+// used computes. Empty computes are not used as targets since that would not free a compute.
+// Hosts that carry VMs are skipped: moving assignments does not move the VMs, so the host would
+// not be freed and its instances could land on its own VMs.
+func (cp *CapacityPlanner) consolidationProposals(strategy ScoringStrategy, maxMoves int, servicesMap map[string]*Service) []RebalanceProposal {
+	used := make(map[string]bool)
+	for _, assignment := range cp.assignments {
+		used[assignment.ComputeID] = true
+	}
+
+	proposals := make([]RebalanceProposal, 0)
+	for _, compute := range cp.computes {
+		if compute.State != ComputeStateActive || !used[compute.ID] {
+			continue
+		}
+		if len(HostedComputes(cp.computes, compute.ID)) > 0 {
+			continue
+		}
+
+		targets := make([]*Compute, 0)
+		for _, other := range cp.computes {
+			if other.ID != compute.ID && used[other.ID] {
+				targets = append(targets, other)
+			}
+		}
+
+		moves, unmovable := cp.planEvacuation([]*Compute{compute}, targets, strategy, servicesMap)
+		if len(unmovable) > 0 || len(moves) == 0 {
+			continue
+		}
+
+		proposal := cp.buildProposal(moves, servicesMap)
+		if maxMoves > 0 && proposal.MoveCount > maxMoves {
+			continue
+		}
+		proposal.Description = fmt.Sprintf("free %s", compute.Name)
+		proposal.FreedComputes = []*Compute{compute}
+		proposals = append(proposals, proposal)
+	}
+
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].MoveCount < proposals[j].MoveCount
+	})
+
+	return proposals
+}
+
+// balanceProposal greedily moves single instances off the most utilized compute while the
+// imbalance between the most and least utilized computes keeps decreasing
+func (cp *CapacityPlanner) balanceProposal(strategy ScoringStrategy, maxMoves int, servicesMap map[string]*Service) *RebalanceProposal {
+	working := make([]*Assignment, len(cp.assignments))
+	copy(working, cp.assignments)
+
+	// Instances of stored assignments that have not been moved yet
+	movable := make(map[string]int)
+	for _, assignment := range cp.assignments {
+		movable[assignment.ID] = assignmentQuantity(assignment)
+	}
+
+	computesByID := make(map[string]*Compute)
+	for _, compute := range cp.computes {
+		computesByID[compute.ID] = compute
+	}
+
+	moves := make([]Move, 0)
+	count := 0
+
+	for maxMoves == 0 || count < maxMoves {
+		utilization := cp.utilizationByCompute(working, servicesMap)
+		current := imbalance(utilization)
+
+		// Most utilized compute first
+		sources := make([]string, 0, len(utilization))
+		for id := range utilization {
+			sources = append(sources, id)
+		}
+		sort.Slice(sources, func(i, j int) bool {
+			if utilization[sources[i]] != utilization[sources[j]] {
+				return utilization[sources[i]] > utilization[sources[j]]
+			}
+			return sources[i] < sources[j]
+		})
+		if len(sources) < 2 {
+			break
+		}
+		source := computesByID[sources[0]]
+
+		var bestMove *Move
+		var bestWorking []*Assignment
+		bestImbalance := current
+
+		for _, assignment := range cp.assignments {
+			if assignment.ComputeID != source.ID || movable[assignment.ID] == 0 {
+				continue
+			}
+			service, ok := servicesMap[assignment.ServiceID]
+			if !ok {
+				continue
+			}
+
+			without := removeInstance(working, assignment)
+			request := PlanRequest{ServiceID: service.ID, Strategy: strategy.Name()}
+			for _, candidate := range cp.rankCandidates(service, request, strategy, without, servicesMap) {
+				if candidate.Compute.ID == source.ID {
+					continue
+				}
+
+				next := append(append([]*Assignment{}, without...), &Assignment{
+					ServiceID: service.ID,
+					ComputeID: candidate.Compute.ID,
+					Quantity:  1,
+				})
+				after := imbalance(cp.utilizationByCompute(next, servicesMap))
+				if after < bestImbalance-0.01 {
+					bestImbalance = after
+					bestWorking = next
+					bestMove = &Move{
+						AssignmentID: assignment.ID,
+						ServiceID:    service.ID,
+						ServiceName:  service.Name,
+						From:         source,
+						To:           candidate.Compute,
+						Quantity:     1,
+					}
+				}
+			}
+		}
+
+		if bestMove == nil {
+			break
+		}
+
+		working = bestWorking
+		movable[bestMove.AssignmentID]--
+		moves = appendMoves(moves, &Assignment{ID: bestMove.AssignmentID}, servicesMap[bestMove.ServiceID], bestMove.From,
+			[]Placement{{Compute: bestMove.To}})
+		count++
+	}
+
+	if len(moves) == 0 {
+		return nil
+	}
+
+	proposal := cp.buildProposal(moves, servicesMap)
+	proposal.Description = fmt.Sprintf("even out utilization (imbalance %.0f%% → %.0f%%)", proposal.ImbalanceBefore, proposal.ImbalanceAfter)
+	return &proposal
+}
+
+// buildProposal calculates the move count and utilization changes of a set of moves
+func (cp *CapacityPlanner) buildProposal(moves []Move, servicesMap map[string]*Service) RebalanceProposal {
+	after := ApplyMoves(cp.assignments, moves)
+	utilBefore := cp.utilizationByCompute(cp.assignments, servicesMap)
+	utilAfter := cp.utilizationByCompute(after, servicesMap)
+
+	proposal := RebalanceProposal{
+		Moves:           moves,
+		ImbalanceBefore: imbalance(utilBefore),
+		ImbalanceAfter:  imbalance(utilAfter),
+	}
+	for _, move := range moves {
+		proposal.MoveCount += move.Quantity
+	}
+
+	for _, compute := range cp.computes {
+		before, okBefore := utilBefore[compute.ID]
+		afterValue, okAfter := utilAfter[compute.ID]
+		if !okBefore || !okAfter || abs(before-afterValue) < 0.01 {
+			continue
+		}
+		proposal.Utilization = append(proposal.Utilization, UtilizationChange{
+			Compute: compute,
+			Before:  before,
+			After:   afterValue,
+		})
+	}
+
+	return proposal
+}
+
+// utilizationByCompute returns the utilization percentage of every active compute with resources
+func (cp *CapacityPlanner) utilizationByCompute(assignments []*Assignment, servicesMap map[string]*Service) map[string]float64 {
+	utilization := make(map[string]float64)
+	for _, compute := range cp.computes {
+		if compute.State != ComputeStateActive || len(compute.Resources) == 0 {
+			continue
+		}
+		allocated := compute.GetAllocatedResources(assignments, servicesMap, cp.reservation)
+		utilization[compute.ID] = UtilizationPct(compute.Resources, allocated)
+	}
+	return utilization
+}
+
+// imbalance returns the difference between the highest and lowest utilization
+func imbalance(utilization map[string]float64) float64 {
+	first := true
+	var min, max float64
+	for _, value := range utilization {
+		if first || value < min {
+			min = value
+		}
+		if first || value > max {
+			max = value
+		}
+		first = false
+	}
+	return max - min
+}
+
+// removeInstance returns a copy of assignments with one instance of assignment removed
+func removeInstance(assignments []*Assignment, assignment *Assignment) []*Assignment {
+	result := make([]*Assignment, 0, len(assignments))
+	removed := false
+	for _, a := range assignments {
+		if !removed && a.ID == assignment.ID {
+			removed = true
+			if quantity := assignmentQuantity(a); quantity > 1 {
+				reduced := *a
+				reduced.Quantity = quantity - 1
+				result = append(result, &reduced)
+			}
+			continue
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// ApplyMoves returns a copy of assignments with the moves applied. Moved instances are added as
+// assignments without an ID on the target compute.
+func ApplyMoves(assignments []*Assignment, moves []Move) []*Assignment {
+	moved := make(map[string]int)
+	for _, move := range moves {
+		moved[move.AssignmentID] += move.Quantity
+	}
+
+	result := make([]*Assignment, 0, len(assignments)+len(moves))
+	for _, assignment := range assignments {
+		if count, ok := moved[assignment.ID]; ok {
+			remaining := assignmentQuantity(assignment) - count
+			if remaining > 0 {
+				reduced := *assignment
+				reduced.Quantity = remaining
+				result = append(result, &reduced)
+			}
+			continue
+		}
+		result = append(result, assignment)
+	}
+
+	for _, move := range moves {
+		result = append(result, &Assignment{
+			ServiceID: move.ServiceID,
+			ComputeID: move.To.ID,
+			Quantity:  move.Quantity,
+		})
+	}
+
+	return result
+}
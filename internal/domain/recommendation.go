@@ -0,0 +1,271 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// RecommendedPart is a catalog component and quantity in a recommended hardware build
+type RecommendedPart struct {
+	Component *Component `json:"component"`
+	Quantity  int        `json:"quantity"`
+	RaidLevel RaidLevel  `json:"raid_level,omitempty"`
+}
+
+// storageResourceKeys are resource keys produced by storage components (the key is the component type)
+var storageResourceKeys = map[string]bool{
+	"storage": true,
+	"nvme":    true,
+	"ssd":     true,
+	"hdd":     true,
+}
+
+// maxPartsPerHost bounds how many units of a component type one host takes: CPU sockets, DIMM
+// slots, NIC and GPU slots, and drive bays
+var maxPartsPerHost = map[string]int{
+	"cpu":     2,
+	"ram":     24,
+	"memory":  24,
+	"nic":     4,
+	"gpu":     8,
+	"storage": 24,
+	"nvme":    24,
+	"ssd":     24,
+	"hdd":     24,
+}
+
+// withinHostLimit checks that a quantity of the component type fits in one host
+func withinHostLimit(componentType ComponentType, quantity int) bool {
+	limit, ok := maxPartsPerHost[string(componentType)]
+	return !ok || quantity <= limit
+}
+
+// recommendBuild searches the component catalog for a host build whose derived resources cover
+// the required resources of instances instances, with mirrored storage for the mirrored tiers.
+// Part quantities stay within what one host takes; when the instances do not fit one host, they
+// are split over several identical hosts, set as the quantity of the recommendation, with the
+// spec and build per host. Returns nil when the catalog is empty.
+func (cp *CapacityPlanner) recommendBuild(instance Resources, instances int, mirrored map[string]bool) *Recommendation {
+	if len(cp.components) == 0 {
+		return nil
+	}
+	if instances < 1 {
+		instances = 1
+	}
+
+	// Most instances per host first, keeping the build that leaves the fewest keys uncovered
+	var best *Recommendation
+	bestUncovered := 0
+	for perHost := instances; perHost >= 1; perHost-- {
+		required := instance.Scale(float64(perHost))
+		build, uncovered := cp.buildHost(required, mirrored)
+		if best == nil || len(uncovered) < bestUncovered {
+			build.Quantity = (instances + perHost - 1) / perHost
+			best = build
+			bestUncovered = len(uncovered)
+		}
+		if len(uncovered) == 0 {
+			break
+		}
+	}
+
+	return best
+}
+
+// buildHost picks the parts of one host covering the required resources and returns the keys no
+// part covers within the limits of one host
+func (cp *CapacityPlanner) buildHost(required Resources, mirrored map[string]bool) (*Recommendation, []string) {
+	parts := make([]RecommendedPart, 0)
+	uncovered := make([]string, 0)
+
+	addPart := func(part *RecommendedPart, key string) {
+		if part == nil {
+			if getFloatValue(required, key) > 0 {
+				uncovered = append(uncovered, key)
+			}
+			return
+		}
+		parts = append(parts, *part)
+	}
+
+	// A host always needs CPU, RAM and a NIC, sized to the requirement when there is one
+	addPart(cp.pickPart([]string{"cpu"}, "cores", getFloatValue(required, "cores")), "cores")
+	addPart(cp.pickPart([]string{"ram", "memory"}, "memory", getFloatValue(required, "memory")), "memory")
+	addPart(cp.pickPart([]string{"nic"}, "bandwidth_gbps", getFloatValue(required, "bandwidth_gbps")), "bandwidth_gbps")
+
+	// GPUs cover both the GPU count and VRAM
+	if getFloatValue(required, "gpu") > 0 || getFloatValue(required, "vram") > 0 {
+		part := cp.pickPart([]string{"gpu"}, "vram", getFloatValue(required, "vram"))
+		if part != nil {
+			if minGPUs := int(getFloatValue(required, "gpu")); part.Quantity < minGPUs {
+				part.Quantity = minGPUs
+			}
+			if !withinHostLimit(part.Component.Type, part.Quantity) {
+				part = nil
+			}
+		} else {
+			part = cp.pickPart([]string{"gpu"}, "gpu", getFloatValue(required, "gpu"))
+		}
+		addPart(part, "gpu")
+	}
+
+	keys := make([]string, 0, len(required))
+	for key := range required {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch {
+		case storageResourceKeys[key]:
+			addPart(cp.pickStorage(key, getFloatValue(required, key), mirrored[key]), key)
+		case key == "cores", key == "memory", key == "bandwidth_gbps", key == "gpu", key == "vram":
+			// Handled above
+		default:
+			if getFloatValue(required, key) > 0 {
+				uncovered = append(uncovered, key)
+			}
+		}
+	}
+
+	total := deriveResources(parts, cp.rules)
+	headroom := total.Sub(required)
+
+	rationale := "component build from catalog covering the shortfall"
+	if len(uncovered) > 0 {
+		rationale = fmt.Sprintf("partial component build, no catalog components provide within one host: %s", strings.Join(uncovered, ", "))
+	}
+
+	return &Recommendation{
+		Type:           ComputeTypeBaremetal,
+		Spec:           required,
+		Quantity:       1,
+		Rationale:      rationale,
+		Parts:          parts,
+		TotalResources: total,
+		Headroom:       headroom,
+	}, uncovered
+}
+
+// pickPart chooses the catalog component of the given types, and the quantity of it, that covers
+// the needed amount of a resource with the least excess, within the units one host takes. With no
+// need, the smallest single unit is used.
+func (cp *CapacityPlanner) pickPart(types []string, key string, need float64) *RecommendedPart {
+	var best *RecommendedPart
+	bestExcess := 0.0
+
+	for _, component := range cp.components {
+		if !containsString(types, string(component.Type)) {
+			continue
+		}
+
+		perUnit := getFloatValue(deriveResources([]RecommendedPart{{Component: component, Quantity: 1}}, cp.rules), key)
+		if perUnit <= 0 {
+			continue
+		}
+
+		quantity := 1
+		if need > perUnit {
+			quantity = int(math.Ceil(need / perUnit))
+		}
+		if !withinHostLimit(component.Type, quantity) {
+			continue
+		}
+		excess := perUnit*float64(quantity) - need
+
+		if best == nil || excess < bestExcess || (excess == bestExcess && quantity < best.Quantity) {
+			best = &RecommendedPart{Component: component, Quantity: quantity}
+			bestExcess = excess
+		}
+	}
+
+	return best
+}
+
+// pickStorage chooses storage disks of the given type in a redundant layout: a RAID1 mirror
+// when one disk holds the need, otherwise RAID5 with one parity disk (minimum three disks), or
+// RAID10 (minimum four disks) when the storage must be mirrored, within the drive bays of one host
+func (cp *CapacityPlanner) pickStorage(key string, need float64, mirrored bool) *RecommendedPart {
+	if need <= 0 {
+		return nil
+	}
+
+	var best *RecommendedPart
+	bestExcess := 0.0
+
+	for _, component := range cp.components {
+		if string(component.Type) != key {
+			continue
+		}
+
+		size := getFloatValue(deriveResources([]RecommendedPart{{Component: component, Quantity: 1}}, cp.rules), key)
+		if size <= 0 {
+			continue
+		}
+
+		part := &RecommendedPart{Component: component, Quantity: 2, RaidLevel: RaidLevel1}
+		if need > size && mirrored {
+			quantity := 2 * int(math.Ceil(need/size))
+			if quantity < 4 {
+				quantity = 4
+			}
+			part = &RecommendedPart{Component: component, Quantity: quantity, RaidLevel: RaidLevel10}
+		} else if need > size {
+			quantity := int(math.Ceil(need/size)) + 1
+			if quantity < 3 {
+				quantity = 3
+			}
+			part = &RecommendedPart{Component: component, Quantity: quantity, RaidLevel: RaidLevel5}
+		}
+
+		if !withinHostLimit(component.Type, part.Quantity) {
+			continue
+		}
+		excess := getFloatValue(deriveResources([]RecommendedPart{*part}, cp.rules), key) - need
+		if excess < 0 {
+			continue
+		}
+
+		if best == nil || excess < bestExcess || (excess == bestExcess && part.Quantity < best.Quantity) {
+			best = part
+			bestExcess = excess
+		}
+	}
+
+	return best
+}
+
+// deriveResources computes the resources of a hypothetical compute built from parts,
+// using the same derivation (including RAID) as assigned components
+func deriveResources(parts []RecommendedPart, rules []*DerivationRule) Resources {
+	compute := &Compute{ID: "recommended-build"}
+	components := make([]*Component, 0, len(parts))
+	assignments := make([]*ComputeComponent, 0, len(parts))
+
+	for i, part := range parts {
+		components = append(components, part.Component)
+		assignment := &ComputeComponent{
+			ComputeID:   compute.ID,
+			ComponentID: part.Component.ID,
+			Quantity:    part.Quantity,
+		}
+		if part.RaidLevel != "" && part.RaidLevel != RaidLevelNone {
+			assignment.RaidLevel = part.RaidLevel
+			assignment.RaidGroup = fmt.Sprintf("recommended-%d", i)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return compute.GetTotalResourcesFromComponents(components, assignments, rules)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
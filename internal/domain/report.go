@@ -0,0 +1,224 @@
+package domain
+
+import "time"
+
+// CapacityReport summarizes utilization across all computes
+type CapacityReport struct {
+	TotalComputes      int                  `json:"total_computes"`
+	ActiveComputes     int                  `json:"active_computes"`
+	TotalServices      int                  `json:"total_services"`
+	TotalAssignments   int                  `json:"total_assignments"`
+	ComputeUtilization []ComputeUtilization `json:"compute_utilization"`
+	WhatIf             bool                 `json:"what_if,omitempty"` // Report was calculated on a hypothetical scenario
+	Reservation        ReservationBasis     `json:"reservation"`       // Spec each instance reserves (min, max or pNN)
+	At                 *time.Time           `json:"at,omitempty"`      // Instant of the report, set when not now
+}
+
+type ComputeUtilization struct {
+	Compute        *Compute            `json:"compute"`
+	TotalResources Resources           `json:"total_resources"`         // Effective capacity, after overcommit
+	RawResources   Resources           `json:"raw_resources,omitempty"` // Physical capacity, set when overcommit applies
+	Overcommit     map[string]float64  `json:"overcommit,omitempty"`    // Ratios applied to the physical capacity
+	Allocated      Resources           `json:"allocated"`
+	Available      Resources           `json:"available"`
+	UtilizationPct float64             `json:"utilization_pct"`
+	Statistics     *ResourceStatistics `json:"statistics,omitempty"`
+
+	// Hypervisor roll-up, set when the compute hosts VMs or VPS
+	HostedComputes  []string  `json:"hosted_computes,omitempty"`  // Names of the hosted computes
+	Hosted          Resources `json:"hosted,omitempty"`           // Resources carved out by the hosted computes, part of Allocated
+	HostedAllocated Resources `json:"hosted_allocated,omitempty"` // Resources reserved by services running on the hosted computes
+
+	// Resources held by capacity reservations, part of Allocated
+	Held Resources `json:"held,omitempty"`
+
+	// Storage groups and pools with the volumes and the storage of assigned services allocated,
+	// set when the storage comes from components
+	Storage []StorageGroup `json:"storage,omitempty"`
+}
+
+type ResourceStatistics struct {
+	Min    Resources `json:"min"`
+	Max    Resources `json:"max"`
+	Avg    Resources `json:"avg"`
+	Median Resources `json:"median"`
+}
+
+// BuildCapacityReport calculates the utilization of every compute, with allocations reserved
+// under the basis. Compute resources must already be populated from components, with overcommit applied,
+// hosted resources rolled up to their parent (see RollUpHosted) and reservations held (see HoldReservations).
+func BuildCapacityReport(computes []*Compute, services []*Service, assignments []*Assignment, basis ReservationBasis) *CapacityReport {
+	// Build services map for resource calculation
+	servicesMap := make(map[string]*Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+
+	// Calculate utilization for each compute
+	computeUtils := make([]ComputeUtilization, 0, len(computes))
+	activeCount := 0
+
+	for _, compute := range computes {
+		if compute.State == ComputeStateActive {
+			activeCount++
+		}
+
+		allocated := compute.GetAllocatedResources(assignments, servicesMap, basis)
+		available := compute.GetAvailableResources(allocated)
+
+		avgUtil := UtilizationPct(compute.Resources, allocated)
+
+		// Calculate statistics for this compute's assignments
+		computeAssignments := make([]*Assignment, 0)
+		for _, a := range assignments {
+			if a.ComputeID == compute.ID {
+				computeAssignments = append(computeAssignments, a)
+			}
+		}
+		stats := CalculateResourceStatistics(computeAssignments, servicesMap, basis)
+
+		utilization := ComputeUtilization{
+			Compute:        compute,
+			TotalResources: compute.Resources,
+			Allocated:      allocated,
+			Available:      available,
+			UtilizationPct: avgUtil,
+			Statistics:     stats,
+		}
+		if len(compute.Held) > 0 {
+			utilization.Held = compute.Held
+		}
+		if len(compute.StorageGroups) > 0 {
+			utilization.Storage = compute.AllocateStorage(computeAssignments, servicesMap)
+		}
+		if len(compute.AppliedOvercommit) > 0 {
+			utilization.RawResources = compute.RawResources
+			utilization.Overcommit = compute.AppliedOvercommit
+		}
+		if hosted := HostedComputes(computes, compute.ID); len(hosted) > 0 {
+			utilization.Hosted = compute.Hosted
+			utilization.HostedAllocated = make(Resources)
+			for _, child := range hosted {
+				utilization.HostedComputes = append(utilization.HostedComputes, child.Name)
+				childAllocated := child.GetAllocatedResources(assignments, servicesMap, basis)
+				utilization.HostedAllocated = utilization.HostedAllocated.Add(childAllocated)
+			}
+		}
+		computeUtils = append(computeUtils, utilization)
+	}
+
+	return &CapacityReport{
+		TotalComputes:      len(computes),
+		ActiveComputes:     activeCount,
+		TotalServices:      len(services),
+		TotalAssignments:   len(assignments),
+		ComputeUtilization: computeUtils,
+		Reservation:        basis,
+	}
+}
+
+// UtilizationPct returns the average utilization percentage over the allocated resource keys
+func UtilizationPct(total Resources, allocated Resources) float64 {
+	totalUtil := 0.0
+	resourceCount := 0
+
+	for key, totalValue := range total {
+		if alloc, ok := allocated[key]; ok && totalValue > 0 {
+			totalUtil += float64(alloc/totalValue) * 100
+			resourceCount++
+		}
+	}
+
+	if resourceCount == 0 {
+		return 0
+	}
+	return totalUtil / float64(resourceCount)
+}
+
+// CalculateResourceStatistics calculates min/max/avg/median for resources across assignments
+// All values are based on the spec reserved under the basis (max_spec by default)
+// Min = smallest reserved spec across all assignments
+// Max = sum of all reserved specs (total reserved by the assignments)
+// Avg = average reserved spec value
+// Median = median reserved spec value
+func CalculateResourceStatistics(assignments []*Assignment, servicesMap map[string]*Service, basis ReservationBasis) *ResourceStatistics {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	// Collect individual max values for statistics
+	maxValues := make(map[string][]float64)
+
+	for _, assignment := range assignments {
+		service, ok := servicesMap[assignment.ServiceID]
+		if !ok {
+			continue
+		}
+
+		quantity := assignment.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+
+		// Process the reserved spec
+		for key, value := range basis.Spec(service) {
+			maxValues[key] = append(maxValues[key], value.Float()*float64(quantity))
+		}
+	}
+
+	// Build final statistics
+	min := make(Resources)
+	max := make(Resources)
+	avg := make(Resources)
+	median := make(Resources)
+
+	for key, values := range maxValues {
+		if len(values) == 0 {
+			continue
+		}
+
+		// Min is the smallest max_spec value
+		minVal := values[0]
+		for _, v := range values {
+			if v < minVal {
+				minVal = v
+			}
+		}
+		min[key] = Quantity(minVal)
+
+		// Max is sum of all max_spec
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		max[key] = Quantity(sum)
+
+		// Average
+		avg[key] = Quantity(sum / float64(len(values)))
+
+		// Median (sort values)
+		sortedValues := make([]float64, len(values))
+		copy(sortedValues, values)
+		// Simple bubble sort for small arrays
+		for i := 0; i < len(sortedValues); i++ {
+			for j := i + 1; j < len(sortedValues); j++ {
+				if sortedValues[i] > sortedValues[j] {
+					sortedValues[i], sortedValues[j] = sortedValues[j], sortedValues[i]
+				}
+			}
+		}
+
+		if len(sortedValues)%2 == 0 {
+			median[key] = Quantity((sortedValues[len(sortedValues)/2-1] + sortedValues[len(sortedValues)/2]) / 2)
+		} else {
+			median[key] = Quantity(sortedValues[len(sortedValues)/2])
+		}
+	}
+
+	return &ResourceStatistics{
+		Min:    min,
+		Max:    max,
+		Avg:    avg,
+		Median: median,
+	}
+}
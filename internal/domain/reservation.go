@@ -0,0 +1,412 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReservationBasis selects how much of its spec each service instance reserves on a compute.
+// "min" reserves the min spec, "max" the max spec, and "pNN" a point between them
+// (p0 is the min spec, p100 the max spec, p75 three quarters of the way to the max spec).
+type ReservationBasis string
+
+// Reservation bases
+const (
+	ReservationMin ReservationBasis = "min"
+	ReservationMax ReservationBasis = "max"
+)
+
+// DefaultReservation is used when neither the server nor the request selects a basis
+const DefaultReservation = ReservationMax
+
+// ParseReservationBasis validates a reservation basis. An empty value returns the default basis.
+func ParseReservationBasis(value string) (ReservationBasis, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "":
+		return DefaultReservation, nil
+	case string(ReservationMin), string(ReservationMax):
+		return ReservationBasis(value), nil
+	}
+
+	if strings.HasPrefix(value, "p") {
+		percentile, err := strconv.ParseFloat(value[1:], 64)
+		if err == nil && percentile >= 0 && percentile <= 100 {
+			return ReservationBasis(value), nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown reservation basis %q (use min, max or p0-p100)", value)
+}
+
+// fraction returns the position of the basis between the min spec (0) and the max spec (1)
+func (b ReservationBasis) fraction() float64 {
+	switch b {
+	case ReservationMin:
+		return 0
+	case ReservationMax, "":
+		return 1
+	}
+	percentile, err := strconv.ParseFloat(strings.TrimPrefix(string(b), "p"), 64)
+	if err != nil {
+		return 1
+	}
+	return percentile / 100
+}
+
+// Spec returns the resources one instance of the service reserves. Keys missing from the
+// max spec use the min spec value, keys missing from the min spec start from 0.
+// Whole quantities are rounded up so a reservation never undercounts.
+func (b ReservationBasis) Spec(service *Service) Resources {
+	fraction := b.fraction()
+	spec := make(Resources)
+
+	keys := make(map[string]bool)
+	for key := range service.MinSpec {
+		keys[key] = true
+	}
+	for key := range service.MaxSpec {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		minValue, hasMin := service.MinSpec[key]
+		maxValue, hasMax := service.MaxSpec[key]
+		if !hasMax {
+			maxValue = minValue
+		}
+
+		switch fraction {
+		case 0:
+			if hasMin {
+				spec[key] = minValue
+			}
+			continue
+		case 1:
+			spec[key] = maxValue
+			continue
+		}
+
+		value := minValue + (maxValue-minValue)*Quantity(fraction)
+		if maxValue.IsWhole() && minValue.IsWhole() {
+			value = Quantity(math.Ceil(float64(value)))
+		}
+		spec[key] = value
+	}
+
+	return spec
+}
+
+// ReservationStatus is the state of a capacity reservation at a point in time
+type ReservationStatus string
+
+// Reservation statuses
+const (
+	ReservationPending ReservationStatus = "pending" // Before starts_at, the capacity is already held
+	ReservationActive  ReservationStatus = "active"
+	ReservationExpired ReservationStatus = "expired" // After expires_at, the capacity is released
+)
+
+// Reservation holds capacity for an owner until it expires, on a target compute or spread over
+// the computes matching a selector. Held resources count as allocated for planning, the
+// assignment admission check and the reports. Converting a reservation creates assignments
+// out of the held capacity.
+type Reservation struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Owner       string               `json:"owner"`
+	Resources   Resources            `json:"resources"`
+	ComputeID   string               `json:"compute_id,omitempty"` // Target compute
+	Selector    *ReservationSelector `json:"selector,omitempty"`   // Computes the capacity may be held on without a target (nil: any compute)
+	StartsAt    time.Time            `json:"starts_at"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	Description string               `json:"description,omitempty"`
+	Status      ReservationStatus    `json:"status,omitempty"` // Set when read, not persisted
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// ReservationSelector selects computes by provider, region and tags
+type ReservationSelector struct {
+	Provider string            `json:"provider,omitempty"`
+	Region   string            `json:"region,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// Matches checks if the compute has the provider, region and every tag of the selector
+func (s *ReservationSelector) Matches(compute *Compute) bool {
+	if s.Provider != "" && compute.Provider != s.Provider {
+		return false
+	}
+	if s.Region != "" && compute.Region != s.Region {
+		return false
+	}
+	return compute.MatchesTags(s.Tags)
+}
+
+// Validate checks the reservation fields
+func (r *Reservation) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if len(r.Resources) == 0 {
+		return fmt.Errorf("at least one resource is required")
+	}
+	for _, key := range r.Resources.Keys() {
+		if r.Resources[key] <= 0 {
+			return fmt.Errorf("resource %s must be greater than 0", key)
+		}
+	}
+	if r.ComputeID != "" && r.Selector != nil {
+		return fmt.Errorf("compute_id and selector are mutually exclusive")
+	}
+	if r.ExpiresAt.IsZero() {
+		return fmt.Errorf("expires_at is required")
+	}
+	if !r.StartsAt.IsZero() && !r.ExpiresAt.After(r.StartsAt) {
+		return fmt.Errorf("expires_at must be after starts_at")
+	}
+	return nil
+}
+
+// StatusAt returns the status of the reservation at the given time
+func (r *Reservation) StatusAt(now time.Time) ReservationStatus {
+	switch {
+	case !now.Before(r.ExpiresAt):
+		return ReservationExpired
+	case now.Before(r.StartsAt):
+		return ReservationPending
+	}
+	return ReservationActive
+}
+
+// Targets checks if the reservation may hold capacity on the compute
+func (r *Reservation) Targets(compute *Compute) bool {
+	if r.ComputeID != "" {
+		return compute.ID == r.ComputeID
+	}
+	return r.Selector == nil || r.Selector.Matches(compute)
+}
+
+// ReservationHold is the part of a reservation held on one compute
+type ReservationHold struct {
+	ComputeID   string    `json:"compute_id"`
+	ComputeName string    `json:"compute_name"`
+	Resources   Resources `json:"resources"`
+}
+
+// ReservationPlacement tells where the capacity of a reservation is held
+type ReservationPlacement struct {
+	Holds     []ReservationHold `json:"holds,omitempty"`
+	Shortfall Resources         `json:"shortfall,omitempty"` // Resources that could not be held for lack of capacity
+}
+
+// HoldReservations sets the Held resources of every compute from the reservations that have not
+// expired. Capacity left after the assignments is held on the target compute, or taken from the
+// active computes matching the selector in name order. Targeted reservations are held first, then
+// the oldest reservations. Compute resources must already be populated, with hosted resources
+// rolled up. The placement of every reservation is returned by reservation ID.
+func HoldReservations(computes []*Compute, reservations []*Reservation, assignments []*Assignment, services map[string]*Service, basis ReservationBasis, now time.Time) map[string]*ReservationPlacement {
+	for _, compute := range computes {
+		compute.Held = nil
+	}
+
+	pending := make([]*Reservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		if reservation.StatusAt(now) != ReservationExpired {
+			pending = append(pending, reservation)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if (pending[i].ComputeID != "") != (pending[j].ComputeID != "") {
+			return pending[i].ComputeID != ""
+		}
+		if !pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+		}
+		return pending[i].Name < pending[j].Name
+	})
+
+	ordered := make([]*Compute, len(computes))
+	copy(ordered, computes)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	placements := make(map[string]*ReservationPlacement, len(pending))
+	for _, reservation := range pending {
+		placement := &ReservationPlacement{}
+		remaining := reservation.Resources.Clone()
+
+		for _, compute := range ordered {
+			if compute.State != ComputeStateActive || !reservation.Targets(compute) {
+				continue
+			}
+			available := compute.GetAvailableResources(compute.GetAllocatedResources(assignments, services, basis))
+
+			hold := make(Resources)
+			for _, key := range remaining.Keys() {
+				take := remaining[key]
+				if available[key] < take {
+					take = available[key]
+				}
+				if take <= 0 {
+					continue
+				}
+				hold[key] = take
+				remaining[key] -= take
+			}
+			if len(hold) == 0 {
+				continue
+			}
+
+			compute.Held = compute.Held.Add(hold)
+			placement.Holds = append(placement.Holds, ReservationHold{
+				ComputeID:   compute.ID,
+				ComputeName: compute.Name,
+				Resources:   hold,
+			})
+		}
+
+		for key, value := range remaining {
+			if value > 0 {
+				if placement.Shortfall == nil {
+					placement.Shortfall = make(Resources)
+				}
+				placement.Shortfall[key] = value
+			}
+		}
+		placements[reservation.ID] = placement
+	}
+
+	return placements
+}
+
+// HoldReservations holds the capacity of the reservations on the computes of the planner,
+// against its assignments and reservation basis. Planners derived with WithWhatIf hold them again
+// on the scenario.
+func (cp *CapacityPlanner) HoldReservations(reservations []*Reservation, now time.Time) map[string]*ReservationPlacement {
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
+	cp.held = reservations
+	cp.heldAt = now
+	return HoldReservations(cp.computes, reservations, cp.assignments, servicesMap, cp.reservation, now)
+}
+
+// ExpiringReservation is a reservation expiring within the window of an expiry report
+type ExpiringReservation struct {
+	*Reservation
+	ExpiresIn string `json:"expires_in"` // Time left, rounded to the minute
+	ReservationPlacement
+}
+
+// ExpiringReservations returns the reservations that have not expired yet and expire within the
+// window, soonest first, with their placements
+func ExpiringReservations(reservations []*Reservation, placements map[string]*ReservationPlacement, now time.Time, within time.Duration) []ExpiringReservation {
+	expiring := make([]ExpiringReservation, 0)
+	for _, reservation := range reservations {
+		if reservation.StatusAt(now) == ReservationExpired || reservation.ExpiresAt.Sub(now) > within {
+			continue
+		}
+		entry := ExpiringReservation{
+			Reservation: reservation,
+			ExpiresIn:   reservation.ExpiresAt.Sub(now).Round(time.Minute).String(),
+		}
+		if placement, ok := placements[reservation.ID]; ok {
+			entry.ReservationPlacement = *placement
+		}
+		expiring = append(expiring, entry)
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+	return expiring
+}
+
+// ParseWindow parses a duration such as "72h", "30m" or, in days, "14d"
+func ParseWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		number, err := strconv.ParseFloat(days, 64)
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(number * float64(24*time.Hour)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 72h or 14d)", value)
+	}
+	return duration, nil
+}
+
+// ReservationConversion asks to turn the capacity of a reservation into assignments of a service
+type ReservationConversion struct {
+	ServiceID string `json:"service_id"`
+	Replicas  int    `json:"replicas,omitempty"` // Number of instances to place (default 1)
+	Strategy  string `json:"strategy,omitempty"` // Scoring strategy name (default balanced)
+}
+
+// ConversionResult is the outcome of converting a reservation
+type ConversionResult struct {
+	Plan        *PlanResult   `json:"plan"`
+	Assignments []*Assignment `json:"assignments,omitempty"` // Assignments created or increased
+	Consumed    Resources     `json:"consumed,omitempty"`    // Reserved resources taken by the new instances
+	Reservation *Reservation  `json:"reservation,omitempty"` // What is left of the reservation, nil when fully consumed
+	Message     string        `json:"message,omitempty"`
+}
+
+// PlanRequest returns the request placing the instances of a conversion on the target compute
+// of the reservation, or the computes matching its selector
+func (r *Reservation) PlanRequest(conversion ReservationConversion) PlanRequest {
+	request := PlanRequest{
+		ServiceID: conversion.ServiceID,
+		Replicas:  conversion.Replicas,
+		Strategy:  conversion.Strategy,
+	}
+	request.Constraints.ComputeID = r.ComputeID
+	if r.Selector != nil {
+		request.Constraints.Provider = r.Selector.Provider
+		request.Constraints.Region = r.Selector.Region
+		request.Constraints.Tags = r.Selector.Tags
+	}
+	return request
+}
+
+// Consume takes the resources reserved by new instances out of the reservation and returns the
+// part of spec taken and the resources left. Keys the reservation does not hold come from free
+// capacity and are not consumed.
+func (r *Reservation) Consume(spec Resources) (Resources, Resources, error) {
+	consumed := make(Resources)
+	for _, key := range spec.Keys() {
+		held, ok := r.Resources[key]
+		if !ok || spec[key] <= 0 {
+			continue
+		}
+		if spec[key] > held {
+			return nil, nil, fmt.Errorf("instances need %s %s, reservation %s holds %s", spec[key], key, r.Name, held)
+		}
+		consumed[key] = spec[key]
+	}
+	if len(consumed) == 0 {
+		return nil, nil, fmt.Errorf("instances need none of the resources held by reservation %s (%s)", r.Name, strings.Join(r.Resources.Keys(), ", "))
+	}
+
+	remaining := make(Resources)
+	for key, value := range r.Resources.Sub(consumed) {
+		if value > 0 {
+			remaining[key] = value
+		}
+	}
+	return consumed, remaining, nil
+}
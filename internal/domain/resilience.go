@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ResilienceRequest asks for an N+1 failure-domain analysis
+type ResilienceRequest struct {
+	TopologyKey string  `json:"topology_key,omitempty"` // Failure domain: host (default), region, provider or a tag key such as rack or zone
+	Strategy    string  `json:"strategy,omitempty"`     // Scoring strategy used to re-plan displaced instances (default balanced)
+	WhatIf      *WhatIf `json:"what_if,omitempty"`      // Analyze hypothetical changes instead of the stored data
+}
+
+// ResilienceReport tells whether losing any single failure domain leaves services unplaceable
+type ResilienceReport struct {
+	TopologyKey string            `json:"topology_key"`
+	Strategy    string            `json:"strategy"`
+	Resilient   bool              `json:"resilient"` // Every failure domain can be lost without stranding instances
+	Domains     []FailureDomain   `json:"domains"`
+	Services    []StrandedService `json:"stranded_services,omitempty"`
+	// Extra capacity needed to be N+1 safe: per resource key, the largest shortfall of any single domain
+	ExtraCapacity   Resources        `json:"extra_capacity,omitempty"`
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+	Unassigned      []*Compute       `json:"unassigned,omitempty"` // Computes without a value for the topology key, never lost
+	Message         string           `json:"message,omitempty"`
+	WhatIf          bool             `json:"what_if,omitempty"`
+	// Spec each instance reserves (min, max or pNN)
+	Reservation ReservationBasis `json:"reservation"`
+}
+
+// FailureDomain is the outcome of losing every compute sharing one topology value
+type FailureDomain struct {
+	Value     string                `json:"value"` // Topology value (compute name for host, a hypervisor with its hosted computes)
+	Computes  []*Compute            `json:"computes"`
+	Displaced int                   `json:"displaced"` // Instances running in the domain
+	Survives  bool                  `json:"survives"`  // Every displaced instance fits on the remaining computes
+	Moves     []Move                `json:"moves,omitempty"`
+	Stranded  []UnmovableAssignment `json:"stranded,omitempty"`
+	Shortfall Resources             `json:"shortfall,omitempty"` // Resources reserved by the stranded instances
+}
+
+// StrandedService lists the failure domains whose loss strands instances of a service
+type StrandedService struct {
+	ServiceID   string   `json:"service_id"`
+	ServiceName string   `json:"service_name"`
+	Domains     []string `json:"domains"`
+	MaxStranded int      `json:"max_stranded"` // Most instances stranded by a single domain
+}
+
+// AnalyzeResilience simulates the loss of each failure domain in turn. The instances running in
+// the domain are re-planned onto the surviving computes with the same placement rules, capacity
+// and retry logic as a drain. Instances without a target are stranded, and their reserved
+// resources make up the shortfall of the domain.
+func (cp *CapacityPlanner) AnalyzeResilience(request ResilienceRequest) (*ResilienceReport, error) {
+	if !request.WhatIf.IsEmpty() {
+		planner, err := cp.WithWhatIf(request.WhatIf)
+		if err != nil {
+			return nil, err
+		}
+
+		request.WhatIf = nil
+		report, err := planner.AnalyzeResilience(request)
+		if report != nil {
+			report.WhatIf = true
+		}
+		return report, err
+	}
+
+	strategy, err := GetScoringStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	key := topologyKeyOrHost(request.TopologyKey)
+
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
+
+	report := &ResilienceReport{
+		TopologyKey: key,
+		Strategy:    strategy.Name(),
+		Domains:     make([]FailureDomain, 0),
+		Reservation: cp.reservation,
+	}
+
+	// Group computes by topology value. Every compute is its own domain for host, together with
+	// the computes it hosts, but only hosts running instances are worth losing.
+	instances := make(map[string]int)
+	for _, assignment := range cp.assignments {
+		instances[assignment.ComputeID] += assignmentQuantity(assignment)
+	}
+
+	names := make(map[string]string, len(cp.computes))
+	for _, compute := range cp.computes {
+		names[compute.ID] = compute.Name
+	}
+
+	members := make(map[string][]*Compute)
+	values := make([]string, 0)
+	for _, compute := range cp.computes {
+		value, ok := compute.TopologyValue(key)
+		if !ok {
+			report.Unassigned = append(report.Unassigned, compute)
+			continue
+		}
+		if _, seen := members[value]; !seen {
+			values = append(values, value)
+		}
+		members[value] = append(members[value], compute)
+	}
+	sort.Strings(values)
+
+	extra := make(Resources)
+	stranded := make(map[string]*StrandedService)
+	serviceOrder := make([]string, 0)
+
+	for _, value := range values {
+		lost := members[value]
+		domain := FailureDomain{
+			Value:    value,
+			Computes: lost,
+		}
+		if key == TopologyKeyHost {
+			domain.Value = lost[0].Name
+			if name, ok := names[value]; ok {
+				domain.Value = name
+			}
+		}
+
+		isLost := make(map[string]bool, len(lost))
+		for _, compute := range lost {
+			isLost[compute.ID] = true
+			domain.Displaced += instances[compute.ID]
+		}
+		if key == TopologyKeyHost && domain.Displaced == 0 {
+			continue
+		}
+		survivors := make([]*Compute, 0, len(cp.computes))
+		for _, compute := range cp.computes {
+			if !isLost[compute.ID] {
+				survivors = append(survivors, compute)
+			}
+		}
+
+		domain.Moves, domain.Stranded = cp.planEvacuation(lost, survivors, strategy, servicesMap)
+		domain.Survives = len(domain.Stranded) == 0
+
+		shortfall := make(Resources)
+		strandedPerService := make(map[string]int)
+		for _, entry := range domain.Stranded {
+			strandedPerService[entry.ServiceID] += entry.Quantity
+			service, ok := servicesMap[entry.ServiceID]
+			if !ok {
+				continue
+			}
+			reserved := cp.reservation.Spec(service)
+			for resource := range reserved {
+				shortfall[resource] += reserved[resource] * Quantity(entry.Quantity)
+			}
+		}
+
+		if len(shortfall) > 0 {
+			domain.Shortfall = shortfall
+			for resource, amount := range shortfall {
+				if amount > extra[resource] {
+					extra[resource] = amount
+				}
+			}
+		}
+
+		for serviceID, count := range strandedPerService {
+			entry, ok := stranded[serviceID]
+			if !ok {
+				entry = &StrandedService{ServiceID: serviceID}
+				if service, found := servicesMap[serviceID]; found {
+					entry.ServiceName = service.Name
+				}
+				stranded[serviceID] = entry
+				serviceOrder = append(serviceOrder, serviceID)
+			}
+			entry.Domains = append(entry.Domains, domain.Value)
+			if count > entry.MaxStranded {
+				entry.MaxStranded = count
+			}
+		}
+
+		report.Domains = append(report.Domains, domain)
+	}
+
+	for _, serviceID := range serviceOrder {
+		report.Services = append(report.Services, *stranded[serviceID])
+	}
+	sort.SliceStable(report.Services, func(i, j int) bool {
+		return report.Services[i].ServiceName < report.Services[j].ServiceName
+	})
+
+	failed := 0
+	for _, domain := range report.Domains {
+		if !domain.Survives {
+			failed++
+		}
+	}
+	report.Resilient = failed == 0
+
+	if len(extra) > 0 {
+		report.ExtraCapacity = extra
+		if build := cp.recommendBuild(report.ExtraCapacity, 1, nil); build != nil {
+			build.Rationale = fmt.Sprintf("%s; placement rules of the stranded services still apply to the new host", build.Rationale)
+			report.Recommendations = append(report.Recommendations, *build)
+		}
+	}
+
+	switch {
+	case len(report.Domains) == 0:
+		report.Message = fmt.Sprintf("no failure domain for topology key %q runs any instance", key)
+	case report.Resilient:
+		report.Message = fmt.Sprintf("N+1 safe: any single %s can be lost (%d domain(s) checked)", key, len(report.Domains))
+	default:
+		report.Message = fmt.Sprintf("not N+1 safe: losing %d of %d %s domain(s) strands instances", failed, len(report.Domains), key)
+	}
+
+	return report, nil
+}
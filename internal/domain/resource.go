@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResourceDefinition describes a resource key that computes provide and services request
+type ResourceDefinition struct {
+	Key         string          `json:"key"`
+	Unit        string          `json:"unit"`      // Canonical unit quantities are stored in
+	Dimension   string          `json:"dimension"` // count, bytes or bandwidth
+	Description string          `json:"description"`
+	Aliases     []ResourceAlias `json:"aliases,omitempty"`
+	Source      string          `json:"source,omitempty"`  // What produces the key on a compute
+	Storage     bool            `json:"storage,omitempty"` // Storage tier, produced by RAID-aware rules
+}
+
+// ResourceAlias is an alternative name for a resource key. Values given under an alias are
+// in the unit of the alias and converted to the unit of the key (ram_gb=4 is memory=4096).
+type ResourceAlias struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+}
+
+// units holds the known units by name
+var units = map[string]Unit{
+	UnitCount.Name: UnitCount,
+	UnitMiB.Name:   UnitMiB,
+	UnitGiB.Name:   UnitGiB,
+	UnitTiB.Name:   UnitTiB,
+	UnitGB.Name:    UnitGB,
+	UnitTB.Name:    UnitTB,
+	UnitMbps.Name:  UnitMbps,
+	UnitGbps.Name:  UnitGbps,
+}
+
+// builtinResources are the keys derived from components by the default derivation rules
+var builtinResources = []ResourceDefinition{
+	{
+		Key: "cores", Unit: UnitCount.Name, Dimension: DimensionCount,
+		Description: "CPU cores",
+		Aliases:     []ResourceAlias{{Name: "cpu", Unit: UnitCount.Name}, {Name: "cpus", Unit: UnitCount.Name}, {Name: "vcpu", Unit: UnitCount.Name}},
+		Source:      "cpu components",
+	},
+	{
+		Key: "memory", Unit: UnitMiB.Name, Dimension: DimensionBytes,
+		Description: "RAM",
+		Aliases:     []ResourceAlias{{Name: "ram", Unit: UnitMiB.Name}, {Name: "ram_mb", Unit: UnitMiB.Name}, {Name: "ram_gb", Unit: UnitGiB.Name}, {Name: "memory_mb", Unit: UnitMiB.Name}, {Name: "memory_gb", Unit: UnitGiB.Name}},
+		Source:      "ram components",
+	},
+	{
+		Key: "gpu", Unit: UnitCount.Name, Dimension: DimensionCount,
+		Description: "GPUs",
+		Aliases:     []ResourceAlias{{Name: "gpus", Unit: UnitCount.Name}},
+		Source:      "gpu components (count)",
+	},
+	{
+		Key: "vram", Unit: UnitMiB.Name, Dimension: DimensionBytes,
+		Description: "GPU memory",
+		Aliases:     []ResourceAlias{{Name: "vram_mb", Unit: UnitMiB.Name}, {Name: "vram_gb", Unit: UnitGiB.Name}},
+		Source:      "gpu components",
+	},
+	{
+		Key: "storage", Unit: UnitGB.Name, Dimension: DimensionBytes, Storage: true,
+		Description: "Storage of components typed storage, after RAID",
+		Aliases:     []ResourceAlias{{Name: "storage_gb", Unit: UnitGB.Name}, {Name: "disk", Unit: UnitGB.Name}, {Name: "disk_gb", Unit: UnitGB.Name}},
+		Source:      "storage components (RAID aware)",
+	},
+	{
+		Key: "nvme", Unit: UnitGB.Name, Dimension: DimensionBytes, Storage: true,
+		Description: "NVMe storage, after RAID",
+		Aliases:     []ResourceAlias{{Name: "nvme_gb", Unit: UnitGB.Name}},
+		Source:      "nvme components (RAID aware)",
+	},
+	{
+		Key: "ssd", Unit: UnitGB.Name, Dimension: DimensionBytes, Storage: true,
+		Description: "SSD storage, after RAID",
+		Aliases:     []ResourceAlias{{Name: "ssd_gb", Unit: UnitGB.Name}},
+		Source:      "ssd components (RAID aware)",
+	},
+	{
+		Key: "hdd", Unit: UnitGB.Name, Dimension: DimensionBytes, Storage: true,
+		Description: "HDD storage, after RAID",
+		Aliases:     []ResourceAlias{{Name: "hdd_gb", Unit: UnitGB.Name}},
+		Source:      "hdd components (RAID aware)",
+	},
+	{
+		Key: "bandwidth_gbps", Unit: UnitGbps.Name, Dimension: DimensionBandwidth,
+		Description: "Network bandwidth",
+		Aliases:     []ResourceAlias{{Name: "bandwidth", Unit: UnitGbps.Name}, {Name: "bandwidth_mbps", Unit: UnitMbps.Name}},
+		Source:      "nic components",
+	},
+}
+
+// resolvedKey is the canonical key a resource name maps to, with the factor converting
+// a value in the unit of the name to the unit of the key
+type resolvedKey struct {
+	key    string
+	factor float64
+}
+
+// ResourceRegistry holds the known resource keys and their aliases
+type ResourceRegistry struct {
+	definitions []ResourceDefinition
+	lookup      map[string]resolvedKey
+}
+
+// NewResourceRegistry creates a registry from the built-in resource keys and extra definitions.
+// An extra definition replaces a built-in one with the same key.
+func NewResourceRegistry(extra ...ResourceDefinition) (*ResourceRegistry, error) {
+	byKey := make(map[string]ResourceDefinition)
+	for _, definition := range builtinResources {
+		byKey[definition.Key] = definition
+	}
+	for _, definition := range extra {
+		byKey[definition.Key] = definition
+	}
+
+	registry := &ResourceRegistry{lookup: make(map[string]resolvedKey)}
+	for _, definition := range byKey {
+		registry.definitions = append(registry.definitions, definition)
+	}
+	sort.Slice(registry.definitions, func(i, j int) bool {
+		return registry.definitions[i].Key < registry.definitions[j].Key
+	})
+
+	for _, definition := range registry.definitions {
+		if _, ok := units[definition.Unit]; !ok {
+			return nil, fmt.Errorf("resource %s: unknown unit %q", definition.Key, definition.Unit)
+		}
+		registry.lookup[definition.Key] = resolvedKey{key: definition.Key, factor: 1}
+	}
+
+	for _, definition := range registry.definitions {
+		unit := units[definition.Unit]
+		for _, alias := range definition.Aliases {
+			aliasUnit, ok := units[alias.Unit]
+			if !ok {
+				return nil, fmt.Errorf("resource %s: alias %s has unknown unit %q", definition.Key, alias.Name, alias.Unit)
+			}
+			if aliasUnit.Dimension != unit.Dimension {
+				return nil, fmt.Errorf("resource %s: alias %s is measured in %s, not a %s unit", definition.Key, alias.Name, alias.Unit, unit.Dimension)
+			}
+			if existing, taken := registry.lookup[alias.Name]; taken {
+				return nil, fmt.Errorf("resource %s: alias %s already names %s", definition.Key, alias.Name, existing.key)
+			}
+			registry.lookup[alias.Name] = resolvedKey{key: definition.Key, factor: aliasUnit.Factor / unit.Factor}
+		}
+	}
+
+	return registry, nil
+}
+
+// DefaultResourceRegistry returns a registry holding the built-in resource keys
+func DefaultResourceRegistry() *ResourceRegistry {
+	registry, err := NewResourceRegistry()
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Definitions returns the known resource keys in alphabetical order
+func (r *ResourceRegistry) Definitions() []ResourceDefinition {
+	return r.definitions
+}
+
+// Resolve returns the canonical key for a key or alias
+func (r *ResourceRegistry) Resolve(name string) (string, bool) {
+	resolved, ok := r.lookup[name]
+	return resolved.key, ok
+}
+
+// StorageTiers returns the storage tier keys in alphabetical order
+func (r *ResourceRegistry) StorageTiers() []string {
+	tiers := make([]string, 0)
+	for _, definition := range r.definitions {
+		if definition.Storage {
+			tiers = append(tiers, definition.Key)
+		}
+	}
+	return tiers
+}
+
+// IsStorageTier checks that a canonical key is a storage tier
+func (r *ResourceRegistry) IsStorageTier(key string) bool {
+	for _, definition := range r.definitions {
+		if definition.Key == key {
+			return definition.Storage
+		}
+	}
+	return false
+}
+
+// Normalize rewrites aliases to their canonical key, converting values to the unit of the key.
+// Unknown keys and keys given twice (e.g. cpu and cores) are rejected.
+func (r *ResourceRegistry) Normalize(spec Resources) (Resources, error) {
+	normalized := make(Resources, len(spec))
+	given := make(map[string]string, len(spec))
+
+	for _, name := range spec.Keys() {
+		resolved, ok := r.lookup[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource key %q (known keys: %s)", name, strings.Join(r.keys(), ", "))
+		}
+		if previous, duplicate := given[resolved.key]; duplicate {
+			return nil, fmt.Errorf("resource keys %q and %q both set %s", previous, name, resolved.key)
+		}
+		given[resolved.key] = name
+		normalized[resolved.key] = spec[name] * Quantity(resolved.factor)
+	}
+
+	return normalized, nil
+}
+
+// keys returns the canonical keys in alphabetical order
+func (r *ResourceRegistry) keys() []string {
+	keys := make([]string, 0, len(r.definitions))
+	for _, definition := range r.definitions {
+		keys = append(keys, definition.Key)
+	}
+	return keys
+}
+
+// builtinUnit returns the unit of a built-in key or alias
+func builtinUnit(name string) (Unit, bool) {
+	for _, definition := range builtinResources {
+		if definition.Key == name {
+			return units[definition.Unit], true
+		}
+		for _, alias := range definition.Aliases {
+			if alias.Name == name {
+				return units[alias.Unit], true
+			}
+		}
+	}
+	return Unit{}, false
+}
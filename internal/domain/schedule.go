@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule is the time span of an assignment: from StartsAt (already running when nil) until
+// EndsAt (indefinitely when nil). Plans use it as the window the placement needs capacity for.
+type Schedule struct {
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+// IsZero reports whether the schedule is unbounded on both sides
+func (s Schedule) IsZero() bool {
+	return s.StartsAt == nil && s.EndsAt == nil
+}
+
+// Validate checks that the schedule ends after it starts
+func (s Schedule) Validate() error {
+	if s.StartsAt != nil && s.EndsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// UTC returns the schedule with its times in UTC, as they are stored
+func (s Schedule) UTC() Schedule {
+	if s.StartsAt != nil {
+		startsAt := s.StartsAt.UTC()
+		s.StartsAt = &startsAt
+	}
+	if s.EndsAt != nil {
+		endsAt := s.EndsAt.UTC()
+		s.EndsAt = &endsAt
+	}
+	return s
+}
+
+// ActiveAt checks if the schedule covers the instant
+func (s Schedule) ActiveAt(t time.Time) bool {
+	if s.StartsAt != nil && t.Before(*s.StartsAt) {
+		return false
+	}
+	return s.EndsAt == nil || t.Before(*s.EndsAt)
+}
+
+// Ended checks if the schedule ended at or before now
+func (s Schedule) Ended(now time.Time) bool {
+	return s.EndsAt != nil && !now.Before(*s.EndsAt)
+}
+
+// Overlaps checks if two schedules share any instant
+func (s Schedule) Overlaps(other Schedule) bool {
+	if s.StartsAt != nil && other.EndsAt != nil && !s.StartsAt.Before(*other.EndsAt) {
+		return false
+	}
+	if other.StartsAt != nil && s.EndsAt != nil && !other.StartsAt.Before(*s.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Equal checks if two schedules start and end at the same times
+func (s Schedule) Equal(other Schedule) bool {
+	return equalTime(s.StartsAt, other.StartsAt) && equalTime(s.EndsAt, other.EndsAt)
+}
+
+// From returns the schedule starting no earlier than now, so that the past is left out
+func (s Schedule) From(now time.Time) Schedule {
+	if s.StartsAt == nil || s.StartsAt.Before(now) {
+		s.StartsAt = &now
+	}
+	return s
+}
+
+// String describes the schedule, e.g. "2026-01-01 → 2026-03-31"
+func (s Schedule) String() string {
+	const layout = "2006-01-02 15:04"
+	switch {
+	case s.StartsAt == nil && s.EndsAt == nil:
+		return "always"
+	case s.EndsAt == nil:
+		return "from " + s.StartsAt.Local().Format(layout)
+	case s.StartsAt == nil:
+		return "until " + s.EndsAt.Local().Format(layout)
+	}
+	return s.StartsAt.Local().Format(layout) + " → " + s.EndsAt.Local().Format(layout)
+}
+
+// ScheduledAssignments returns the assignments running at some point of the window. Every
+// instance is counted for the whole window, so two assignments one after the other within it
+// both count.
+func ScheduledAssignments(assignments []*Assignment, window Schedule) []*Assignment {
+	if window.IsZero() {
+		return assignments
+	}
+
+	scheduled := make([]*Assignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if assignment.Schedule.Overlaps(window) {
+			scheduled = append(scheduled, assignment)
+		}
+	}
+	return scheduled
+}
+
+// ActiveAssignments returns the assignments running at the instant
+func ActiveAssignments(assignments []*Assignment, at time.Time) []*Assignment {
+	active := make([]*Assignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if assignment.Schedule.ActiveAt(at) {
+			active = append(active, assignment)
+		}
+	}
+	return active
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -1,25 +1,87 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
 // Service represents an application or workload with resource requirements
 type Service struct {
-	ID        string              `json:"id"`
-	Name      string              `json:"name"`
-	MinSpec   Resources           `json:"min_spec"`
-	MaxSpec   Resources           `json:"max_spec"`
-	Placement PlacementRules      `json:"placement"`
-	Ports     []PortRequirement   `json:"ports,omitempty"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	MinSpec   Resources            `json:"min_spec"`
+	MaxSpec   Resources            `json:"max_spec"`
+	Placement PlacementRules       `json:"placement"`
+	Ports     []PortRequirement    `json:"ports,omitempty"`
+	Storage   []StorageRequirement `json:"storage,omitempty"` // Storage each instance needs on a single storage group
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
 }
 
 // PlacementRules defines constraints for service placement
 type PlacementRules struct {
-	Affinity     []TagSelector `json:"affinity,omitempty"`
-	AntiAffinity []TagSelector `json:"antiAffinity,omitempty"`
-	SpreadMax    int           `json:"spreadMax,omitempty"` // Max instances per compute (0 = unlimited)
-	TopologyKey  string        `json:"topologyKey,omitempty"` // Tag key to spread across
+	Affinity              []TagSelector      `json:"affinity,omitempty"`
+	AntiAffinity          []TagSelector      `json:"antiAffinity,omitempty"`
+	HardwareAffinity      []TagSelector      `json:"hardwareAffinity,omitempty"`      // Must match hardware facts of the compute components
+	HardwareAntiAffinity  []TagSelector      `json:"hardwareAntiAffinity,omitempty"`  // Must NOT match hardware facts of the compute components
+	ServiceAffinity       []ServiceSelector  `json:"serviceAffinity,omitempty"`       // Must share a topology domain with these services
+	ServiceAntiAffinity   []ServiceSelector  `json:"serviceAntiAffinity,omitempty"`   // Must NOT share a topology domain with these services
+	PreferredAffinity     []WeightedSelector `json:"preferredAffinity,omitempty"`     // Matching terms raise the candidate score
+	PreferredAntiAffinity []WeightedSelector `json:"preferredAntiAffinity,omitempty"` // Matching terms lower the candidate score
+	SpreadMax             int                `json:"spreadMax,omitempty"`             // Max instances per compute (0 = unlimited)
+	TopologyKey           string             `json:"topologyKey,omitempty"`           // Tag key (or host, region, provider) to spread across
+}
+
+// ServiceSelector matches other services by ID or name within a topology domain
+type ServiceSelector struct {
+	Services    []string `json:"services"`              // Service IDs or names
+	TopologyKey string   `json:"topologyKey,omitempty"` // host (default), region, provider or a tag key
+}
+
+// WeightedSelector is a soft placement term. It matches when the tag selector matches the
+// compute and, if set, the hardware selector matches its hardware facts and the service
+// selector finds a selected service in the same domain.
+type WeightedSelector struct {
+	Weight int `json:"weight"` // 1-100, added to or subtracted from the candidate score
+	TagSelector
+	Hardware *TagSelector     `json:"hardware,omitempty"`
+	Service  *ServiceSelector `json:"service,omitempty"`
+}
+
+// Matches checks if the weighted term applies to the compute
+func (ws *WeightedSelector) Matches(compute *Compute, assignments []*Assignment, computes []*Compute, services map[string]*Service) bool {
+	if !ws.TagSelector.Matches(compute.Tags) {
+		return false
+	}
+	if ws.Hardware != nil && !ws.Hardware.MatchesFacts(compute.Facts) {
+		return false
+	}
+	if ws.Service != nil && !sharesDomain(compute, *ws.Service, assignments, computes, services) {
+		return false
+	}
+	return true
+}
+
+// Topology keys resolved from compute fields instead of tags
+const (
+	TopologyKeyHost     = "host"
+	TopologyKeyRegion   = "region"
+	TopologyKeyProvider = "provider"
+)
+
+// Selects checks if the selector references the given service
+func (ss *ServiceSelector) Selects(service *Service) bool {
+	for _, ref := range ss.Services {
+		if ref == service.ID || ref == service.Name {
+			return true
+		}
+	}
+	return false
 }
 
 // TagSelector matches compute tags
@@ -43,6 +105,12 @@ const (
 	OperatorNotIn        Operator = "NotIn"
 	OperatorExists       Operator = "Exists"
 	OperatorDoesNotExist Operator = "DoesNotExist"
+	OperatorGt           Operator = "Gt"    // Numeric value greater than the single value
+	OperatorLt           Operator = "Lt"    // Numeric value less than the single value
+	OperatorGte          Operator = "Gte"   // Numeric value greater than or equal to the single value
+	OperatorLte          Operator = "Lte"   // Numeric value less than or equal to the single value
+	OperatorRegex        Operator = "Regex" // Value matches any of the regular expressions (RE2, unanchored)
+	OperatorGlob         Operator = "Glob"  // Value matches any of the glob patterns (*, ? and [...])
 )
 
 // Matches checks if a TagSelector matches the given tags
@@ -67,65 +135,454 @@ func (ts *TagSelector) Matches(tags map[string]string) bool {
 // Matches checks if an Expression matches the given tags
 func (e *Expression) Matches(tags map[string]string) bool {
 	value, exists := tags[e.Key]
+	if !exists {
+		return e.matchValues(nil)
+	}
+	return e.matchValues([]string{value})
+}
 
+// matchValues checks the expression against the values of a key, none when the key is absent.
+// In matches when any value is listed, NotIn when none is; the numeric and pattern operators
+// match when any value does.
+func (e *Expression) matchValues(values []string) bool {
 	switch e.Operator {
 	case OperatorExists:
-		return exists
+		return len(values) > 0
 	case OperatorDoesNotExist:
-		return !exists
+		return len(values) == 0
 	case OperatorIn:
-		if !exists {
-			return false
-		}
-		for _, v := range e.Values {
-			if v == value {
+		for _, value := range values {
+			if containsString(e.Values, value) {
 				return true
 			}
 		}
 		return false
 	case OperatorNotIn:
-		if !exists {
-			return true
-		}
-		for _, v := range e.Values {
-			if v == value {
+		for _, value := range values {
+			if containsString(e.Values, value) {
 				return false
 			}
 		}
 		return true
+	case OperatorGt, OperatorLt, OperatorGte, OperatorLte:
+		for _, value := range values {
+			if e.compareNumber(value) {
+				return true
+			}
+		}
+		return false
+	case OperatorRegex:
+		for _, value := range values {
+			for _, pattern := range e.Values {
+				if re, err := compilePattern(pattern); err == nil && re.MatchString(value) {
+					return true
+				}
+			}
+		}
+		return false
+	case OperatorGlob:
+		for _, value := range values {
+			for _, pattern := range e.Values {
+				if matched, err := path.Match(pattern, value); err == nil && matched {
+					return true
+				}
+			}
+		}
+		return false
 	default:
 		return false
 	}
 }
 
+// compareNumber compares a value with the single value of a numeric expression; values that
+// are not numbers never match
+func (e *Expression) compareNumber(value string) bool {
+	if len(e.Values) != 1 {
+		return false
+	}
+	actual, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	bound, err := strconv.ParseFloat(e.Values[0], 64)
+	if err != nil {
+		return false
+	}
+
+	switch e.Operator {
+	case OperatorGt:
+		return actual > bound
+	case OperatorLt:
+		return actual < bound
+	case OperatorGte:
+		return actual >= bound
+	case OperatorLte:
+		return actual <= bound
+	}
+	return false
+}
+
+// maxCachedPatterns bounds the pattern cache, since patterns come from user input such as
+// compute list selectors
+const maxCachedPatterns = 256
+
+// patterns caches compiled regular expressions, since expressions are matched against every
+// candidate compute. The cache is emptied when it is full.
+var patterns = struct {
+	sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patterns.Lock()
+	defer patterns.Unlock()
+
+	if re, ok := patterns.compiled[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns.compiled) >= maxCachedPatterns {
+		patterns.compiled = make(map[string]*regexp.Regexp)
+	}
+	patterns.compiled[pattern] = re
+	return re, nil
+}
+
+// Validate checks that the expression has a key, a known operator and values the operator
+// accepts
+func (e *Expression) Validate() error {
+	if e.Key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	switch e.Operator {
+	case OperatorIn, OperatorNotIn:
+		if len(e.Values) == 0 {
+			return fmt.Errorf("operator %s requires at least one value", e.Operator)
+		}
+	case OperatorExists, OperatorDoesNotExist:
+		if len(e.Values) > 0 {
+			return fmt.Errorf("operator %s takes no values", e.Operator)
+		}
+	case OperatorGt, OperatorLt, OperatorGte, OperatorLte:
+		if len(e.Values) != 1 {
+			return fmt.Errorf("operator %s requires exactly one value, got %d", e.Operator, len(e.Values))
+		}
+		if _, err := strconv.ParseFloat(e.Values[0], 64); err != nil {
+			return fmt.Errorf("operator %s requires a number, got %q", e.Operator, e.Values[0])
+		}
+	case OperatorRegex:
+		if len(e.Values) == 0 {
+			return fmt.Errorf("operator %s requires at least one pattern", e.Operator)
+		}
+		for _, pattern := range e.Values {
+			if _, err := compilePattern(pattern); err != nil {
+				return fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+			}
+		}
+	case OperatorGlob:
+		if len(e.Values) == 0 {
+			return fmt.Errorf("operator %s requires at least one pattern", e.Operator)
+		}
+		for _, pattern := range e.Values {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown operator %q (use In, NotIn, Exists, DoesNotExist, Gt, Lt, Gte, Lte, Regex or Glob)", e.Operator)
+	}
+	return nil
+}
+
+// Validate checks the label keys and the expressions of the selector
+func (ts *TagSelector) Validate() error {
+	for key := range ts.MatchLabels {
+		if key == "" {
+			return fmt.Errorf("matchLabels: empty key")
+		}
+	}
+	for i := range ts.MatchExpressions {
+		if err := ts.MatchExpressions[i].Validate(); err != nil {
+			return fmt.Errorf("matchExpressions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the service placement rules are well formed
+func (s *Service) Validate() error {
+	names := make(map[string]bool)
+	for i, requirement := range s.Storage {
+		if err := requirement.Validate(); err != nil {
+			return fmt.Errorf("storage[%d]: %w", i, err)
+		}
+		if requirement.Name != "" {
+			if names[requirement.Name] {
+				return fmt.Errorf("storage[%d]: name %s is used twice", i, requirement.Name)
+			}
+			names[requirement.Name] = true
+		}
+	}
+	selectors := []struct {
+		rule      string
+		selectors []TagSelector
+	}{
+		{"affinity", s.Placement.Affinity},
+		{"antiAffinity", s.Placement.AntiAffinity},
+		{"hardwareAffinity", s.Placement.HardwareAffinity},
+		{"hardwareAntiAffinity", s.Placement.HardwareAntiAffinity},
+	}
+	for _, rule := range selectors {
+		for i := range rule.selectors {
+			if err := rule.selectors[i].Validate(); err != nil {
+				return fmt.Errorf("%s[%d]: %w", rule.rule, i, err)
+			}
+		}
+	}
+	for i, selector := range s.Placement.ServiceAffinity {
+		if len(selector.Services) == 0 {
+			return fmt.Errorf("serviceAffinity[%d]: services is required", i)
+		}
+	}
+	for i, selector := range s.Placement.ServiceAntiAffinity {
+		if len(selector.Services) == 0 {
+			return fmt.Errorf("serviceAntiAffinity[%d]: services is required", i)
+		}
+	}
+	for i, term := range s.Placement.PreferredAffinity {
+		if err := term.validate(); err != nil {
+			return fmt.Errorf("preferredAffinity[%d]: %w", i, err)
+		}
+	}
+	for i, term := range s.Placement.PreferredAntiAffinity {
+		if err := term.validate(); err != nil {
+			return fmt.Errorf("preferredAntiAffinity[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NormalizeResources rewrites the min and max specs and the storage tiers to the canonical keys of the registry,
+// rejecting unknown resource keys and storage tiers that are not storage keys
+func (s *Service) NormalizeResources(registry *ResourceRegistry) error {
+	minSpec, err := registry.Normalize(s.MinSpec)
+	if err != nil {
+		return fmt.Errorf("min_spec: %w", err)
+	}
+	maxSpec, err := registry.Normalize(s.MaxSpec)
+	if err != nil {
+		return fmt.Errorf("max_spec: %w", err)
+	}
+	s.MinSpec = minSpec
+	s.MaxSpec = maxSpec
+
+	for i, requirement := range s.Storage {
+		if requirement.Tier == "" {
+			continue
+		}
+		tier, ok := registry.Resolve(requirement.Tier)
+		if !ok {
+			return fmt.Errorf("storage[%d]: unknown resource key %q", i, requirement.Tier)
+		}
+		if !registry.IsStorageTier(tier) {
+			return fmt.Errorf("storage[%d]: %s is not a storage tier (storage tiers: %s)", i, tier, strings.Join(registry.StorageTiers(), ", "))
+		}
+		s.Storage[i].Tier = tier
+	}
+	return nil
+}
+
+func (ws *WeightedSelector) validate() error {
+	if ws.Weight < 1 || ws.Weight > 100 {
+		return fmt.Errorf("weight must be between 1 and 100, got %d", ws.Weight)
+	}
+	if err := ws.TagSelector.Validate(); err != nil {
+		return err
+	}
+	if ws.Hardware != nil {
+		if err := ws.Hardware.Validate(); err != nil {
+			return fmt.Errorf("hardware: %w", err)
+		}
+	}
+	if ws.Service != nil && len(ws.Service.Services) == 0 {
+		return fmt.Errorf("service.services is required")
+	}
+	return nil
+}
+
 // CanPlaceOn checks if service can be placed on compute based on placement rules
-func (s *Service) CanPlaceOn(compute *Compute, existingAssignments []*Assignment) bool {
+func (s *Service) CanPlaceOn(compute *Compute, existingAssignments []*Assignment, computes []*Compute, services map[string]*Service) bool {
+	return s.CheckPlacement(compute, existingAssignments, computes, services) == nil
+}
+
+// CheckPlacement returns an error describing the first placement rule the compute violates.
+// Computes and services are used to resolve the topology domain and name of existing assignments.
+func (s *Service) CheckPlacement(compute *Compute, existingAssignments []*Assignment, computes []*Compute, services map[string]*Service) error {
 	// Check affinity rules (must match)
-	for _, selector := range s.Placement.Affinity {
+	for i, selector := range s.Placement.Affinity {
 		if !selector.Matches(compute.Tags) {
-			return false
+			return fmt.Errorf("affinity[%d] does not match compute tags", i)
 		}
 	}
 
 	// Check anti-affinity rules (must NOT match)
-	for _, selector := range s.Placement.AntiAffinity {
+	for i, selector := range s.Placement.AntiAffinity {
 		if selector.Matches(compute.Tags) {
-			return false
+			return fmt.Errorf("antiAffinity[%d] matches compute tags", i)
+		}
+	}
+
+	// Check hardware affinity rules (facts of the compute components must match)
+	for i, selector := range s.Placement.HardwareAffinity {
+		if !selector.MatchesFacts(compute.Facts) {
+			return fmt.Errorf("hardwareAffinity[%d] does not match compute hardware", i)
+		}
+	}
+
+	// Check hardware anti-affinity rules (facts of the compute components must NOT match)
+	for i, selector := range s.Placement.HardwareAntiAffinity {
+		if selector.MatchesFacts(compute.Facts) {
+			return fmt.Errorf("hardwareAntiAffinity[%d] matches compute hardware", i)
+		}
+	}
+
+	// Check topology key (compute must carry the tag to be part of a spread domain)
+	if s.Placement.TopologyKey != "" {
+		if _, ok := compute.TopologyValue(s.Placement.TopologyKey); !ok {
+			return fmt.Errorf("compute has no value for topology key %q", s.Placement.TopologyKey)
 		}
 	}
 
 	// Check spread constraint (max instances per compute)
 	if s.Placement.SpreadMax > 0 {
-		count := 0
-		for _, assignment := range existingAssignments {
-			if assignment.ServiceID == s.ID && assignment.ComputeID == compute.ID {
-				count++
+		if s.instancesOn(compute.ID, existingAssignments) >= s.Placement.SpreadMax {
+			return fmt.Errorf("spreadMax %d reached on compute", s.Placement.SpreadMax)
+		}
+	}
+
+	// Check service affinity (a selected service must run in the same topology domain)
+	for i, selector := range s.Placement.ServiceAffinity {
+		if sharesDomain(compute, selector, existingAssignments, computes, services) {
+			continue
+		}
+		// A service selecting itself may start anywhere when no instance exists yet
+		if selector.Selects(s) && !anyInstance(selector, existingAssignments, services) {
+			continue
+		}
+		return fmt.Errorf("serviceAffinity[%d] not satisfied: none of %v in the same %s", i, selector.Services, topologyKeyOrHost(selector.TopologyKey))
+	}
+
+	// Check service anti-affinity (no selected service may run in the same topology domain)
+	for i, selector := range s.Placement.ServiceAntiAffinity {
+		if sharesDomain(compute, selector, existingAssignments, computes, services) {
+			return fmt.Errorf("serviceAntiAffinity[%d] violated: one of %v in the same %s", i, selector.Services, topologyKeyOrHost(selector.TopologyKey))
+		}
+	}
+
+	// Check anti-affinity declared by other services against this one
+	for _, other := range services {
+		if other.ID == s.ID {
+			continue
+		}
+		for i, selector := range other.Placement.ServiceAntiAffinity {
+			if !selector.Selects(s) {
+				continue
+			}
+			otherSelector := ServiceSelector{Services: []string{other.ID}, TopologyKey: selector.TopologyKey}
+			if sharesDomain(compute, otherSelector, existingAssignments, computes, services) {
+				return fmt.Errorf("service %s serviceAntiAffinity[%d] excludes this service from the same %s", other.Name, i, topologyKeyOrHost(selector.TopologyKey))
 			}
 		}
-		if count >= s.Placement.SpreadMax {
-			return false
+	}
+
+	return nil
+}
+
+// sharesDomain checks if any service matched by the selector has an instance in the compute's topology domain
+func sharesDomain(compute *Compute, selector ServiceSelector, assignments []*Assignment, computes []*Compute, services map[string]*Service) bool {
+	key := topologyKeyOrHost(selector.TopologyKey)
+	domain, ok := compute.TopologyValue(key)
+	if !ok {
+		return false
+	}
+
+	for _, assignment := range assignments {
+		service, ok := services[assignment.ServiceID]
+		if !ok || !selector.Selects(service) {
+			continue
+		}
+		for _, other := range computes {
+			if other.ID != assignment.ComputeID {
+				continue
+			}
+			if value, ok := other.TopologyValue(key); ok && value == domain {
+				return true
+			}
+			break
 		}
 	}
 
-	return true
+	return false
+}
+
+// anyInstance checks if any service matched by the selector is assigned anywhere
+func anyInstance(selector ServiceSelector, assignments []*Assignment, services map[string]*Service) bool {
+	for _, assignment := range assignments {
+		if service, ok := services[assignment.ServiceID]; ok && selector.Selects(service) {
+			return true
+		}
+	}
+	return false
+}
+
+func topologyKeyOrHost(key string) string {
+	if key == "" {
+		return TopologyKeyHost
+	}
+	return key
+}
+
+// CheckInstancesApart returns an error when quantity instances of the service placed on the
+// compute together break a serviceAntiAffinity rule of the service that selects the service
+// itself, since the instances then share every topology domain of the compute
+func (s *Service) CheckInstancesApart(compute *Compute, quantity int) error {
+	if quantity < 2 {
+		return nil
+	}
+	for i, selector := range s.Placement.ServiceAntiAffinity {
+		if !selector.Selects(s) {
+			continue
+		}
+		if _, ok := compute.TopologyValue(topologyKeyOrHost(selector.TopologyKey)); ok {
+			return fmt.Errorf("serviceAntiAffinity[%d] violated: %d instances of %s in the same %s", i, quantity, s.Name, topologyKeyOrHost(selector.TopologyKey))
+		}
+	}
+	return nil
+}
+
+// CheckSpreadMax returns an error when placing quantity more instances on the compute would exceed
+// SpreadMax, counting the instances the assignments already place there
+func (s *Service) CheckSpreadMax(computeID string, assignments []*Assignment, quantity int) error {
+	if s.Placement.SpreadMax == 0 {
+		return nil
+	}
+	if existing := s.instancesOn(computeID, assignments); existing+quantity > s.Placement.SpreadMax {
+		return fmt.Errorf("spreadMax %d exceeded on compute: %d instance(s) assigned, %d requested", s.Placement.SpreadMax, existing, quantity)
+	}
+	return nil
+}
+
+// instancesOn counts the instances of the service assigned to a compute, honoring assignment quantity
+func (s *Service) instancesOn(computeID string, assignments []*Assignment) int {
+	count := 0
+	for _, assignment := range assignments {
+		if assignment.ServiceID == s.ID && assignment.ComputeID == computeID {
+			count += assignmentQuantity(assignment)
+		}
+	}
+	return count
 }
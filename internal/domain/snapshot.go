@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// CapacitySnapshot is the capacity of every compute at one point in time, persisted to follow
+// utilization over time
+type CapacitySnapshot struct {
+	ID          string           `json:"id"`
+	TakenAt     time.Time        `json:"taken_at"`
+	Reservation ReservationBasis `json:"reservation"` // Basis the allocations were reserved under
+	Computes    int              `json:"computes"`    // Number of samples
+	Samples     []ComputeSample  `json:"samples,omitempty"`
+}
+
+// ComputeSample is the capacity of one compute in a snapshot. Compute attributes are copied so
+// the history survives renames and deletions.
+type ComputeSample struct {
+	ComputeID      string            `json:"compute_id"`
+	ComputeName    string            `json:"compute_name"`
+	Type           ComputeType       `json:"type"`
+	ParentID       string            `json:"parent_id,omitempty"`
+	Provider       string            `json:"provider"`
+	Region         string            `json:"region"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	State          ComputeState      `json:"state"`
+	Total          Resources         `json:"total"`
+	Allocated      Resources         `json:"allocated"`
+	Available      Resources         `json:"available"`
+	UtilizationPct float64           `json:"utilization_pct"`
+}
+
+// Snapshot grouping keys besides region, provider and tag keys
+const (
+	SnapshotGroupAll     = "all"     // Every compute in one group
+	SnapshotGroupCompute = "compute" // One group per compute
+)
+
+// NewCapacitySnapshot records the utilization of a capacity report
+func NewCapacitySnapshot(report *CapacityReport, takenAt time.Time) *CapacitySnapshot {
+	snapshot := &CapacitySnapshot{
+		TakenAt:     takenAt,
+		Reservation: report.Reservation,
+		Samples:     make([]ComputeSample, 0, len(report.ComputeUtilization)),
+	}
+
+	for _, util := range report.ComputeUtilization {
+		snapshot.Samples = append(snapshot.Samples, ComputeSample{
+			ComputeID:      util.Compute.ID,
+			ComputeName:    util.Compute.Name,
+			Type:           util.Compute.Type,
+			ParentID:       util.Compute.ParentID,
+			Provider:       util.Compute.Provider,
+			Region:         util.Compute.Region,
+			Tags:           util.Compute.Tags,
+			State:          util.Compute.State,
+			Total:          util.TotalResources,
+			Allocated:      util.Allocated,
+			Available:      util.Available,
+			UtilizationPct: util.UtilizationPct,
+		})
+	}
+	snapshot.Computes = len(snapshot.Samples)
+
+	return snapshot
+}
+
+// GroupValue returns the value of the grouping key for the sample: all, compute or host (its
+// name), region, provider or a tag key. ok is false when the sample has no value for a tag key.
+func (s *ComputeSample) GroupValue(key string) (string, bool) {
+	switch key {
+	case "", SnapshotGroupAll:
+		return SnapshotGroupAll, true
+	case SnapshotGroupCompute, TopologyKeyHost:
+		return s.ComputeName, true
+	case TopologyKeyRegion:
+		return s.Region, s.Region != ""
+	case TopologyKeyProvider:
+		return s.Provider, s.Provider != ""
+	}
+	value, ok := s.Tags[key]
+	return value, ok && value != ""
+}
+
+// CapacityHistory is the utilization of groups of computes over time
+type CapacityHistory struct {
+	GroupBy string           `json:"group_by"`
+	Series  []CapacitySeries `json:"series"`
+}
+
+// CapacitySeries is the utilization of one group, one point per snapshot
+type CapacitySeries struct {
+	Group  string        `json:"group"`
+	Points []SeriesPoint `json:"points"`
+}
+
+// SeriesPoint sums the capacity of the computes of a group in one snapshot
+type SeriesPoint struct {
+	Time           time.Time `json:"time"`
+	Computes       int       `json:"computes"`
+	Total          Resources `json:"total"`
+	Allocated      Resources `json:"allocated"`
+	Available      Resources `json:"available"`
+	UtilizationPct float64   `json:"utilization_pct"`
+}
+
+// BuildCapacityHistory groups the samples of the snapshots by region, provider, a tag key, all
+// computes or each compute. Groups only sum active computes that are not hosted, since the
+// resources of a VM are already allocated on its hypervisor; per compute, every sample is kept.
+func BuildCapacityHistory(snapshots []*CapacitySnapshot, groupBy string) *CapacityHistory {
+	switch groupBy {
+	case "":
+		groupBy = SnapshotGroupAll
+	case TopologyKeyHost:
+		groupBy = SnapshotGroupCompute
+	}
+
+	ordered := make([]*CapacitySnapshot, len(snapshots))
+	copy(ordered, snapshots)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].TakenAt.Before(ordered[j].TakenAt)
+	})
+
+	byGroup := make(map[string]*CapacitySeries)
+	groups := make([]string, 0)
+
+	for _, snapshot := range ordered {
+		points := make(map[string]*SeriesPoint)
+		for i := range snapshot.Samples {
+			sample := &snapshot.Samples[i]
+			if groupBy != SnapshotGroupCompute && (sample.ParentID != "" || sample.State != ComputeStateActive) {
+				continue
+			}
+			group, ok := sample.GroupValue(groupBy)
+			if !ok {
+				continue
+			}
+
+			point, ok := points[group]
+			if !ok {
+				point = &SeriesPoint{Time: snapshot.TakenAt, Total: make(Resources), Allocated: make(Resources), Available: make(Resources)}
+				points[group] = point
+			}
+			point.Computes++
+			point.Total = point.Total.Add(sample.Total)
+			point.Allocated = point.Allocated.Add(sample.Allocated)
+			point.Available = point.Available.Add(sample.Available)
+		}
+
+		for group, point := range points {
+			point.UtilizationPct = UtilizationPct(point.Total, point.Allocated)
+			series, ok := byGroup[group]
+			if !ok {
+				series = &CapacitySeries{Group: group}
+				byGroup[group] = series
+				groups = append(groups, group)
+			}
+			series.Points = append(series.Points, *point)
+		}
+	}
+
+	sort.Strings(groups)
+	history := &CapacityHistory{GroupBy: groupBy, Series: make([]CapacitySeries, 0, len(groups))}
+	for _, group := range groups {
+		history.Series = append(history.Series, *byGroup[group])
+	}
+	return history
+}
+
+// utilization returns the utilization of a point, of one resource key when resource is set.
+// ok is false when the point has no capacity for the key.
+func (p *SeriesPoint) utilization(resource string) (float64, bool) {
+	if resource == "" {
+		return p.UtilizationPct, true
+	}
+	total := p.Total[resource]
+	if total <= 0 {
+		return 0, false
+	}
+	return float64(p.Allocated[resource]/total) * 100, true
+}
@@ -0,0 +1,202 @@
+package domain
+
+import "fmt"
+
+// StackPlanRequest plans a group of services together (e.g. an app, its queue and its database)
+type StackPlanRequest struct {
+	Members     []StackMember `json:"members"`
+	Strategy    string        `json:"strategy,omitempty"`
+	Constraints Constraints   `json:"constraints,omitempty"`
+	// Window the stack needs capacity for; only assignments running during it are counted
+	Schedule
+}
+
+// StackMember is a service and replica count in a stack plan
+type StackMember struct {
+	ServiceID string `json:"service_id"`
+	Replicas  int    `json:"replicas,omitempty"` // Default 1
+}
+
+// StackPlanResult contains the placements of every stack member. The stack is feasible only
+// when every replica of every member could be placed.
+type StackPlanResult struct {
+	Feasible bool                `json:"feasible"`
+	Strategy string              `json:"strategy,omitempty"`
+	Members  []StackMemberResult `json:"members"`
+	Applied  bool                `json:"applied"`
+	// Assignments created or updated when the stack is applied
+	Assignments []*Assignment    `json:"assignments,omitempty"`
+	Message     string           `json:"message,omitempty"`
+	Reservation ReservationBasis `json:"reservation"` // Spec each instance reserves (min, max or pNN)
+	Schedule                     // Window planned for, given to the planned assignments
+}
+
+// StackMemberResult contains the placements of one stack member
+type StackMemberResult struct {
+	ServiceID       string           `json:"service_id"`
+	ServiceName     string           `json:"service_name"`
+	Replicas        int              `json:"replicas"`
+	Feasible        bool             `json:"feasible"`
+	Placements      []Placement      `json:"placements,omitempty"`
+	Spread          *SpreadResult    `json:"spread,omitempty"`
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+	Message         string           `json:"message,omitempty"`
+}
+
+// SpreadSatisfied checks if every member with a topology key met its spread
+func (r *StackPlanResult) SpreadSatisfied() bool {
+	for _, member := range r.Members {
+		if member.Spread != nil && !member.Spread.Satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanStack places the members in order. Capacity taken by earlier members of the same
+// plan is counted as allocated when placing later members, and service affinity rules
+// can refer to earlier members.
+func (cp *CapacityPlanner) PlanStack(request StackPlanRequest) (*StackPlanResult, error) {
+	if len(request.Members) == 0 {
+		return nil, fmt.Errorf("stack has no members")
+	}
+
+	strategy, err := GetScoringStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build services map for resource calculation
+	servicesMap := make(map[string]*Service)
+	for _, svc := range cp.services {
+		servicesMap[svc.ID] = svc
+	}
+
+	result := &StackPlanResult{
+		Feasible:    true,
+		Strategy:    strategy.Name(),
+		Members:     make([]StackMemberResult, 0, len(request.Members)),
+		Reservation: cp.reservation,
+		Schedule:    request.Schedule,
+	}
+
+	working := ScheduledAssignments(cp.assignments, request.Schedule)
+
+	for _, member := range request.Members {
+		service, ok := servicesMap[member.ServiceID]
+		if !ok {
+			return nil, fmt.Errorf("service %s not found", member.ServiceID)
+		}
+
+		replicas := member.Replicas
+		if replicas <= 0 {
+			replicas = 1
+		}
+
+		memberRequest := PlanRequest{
+			ServiceID:   service.ID,
+			Replicas:    replicas,
+			Strategy:    request.Strategy,
+			Constraints: request.Constraints,
+			Schedule:    request.Schedule,
+		}
+
+		placements, spread, next := cp.placeReplicas(service, memberRequest, strategy, replicas, working, servicesMap)
+
+		memberResult := StackMemberResult{
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			Replicas:    replicas,
+			Feasible:    len(placements) == replicas,
+			Placements:  placements,
+			Spread:      spread,
+		}
+
+		if !memberResult.Feasible {
+			result.Feasible = false
+			memberResult.Recommendations = cp.generateRecommendations(service, replicas-len(placements))
+			memberResult.Message = fmt.Sprintf("only %d of %d replicas could be placed", len(placements), replicas)
+		} else if spread != nil && !spread.Satisfied {
+			memberResult.Message = fmt.Sprintf("topology spread on %q not met: %d replicas placed across %d distinct values",
+				spread.TopologyKey, replicas, distinctTopologyValues(placements))
+		}
+
+		result.Members = append(result.Members, memberResult)
+		working = next
+	}
+
+	if result.Feasible {
+		result.Message = "all stack members placed"
+	} else {
+		result.Message = "stack does not fit, nothing can be applied"
+	}
+
+	return result, nil
+}
+
+// PlannedAssignments merges the planned placements into the existing assignments. Existing
+// assignments of the same service, compute and schedule get their quantity increased; new
+// assignments are returned without an ID, with the schedule of the plan.
+func (r *StackPlanResult) PlannedAssignments(existing []*Assignment) []*Assignment {
+	merged := newAssignmentMerge(existing, r.Schedule)
+	for _, member := range r.Members {
+		for _, placement := range member.Placements {
+			merged.add(member.ServiceID, placement.Compute.ID)
+		}
+	}
+	return merged.planned
+}
+
+// PlannedAssignments merges the placements of the plan into the existing assignments of the
+// service, as StackPlanResult.PlannedAssignments does
+func (r *PlanResult) PlannedAssignments(serviceID string, existing []*Assignment) []*Assignment {
+	merged := newAssignmentMerge(existing, r.Schedule)
+	for _, placement := range r.Placements {
+		merged.add(serviceID, placement.Compute.ID)
+	}
+	return merged.planned
+}
+
+// assignmentMerge adds placements one instance at a time to new or existing assignments
+type assignmentMerge struct {
+	existing []*Assignment
+	schedule Schedule
+	byKey    map[string]*Assignment
+	planned  []*Assignment
+}
+
+func newAssignmentMerge(existing []*Assignment, schedule Schedule) *assignmentMerge {
+	return &assignmentMerge{
+		existing: existing,
+		schedule: schedule,
+		byKey:    make(map[string]*Assignment),
+		planned:  make([]*Assignment, 0),
+	}
+}
+
+// add places one instance of the service on the compute
+func (m *assignmentMerge) add(serviceID, computeID string) {
+	key := serviceID + "/" + computeID
+	if assignment, ok := m.byKey[key]; ok {
+		assignment.Quantity++
+		return
+	}
+
+	assignment := &Assignment{
+		ServiceID: serviceID,
+		ComputeID: computeID,
+		Quantity:  1,
+		Schedule:  m.schedule,
+	}
+	for _, e := range m.existing {
+		if e.ServiceID == serviceID && e.ComputeID == computeID && e.Schedule.Equal(m.schedule) {
+			copied := *e
+			copied.Quantity = assignmentQuantity(e) + 1
+			assignment = &copied
+			break
+		}
+	}
+
+	m.byKey[key] = assignment
+	m.planned = append(m.planned, assignment)
+}
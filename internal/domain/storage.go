@@ -0,0 +1,412 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Redundancy is the protection of a storage group against disk failures
+type Redundancy string
+
+const (
+	RedundancyNone     Redundancy = "none"     // No protection (standalone disks, RAID0, JBOD)
+	RedundancyParity   Redundancy = "parity"   // Parity (RAID5, RAID6, RAID50, RAID60)
+	RedundancyMirrored Redundancy = "mirrored" // Mirrors (RAID1, RAID10)
+)
+
+// rank orders redundancies so that a group satisfies every requirement of a lower or equal
+// rank: mirrored groups satisfy parity requirements, any group satisfies none
+func (r Redundancy) rank() int {
+	switch r {
+	case RedundancyParity:
+		return 1
+	case RedundancyMirrored:
+		return 2
+	}
+	return 0
+}
+
+// Satisfies checks if a group with this redundancy meets the required redundancy
+func (r Redundancy) Satisfies(required Redundancy) bool {
+	return r.rank() >= required.rank()
+}
+
+// Redundancy returns the protection of a RAID level
+func (l RaidLevel) Redundancy() Redundancy {
+	switch l {
+	case RaidLevel1, RaidLevel10:
+		return RedundancyMirrored
+	case RaidLevel5, RaidLevel6, RaidLevel50, RaidLevel60:
+		return RedundancyParity
+	}
+	return RedundancyNone
+}
+
+// DefaultStorageTiers are the storage resource keys derived from storage components by default
+var DefaultStorageTiers = []string{"nvme", "ssd", "hdd", "storage"}
+
+// StorageRequirement is storage a service instance needs on a single storage group of a compute
+type StorageRequirement struct {
+	Name       string     `json:"name,omitempty"`       // Names the volume, so that a volume provisioned for it in a pool can provide it
+	Tier       string     `json:"tier,omitempty"`       // Storage resource key (nvme, ssd, hdd, storage); empty for any tier
+	Size       Quantity   `json:"size"`                 // In GB
+	Redundancy Redundancy `json:"redundancy,omitempty"` // Minimum redundancy (default none)
+}
+
+// UnmarshalJSON accepts the size in GB or as a quantity string with a unit, e.g. "2TB"
+func (r *StorageRequirement) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name       string          `json:"name"`
+		Tier       string          `json:"tier"`
+		Size       json.RawMessage `json:"size"`
+		Redundancy Redundancy      `json:"redundancy"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	size, err := parseStorageSize(raw.Size)
+	if err != nil {
+		return err
+	}
+	r.Name = raw.Name
+	r.Tier = raw.Tier
+	r.Redundancy = raw.Redundancy
+	r.Size = size
+	return nil
+}
+
+// parseStorageSize reads a storage size in GB from a JSON number or quantity string
+func parseStorageSize(raw json.RawMessage) (Quantity, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	var number float64
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return Quantity(number), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, fmt.Errorf("storage size must be a number of GB or a quantity string such as \"2TB\"")
+	}
+	return ParseQuantity("storage", text)
+}
+
+// Validate checks the requirement fields
+func (r StorageRequirement) Validate() error {
+	if r.Size <= 0 {
+		return fmt.Errorf("size must be positive")
+	}
+	switch r.Redundancy {
+	case "", RedundancyNone, RedundancyParity, RedundancyMirrored:
+	default:
+		return fmt.Errorf("unknown redundancy %q (use none, parity or mirrored)", r.Redundancy)
+	}
+	return nil
+}
+
+// Matches checks if the group is of the required tier and redundancy, regardless of free space
+func (r StorageRequirement) Matches(group StorageGroup) bool {
+	if r.Tier != "" && r.Tier != group.Tier {
+		return false
+	}
+	return group.Redundancy.Satisfies(r.Redundancy)
+}
+
+// String describes the requirement, e.g. "200 GB nvme mirrored" or "data: 200 GB nvme mirrored"
+func (r StorageRequirement) String() string {
+	tier := r.Tier
+	if tier == "" {
+		tier = "any tier"
+	}
+	redundancy := r.Redundancy
+	if redundancy == "" {
+		redundancy = RedundancyNone
+	}
+	if r.Name != "" {
+		return fmt.Sprintf("%s: %s GB %s %s", r.Name, r.Size, tier, redundancy)
+	}
+	return fmt.Sprintf("%s GB %s %s", r.Size, tier, redundancy)
+}
+
+// StorageSpec returns the storage requirements summed per tier; requirements for any tier
+// count as generic storage
+func (s *Service) StorageSpec() Resources {
+	spec := make(Resources)
+	for _, requirement := range s.Storage {
+		tier := requirement.Tier
+		if tier == "" {
+			tier = "storage"
+		}
+		spec[tier] += requirement.Size
+	}
+	return spec
+}
+
+// mirroredTiers returns the tiers with a requirement for mirrored storage
+func (s *Service) mirroredTiers() map[string]bool {
+	tiers := make(map[string]bool)
+	for _, requirement := range s.Storage {
+		if requirement.Redundancy == RedundancyMirrored {
+			tier := requirement.Tier
+			if tier == "" {
+				tier = "storage"
+			}
+			tiers[tier] = true
+		}
+	}
+	return tiers
+}
+
+// StorageGroup is usable storage of a compute that one volume can live on: a RAID group, the
+// standalone disks of one tier, or a pool built on RAID groups
+type StorageGroup struct {
+	Name       string     `json:"name"` // RAID group, the tier for standalone disks, or the pool
+	Tier       string     `json:"tier"`
+	RaidLevel  RaidLevel  `json:"raid_level"` // Empty for a pool on RAID groups of different levels
+	Redundancy Redundancy `json:"redundancy"`
+	Disks      int        `json:"disks"`
+	Capacity   Quantity   `json:"capacity"`            // Usable capacity in GB
+	Allocated  Quantity   `json:"allocated,omitempty"` // Volumes of the pool, plus what AllocateStorage sets
+	Pool       PoolType   `json:"pool,omitempty"`      // Set for a pool
+	Volumes    []*Volume  `json:"volumes,omitempty"`   // Volumes carved out of the pool
+}
+
+// Available returns the capacity not allocated
+func (g StorageGroup) Available() Quantity {
+	return g.Capacity - g.Allocated
+}
+
+// GetStorageGroupsFromComponents returns the storage groups of the components assigned to the
+// compute, using the derivation rules with raid aggregation. RAID groups that cannot be built are
+// left out. nil rules use DefaultDerivationRules.
+func (c *Compute) GetStorageGroupsFromComponents(components []*Component, assignments []*ComputeComponent, rules []*DerivationRule) []StorageGroup {
+	if rules == nil {
+		rules = DefaultDerivationRules()
+	}
+	_, layouts := deriveFromComponents(c.ID, components, assignments, rules)
+
+	groups := make([]StorageGroup, 0, len(layouts))
+	for _, layout := range layouts {
+		if layout.Error != "" || layout.Usable <= 0 {
+			continue
+		}
+		groups = append(groups, StorageGroup{
+			Name:       layout.Group,
+			Tier:       layout.Tier,
+			RaidLevel:  layout.RaidLevel,
+			Redundancy: layout.RaidLevel.Redundancy(),
+			Disks:      layout.Disks,
+			Capacity:   Quantity(math.Trunc(layout.Usable.Float())),
+		})
+	}
+	return groups
+}
+
+// Storage returns the storage groups of the compute. A compute without storage components,
+// such as a VM sized out of its host, has one unprotected group per storage tier it has.
+func (c *Compute) Storage() []StorageGroup {
+	if len(c.StorageGroups) > 0 {
+		return c.StorageGroups
+	}
+
+	groups := make([]StorageGroup, 0)
+	for _, tier := range DefaultStorageTiers {
+		if capacity := c.Resources[tier]; capacity > 0 {
+			groups = append(groups, StorageGroup{
+				Name:       tier,
+				Tier:       tier,
+				RaidLevel:  RaidLevelNone,
+				Redundancy: RedundancyNone,
+				Capacity:   capacity,
+			})
+		}
+	}
+	return groups
+}
+
+// AllocateStorage returns the storage groups of the compute with the requirements of the
+// service instances assigned to it allocated, in assignment order. A named requirement provided
+// by a volume provisioned for the service in a pool uses the volume, which is already allocated.
+// Instances whose storage does not fit (forced assignments) are left out.
+func (c *Compute) AllocateStorage(assignments []*Assignment, services map[string]*Service) []StorageGroup {
+	groups, _ := c.allocateStorage(assignments, services)
+	return groups
+}
+
+// allocateStorage allocates the storage of the assignments like AllocateStorage, and also
+// returns the IDs of the volumes the instances use
+func (c *Compute) allocateStorage(assignments []*Assignment, services map[string]*Service) ([]StorageGroup, map[string]bool) {
+	source := c.Storage()
+	groups := make([]StorageGroup, len(source))
+	copy(groups, source)
+	claimed := make(map[string]bool)
+
+	for _, assignment := range assignments {
+		if assignment.ComputeID != c.ID {
+			continue
+		}
+		service, ok := services[assignment.ServiceID]
+		if !ok || len(service.Storage) == 0 {
+			continue
+		}
+		for i := 0; i < assignmentQuantity(assignment); i++ {
+			requirements, _, volumes := claimVolumes(groups, service, claimed)
+			if allocateRequirements(groups, requirements) < 0 {
+				for _, id := range volumes {
+					claimed[id] = true
+				}
+			}
+		}
+	}
+
+	return groups, claimed
+}
+
+// storageAllocated returns the storage the volumes of pools and the requirements of the instances
+// assigned to the compute take, summed per tier of the storage groups they are allocated on
+func (c *Compute) storageAllocated(assignments []*Assignment, services map[string]*Service) Resources {
+	groups, _ := c.allocateStorage(assignments, services)
+	allocated := make(Resources)
+	for _, group := range groups {
+		if group.Allocated > 0 {
+			allocated[group.Tier] += group.Allocated
+		}
+	}
+	return allocated
+}
+
+// allocatedSince sums, per tier, what was allocated on the groups since before was copied
+func allocatedSince(before, after []StorageGroup) Resources {
+	allocated := make(Resources)
+	for i, group := range after {
+		if taken := group.Allocated - before[i].Allocated; taken > 0 {
+			allocated[group.Tier] += taken
+		}
+	}
+	return allocated
+}
+
+// CheckStorage returns an error describing the first storage requirement of the service that
+// does not fit on the compute for quantity more instances, once the existing assignments are
+// allocated. Otherwise it returns the storage the new instances take per tier, which also
+// counts against the tier resources of the compute.
+func (c *Compute) CheckStorage(service *Service, quantity int, assignments []*Assignment, services map[string]*Service) (Resources, error) {
+	if len(service.Storage) == 0 {
+		return nil, nil
+	}
+
+	groups, claimed := c.allocateStorage(assignments, services)
+	before := make([]StorageGroup, len(groups))
+	copy(before, groups)
+	for i := 0; i < quantity; i++ {
+		requirements, indexes, volumes := claimVolumes(groups, service, claimed)
+		failed := allocateRequirements(groups, requirements)
+		if failed < 0 {
+			for _, id := range volumes {
+				claimed[id] = true
+			}
+			continue
+		}
+
+		index := indexes[failed]
+		requirement := service.Storage[index]
+		largest := Quantity(0)
+		matching := 0
+		for _, group := range groups {
+			if requirement.Matches(group) {
+				matching++
+				if group.Available() > largest {
+					largest = group.Available()
+				}
+			}
+		}
+		if matching == 0 {
+			return nil, fmt.Errorf("storage[%d]: no storage group for %s", index, requirement)
+		}
+		return nil, fmt.Errorf("storage[%d]: no storage group has %s free (largest matching group has %s GB free)", index, requirement, largest)
+	}
+
+	return allocatedSince(before, groups), nil
+}
+
+// claimVolumes finds, for each named requirement of the service, a volume not yet claimed that
+// was provisioned for it in a matching pool and is large enough. It returns the requirements no
+// volume provides with their indexes in the service storage, and the IDs of the volumes found.
+func claimVolumes(groups []StorageGroup, service *Service, claimed map[string]bool) ([]StorageRequirement, []int, []string) {
+	requirements := make([]StorageRequirement, 0, len(service.Storage))
+	indexes := make([]int, 0, len(service.Storage))
+	volumes := make([]string, 0)
+	found := make(map[string]bool)
+
+	for index, requirement := range service.Storage {
+		volume := ""
+		if requirement.Name != "" {
+		search:
+			for _, group := range groups {
+				if !requirement.Matches(group) {
+					continue
+				}
+				for _, v := range group.Volumes {
+					if v.ServiceID == service.ID && v.Requirement == requirement.Name && v.Size >= requirement.Size && !claimed[v.ID] && !found[v.ID] {
+						volume = v.ID
+						break search
+					}
+				}
+			}
+		}
+
+		if volume == "" {
+			requirements = append(requirements, requirement)
+			indexes = append(indexes, index)
+			continue
+		}
+		found[volume] = true
+		volumes = append(volumes, volume)
+	}
+
+	return requirements, indexes, volumes
+}
+
+// allocateRequirements allocates every requirement to a matching group with enough free
+// space, preferring the least redundant and then the fullest group so that better groups stay
+// free. It returns -1 when all fit, or else the index of the first requirement that does not
+// fit, with nothing allocated.
+func allocateRequirements(groups []StorageGroup, requirements []StorageRequirement) int {
+	order := make([]int, len(groups))
+	before := make([]Quantity, len(groups))
+	for i := range order {
+		order[i] = i
+		before[i] = groups[i].Allocated
+	}
+
+	for index, requirement := range requirements {
+		sort.SliceStable(order, func(a, b int) bool {
+			ga, gb := groups[order[a]], groups[order[b]]
+			if ga.Redundancy.rank() != gb.Redundancy.rank() {
+				return ga.Redundancy.rank() < gb.Redundancy.rank()
+			}
+			return ga.Available() < gb.Available()
+		})
+
+		allocated := false
+		for _, i := range order {
+			if requirement.Matches(groups[i]) && groups[i].Available() >= requirement.Size {
+				groups[i].Allocated += requirement.Size
+				allocated = true
+				break
+			}
+		}
+		if !allocated {
+			for i := range groups {
+				groups[i].Allocated = before[i]
+			}
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Built-in scoring strategy names
+const (
+	StrategyBalanced         = "balanced"
+	StrategyBinPack          = "binpack"
+	StrategySpread           = "spread"
+	StrategyCheapest         = "cheapest"
+	StrategyDominantResource = "dominant-resource"
+)
+
+// DefaultStrategy is used when a plan request does not name a strategy
+const DefaultStrategy = StrategyBalanced
+
+// ScoreInput holds what a scoring strategy knows about a candidate compute
+type ScoreInput struct {
+	Service          *Service
+	Compute          *Compute
+	Reserved         Resources          // Resources one instance reserves under the planner's reservation basis
+	Available        Resources          // Available resources before placement
+	AvailableAfter   Resources          // Available resources after placing the reserved spec
+	Utilization      map[string]float64 // Utilization per resource key after placement (0.0-1.0)
+	UtilizationAfter float64            // Average utilization after placement (0.0-1.0)
+}
+
+// ScoringStrategy ranks candidate computes. Higher scores are preferred.
+type ScoringStrategy interface {
+	Name() string
+	Score(input ScoreInput) float64
+}
+
+var scoringStrategies = map[string]ScoringStrategy{}
+
+func init() {
+	RegisterScoringStrategy(balancedStrategy{})
+	RegisterScoringStrategy(binPackStrategy{})
+	RegisterScoringStrategy(spreadStrategy{})
+	RegisterScoringStrategy(cheapestStrategy{})
+	RegisterScoringStrategy(dominantResourceStrategy{})
+}
+
+// RegisterScoringStrategy makes a strategy selectable by name, replacing any strategy with the same name
+func RegisterScoringStrategy(strategy ScoringStrategy) {
+	scoringStrategies[strategy.Name()] = strategy
+}
+
+// GetScoringStrategy returns the named strategy, or the default strategy for an empty name
+func GetScoringStrategy(name string) (ScoringStrategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+	strategy, ok := scoringStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring strategy %q (available: %v)", name, ScoringStrategies())
+	}
+	return strategy, nil
+}
+
+// ScoringStrategies returns the names of all registered strategies
+func ScoringStrategies() []string {
+	names := make([]string, 0, len(scoringStrategies))
+	for name := range scoringStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// balancedStrategy prefers computes close to 65% average utilization after placement
+type balancedStrategy struct{}
+
+func (balancedStrategy) Name() string { return StrategyBalanced }
+
+func (balancedStrategy) Score(input ScoreInput) float64 {
+	// Ideal target is around 60-70% utilization
+	targetUtilization := 0.65
+	return 100.0 - (100.0 * abs(input.UtilizationAfter-targetUtilization))
+}
+
+// binPackStrategy fills the most utilized computes first
+type binPackStrategy struct{}
+
+func (binPackStrategy) Name() string { return StrategyBinPack }
+
+func (binPackStrategy) Score(input ScoreInput) float64 {
+	return 100.0 * input.UtilizationAfter
+}
+
+// spreadStrategy prefers the least loaded computes
+type spreadStrategy struct{}
+
+func (spreadStrategy) Name() string { return StrategySpread }
+
+func (spreadStrategy) Score(input ScoreInput) float64 {
+	return 100.0 * (1.0 - input.UtilizationAfter)
+}
+
+// cheapestStrategy prefers the lowest monthly cost per instance slot, where slots is the
+// number of service instances (by reserved spec) the free resources can still hold.
+// Computes without billing data score 0.
+type cheapestStrategy struct{}
+
+func (cheapestStrategy) Name() string { return StrategyCheapest }
+
+func (cheapestStrategy) Score(input ScoreInput) float64 {
+	monthlyCost := 0.0
+	if input.Compute.MonthlyCost != nil {
+		monthlyCost = *input.Compute.MonthlyCost
+	} else if input.Compute.AnnualCost != nil {
+		monthlyCost = *input.Compute.AnnualCost / 12
+	} else {
+		return 0
+	}
+
+	slots := 0.0
+	for key, value := range input.Reserved {
+		required := getFloatValue(Resources{key: value}, key)
+		if required <= 0 {
+			continue
+		}
+		fit := float64(int(getFloatValue(input.Available, key) / required))
+		if slots == 0 || fit < slots {
+			slots = fit
+		}
+	}
+	if slots < 1 {
+		slots = 1
+	}
+
+	costPerSlot := monthlyCost / slots
+	return 100.0 / (1.0 + costPerSlot)
+}
+
+// dominantResourceStrategy prefers computes whose resources stay evenly used after placement,
+// penalizing the gap between the most and least utilized resource
+type dominantResourceStrategy struct{}
+
+func (dominantResourceStrategy) Name() string { return StrategyDominantResource }
+
+func (dominantResourceStrategy) Score(input ScoreInput) float64 {
+	if len(input.Utilization) == 0 {
+		return 100.0
+	}
+
+	first := true
+	minUtil, maxUtil := 0.0, 0.0
+	for _, util := range input.Utilization {
+		if first || util < minUtil {
+			minUtil = util
+		}
+		if first || util > maxUtil {
+			maxUtil = util
+		}
+		first = false
+	}
+
+	return 100.0 * (1.0 - (maxUtil - minUtil))
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultTimelineWindow is how far ahead the capacity timeline looks by default
+const DefaultTimelineWindow = 90 * 24 * time.Hour
+
+// CapacityTimeline is the utilization of groups of computes at the start of a window and at
+// every scheduled change within it
+type CapacityTimeline struct {
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	GroupBy     string           `json:"group_by"`
+	Series      []CapacitySeries `json:"series"`
+	Events      []TimelineEvent  `json:"events"`
+	Reservation ReservationBasis `json:"reservation"` // Spec each instance reserves (min, max or pNN)
+}
+
+// Timeline event kinds
+const (
+	TimelineAssignmentStart   = "assignment-start"
+	TimelineAssignmentEnd     = "assignment-end"
+	TimelineReservationExpiry = "reservation-expiry"
+)
+
+// TimelineEvent is a scheduled change of allocations: an assignment starting or ending, or a
+// reservation releasing its capacity
+type TimelineEvent struct {
+	Time        time.Time `json:"time"`
+	Kind        string    `json:"kind"`
+	ServiceID   string    `json:"service_id,omitempty"`
+	ServiceName string    `json:"service_name,omitempty"`
+	ComputeID   string    `json:"compute_id,omitempty"`
+	ComputeName string    `json:"compute_name,omitempty"`
+	Quantity    int       `json:"quantity,omitempty"`
+	Reservation string    `json:"reservation,omitempty"` // Name of the expiring reservation
+}
+
+// BuildCapacityTimeline computes the capacity report at the start of the window and after each
+// scheduled change within it, counting the assignments running and the reservations holding
+// capacity at that instant, and groups the utilization like the capacity history. The computes
+// are left holding the reservations of the last instant.
+func BuildCapacityTimeline(computes []*Compute, services []*Service, assignments []*Assignment, reservations []*Reservation, basis ReservationBasis, from, to time.Time, groupBy string) *CapacityTimeline {
+	servicesMap := make(map[string]*Service)
+	for _, svc := range services {
+		servicesMap[svc.ID] = svc
+	}
+	computeNames := make(map[string]string)
+	for _, compute := range computes {
+		computeNames[compute.ID] = compute.Name
+	}
+
+	within := func(t *time.Time) bool {
+		return t != nil && t.After(from) && !t.After(to)
+	}
+
+	events := make([]TimelineEvent, 0)
+	for _, assignment := range assignments {
+		event := TimelineEvent{
+			ServiceID:   assignment.ServiceID,
+			ComputeID:   assignment.ComputeID,
+			ComputeName: computeNames[assignment.ComputeID],
+			Quantity:    assignmentQuantity(assignment),
+		}
+		if service, ok := servicesMap[assignment.ServiceID]; ok {
+			event.ServiceName = service.Name
+		}
+		if within(assignment.StartsAt) {
+			event.Time = *assignment.StartsAt
+			event.Kind = TimelineAssignmentStart
+			events = append(events, event)
+		}
+		if within(assignment.EndsAt) {
+			event.Time = *assignment.EndsAt
+			event.Kind = TimelineAssignmentEnd
+			events = append(events, event)
+		}
+	}
+	for _, reservation := range reservations {
+		if within(&reservation.ExpiresAt) {
+			events = append(events, TimelineEvent{
+				Time:        reservation.ExpiresAt,
+				Kind:        TimelineReservationExpiry,
+				ComputeID:   reservation.ComputeID,
+				ComputeName: computeNames[reservation.ComputeID],
+				Reservation: reservation.Name,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	// One snapshot at the start of the window and at each distinct event time
+	instants := []time.Time{from}
+	for _, event := range events {
+		if !event.Time.Equal(instants[len(instants)-1]) {
+			instants = append(instants, event.Time)
+		}
+	}
+
+	snapshots := make([]*CapacitySnapshot, 0, len(instants))
+	for _, at := range instants {
+		active := ActiveAssignments(assignments, at)
+		HoldReservations(computes, reservations, active, servicesMap, basis, at)
+		report := BuildCapacityReport(computes, services, active, basis)
+		snapshots = append(snapshots, NewCapacitySnapshot(report, at))
+	}
+
+	history := BuildCapacityHistory(snapshots, groupBy)
+
+	return &CapacityTimeline{
+		From:        from,
+		To:          to,
+		GroupBy:     history.GroupBy,
+		Series:      history.Series,
+		Events:      events,
+		Reservation: basis,
+	}
+}
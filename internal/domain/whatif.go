@@ -0,0 +1,234 @@
+package domain
+
+import "fmt"
+
+// WhatIf describes hypothetical changes to the fleet. Plans and reports run on a temporary
+// copy of the data with the changes applied; nothing is written to the database.
+type WhatIf struct {
+	Computes          []HypotheticalCompute `json:"computes,omitempty"`
+	Services          []*Service            `json:"services,omitempty"`           // Services without an ID use their name as ID
+	RemoveComputes    []string              `json:"remove_computes,omitempty"`    // Compute IDs or names to leave out
+	RemoveAssignments []string              `json:"remove_assignments,omitempty"` // Assignment IDs to leave out
+}
+
+// HypotheticalCompute is a compute that does not exist yet, described by its components
+// from the catalog or by explicit resources. A hosted VM or VPS sets parent_id and size instead.
+type HypotheticalCompute struct {
+	Compute
+	Count      int                `json:"count,omitempty"` // Number of identical computes to add (default 1)
+	Components []ComputeComponent `json:"components,omitempty"`
+	Resources  Resources          `json:"resources,omitempty"` // Used when no components are given
+}
+
+// IsEmpty checks if the scenario changes nothing
+func (w *WhatIf) IsEmpty() bool {
+	return w == nil || (len(w.Computes) == 0 && len(w.Services) == 0 &&
+		len(w.RemoveComputes) == 0 && len(w.RemoveAssignments) == 0)
+}
+
+// FindService returns the hypothetical service with the given ID or name
+func (w *WhatIf) FindService(ref string) *Service {
+	if w == nil {
+		return nil
+	}
+	for _, svc := range w.Services {
+		if svc != nil && (svc.ID == ref || svc.Name == ref) {
+			found := *svc
+			if found.ID == "" {
+				found.ID = found.Name
+			}
+			return &found
+		}
+	}
+	return nil
+}
+
+// Apply returns copies of computes, services and assignments with the hypothetical changes
+// applied. Assignments on removed computes are left out as well. Resources of hypothetical
+// computes are derived from the component catalog, derivation rules and overcommit policies
+// the same way as for real computes.
+func (w *WhatIf) Apply(computes []*Compute, services []*Service, assignments []*Assignment, components []*Component, rules []*DerivationRule, policies []*OvercommitPolicy) ([]*Compute, []*Service, []*Assignment, error) {
+	removedComputes := make(map[string]bool)
+	for _, ref := range w.RemoveComputes {
+		found := false
+		for _, compute := range computes {
+			if compute.ID == ref || compute.Name == ref {
+				removedComputes[compute.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, nil, nil, fmt.Errorf("compute to remove %s not found", ref)
+		}
+	}
+	// Computes hosted on a removed compute are removed with it
+	for _, compute := range computes {
+		if removedComputes[compute.ParentID] {
+			removedComputes[compute.ID] = true
+		}
+	}
+
+	removedAssignments := make(map[string]bool)
+	for _, id := range w.RemoveAssignments {
+		found := false
+		for _, assignment := range assignments {
+			if assignment.ID == id {
+				removedAssignments[id] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, nil, fmt.Errorf("assignment to remove %s not found", id)
+		}
+	}
+
+	// Computes are copied so the hosted resources can be rolled up for the scenario
+	nextComputes := make([]*Compute, 0, len(computes)+len(w.Computes))
+	for _, compute := range computes {
+		if !removedComputes[compute.ID] {
+			copied := *compute
+			nextComputes = append(nextComputes, &copied)
+		}
+	}
+
+	for i, hypothetical := range w.Computes {
+		added, err := hypothetical.materialize(i, components, rules, policies)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		nextComputes = append(nextComputes, added...)
+	}
+
+	// Hypothetical computes may name their parent
+	byRef := make(map[string]*Compute, 2*len(nextComputes))
+	for _, compute := range nextComputes {
+		byRef[compute.Name] = compute
+	}
+	for _, compute := range nextComputes {
+		byRef[compute.ID] = compute
+	}
+	for _, compute := range nextComputes {
+		if compute.ParentID == "" {
+			continue
+		}
+		parent, ok := byRef[compute.ParentID]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("parent %s of compute %s not found", compute.ParentID, compute.Name)
+		}
+		compute.ParentID = parent.ID
+		if err := compute.ValidateParent(parent); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	RollUpHosted(nextComputes)
+	InheritHardwareFacts(nextComputes)
+
+	nextServices := make([]*Service, 0, len(services)+len(w.Services))
+	nextServices = append(nextServices, services...)
+	for _, svc := range w.Services {
+		if svc == nil || svc.Name == "" {
+			return nil, nil, nil, fmt.Errorf("hypothetical service requires a name")
+		}
+		added := *svc
+		if added.ID == "" {
+			added.ID = added.Name
+		}
+		if err := added.Validate(); err != nil {
+			return nil, nil, nil, fmt.Errorf("hypothetical service %s: %w", added.Name, err)
+		}
+		nextServices = append(nextServices, &added)
+	}
+
+	nextAssignments := make([]*Assignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if removedAssignments[assignment.ID] || removedComputes[assignment.ComputeID] {
+			continue
+		}
+		nextAssignments = append(nextAssignments, assignment)
+	}
+
+	return nextComputes, nextServices, nextAssignments, nil
+}
+
+// materialize creates the computes described by a hypothetical compute
+func (h HypotheticalCompute) materialize(index int, components []*Component, rules []*DerivationRule, policies []*OvercommitPolicy) ([]*Compute, error) {
+	if h.Name == "" {
+		return nil, fmt.Errorf("hypothetical compute %d requires a name", index+1)
+	}
+
+	count := h.Count
+	if count <= 0 {
+		count = 1
+	}
+
+	computes := make([]*Compute, 0, count)
+	for n := 1; n <= count; n++ {
+		compute := h.Compute
+		if count > 1 {
+			compute.Name = fmt.Sprintf("%s-%d", h.Name, n)
+		}
+		if h.ID == "" || count > 1 {
+			compute.ID = "whatif-" + compute.Name
+		}
+		if compute.State == "" {
+			compute.State = ComputeStateActive
+		}
+
+		if len(h.Components) > 0 {
+			assignments := make([]*ComputeComponent, 0, len(h.Components))
+			for _, part := range h.Components {
+				found := false
+				for _, component := range components {
+					if component.ID == part.ComponentID || component.Name == part.ComponentID {
+						part.ComponentID = component.ID
+						found = true
+						break
+					}
+				}
+				if !found {
+					return nil, fmt.Errorf("hypothetical compute %s: component %s not found", compute.Name, part.ComponentID)
+				}
+				part.ComputeID = compute.ID
+				if part.Quantity <= 0 {
+					part.Quantity = 1
+				}
+				assigned := part
+				assignments = append(assignments, &assigned)
+			}
+			compute.Resources = compute.GetTotalResourcesFromComponents(components, assignments, rules)
+			compute.StorageGroups = compute.GetStorageGroupsFromComponents(components, assignments, rules)
+			compute.Facts = compute.GetHardwareFactsFromComponents(components, assignments)
+		} else {
+			compute.Resources = make(Resources)
+			for key, value := range h.Resources {
+				compute.Resources[key] = value
+			}
+		}
+		compute.ApplySize()
+		compute.ApplyOvercommit(policies)
+
+		computes = append(computes, &compute)
+	}
+
+	return computes, nil
+}
+
+// WithWhatIf returns a planner working on a copy of the data with the scenario applied
+func (cp *CapacityPlanner) WithWhatIf(whatIf *WhatIf) (*CapacityPlanner, error) {
+	computes, services, assignments, err := whatIf.Apply(cp.computes, cp.services, cp.assignments, cp.components, cp.rules, cp.policies)
+	if err != nil {
+		return nil, err
+	}
+
+	planner := NewCapacityPlanner(computes, services, assignments)
+	planner.SetComponents(cp.components)
+	planner.SetDerivationRules(cp.rules)
+	planner.SetOvercommitPolicies(cp.policies)
+	planner.SetReservation(cp.reservation)
+	if cp.held != nil {
+		// Hold again on the scenario: hypothetical computes can hold, removed ones no longer do
+		planner.HoldReservations(cp.held, cp.heldAt)
+	}
+	return planner, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type apikeyRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *apikeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
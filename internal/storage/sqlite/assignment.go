@@ -11,7 +11,7 @@ import (
 )
 
 type assignmentRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *assignmentRepo) Create(ctx context.Context, assignment *domain.Assignment) error {
@@ -23,11 +23,13 @@ func (r *assignmentRepo) Create(ctx context.Context, assignment *domain.Assignme
 		assignment.Quantity = 1
 	}
 
+	assignment.Schedule = assignment.Schedule.UTC()
+
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO assignments (id, service_id, compute_id, quantity, notes, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO assignments (id, service_id, compute_id, quantity, notes, starts_at, ends_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, assignment.ID, assignment.ServiceID, assignment.ComputeID, assignment.Quantity, assignment.Notes,
-	   assignment.CreatedAt, assignment.UpdatedAt)
+	   assignment.StartsAt, assignment.EndsAt, assignment.CreatedAt, assignment.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create assignment: %w", err)
@@ -40,11 +42,11 @@ func (r *assignmentRepo) Get(ctx context.Context, id string) (*domain.Assignment
 	var assignment domain.Assignment
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, service_id, compute_id, quantity, notes, created_at, updated_at
+		SELECT id, service_id, compute_id, quantity, notes, starts_at, ends_at, created_at, updated_at
 		FROM assignments
 		WHERE id = ?
 	`, id).Scan(&assignment.ID, &assignment.ServiceID, &assignment.ComputeID, &assignment.Quantity, &assignment.Notes,
-		&assignment.CreatedAt, &assignment.UpdatedAt)
+		&assignment.StartsAt, &assignment.EndsAt, &assignment.CreatedAt, &assignment.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("assignment not found")
@@ -56,15 +58,17 @@ func (r *assignmentRepo) Get(ctx context.Context, id string) (*domain.Assignment
 	return &assignment, nil
 }
 
-func (r *assignmentRepo) GetByComputeAndService(ctx context.Context, computeID, serviceID string) (*domain.Assignment, error) {
+// GetByComputeAndService returns the assignment of the service on the compute with the same schedule
+func (r *assignmentRepo) GetByComputeAndService(ctx context.Context, computeID, serviceID string, schedule domain.Schedule) (*domain.Assignment, error) {
 	var assignment domain.Assignment
+	schedule = schedule.UTC()
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, service_id, compute_id, quantity, notes, created_at, updated_at
+		SELECT id, service_id, compute_id, quantity, notes, starts_at, ends_at, created_at, updated_at
 		FROM assignments
-		WHERE compute_id = ? AND service_id = ?
-	`, computeID, serviceID).Scan(&assignment.ID, &assignment.ServiceID, &assignment.ComputeID, &assignment.Quantity, &assignment.Notes,
-		&assignment.CreatedAt, &assignment.UpdatedAt)
+		WHERE compute_id = ? AND service_id = ? AND starts_at IS ? AND ends_at IS ?
+	`, computeID, serviceID, schedule.StartsAt, schedule.EndsAt).Scan(&assignment.ID, &assignment.ServiceID, &assignment.ComputeID, &assignment.Quantity, &assignment.Notes,
+		&assignment.StartsAt, &assignment.EndsAt, &assignment.CreatedAt, &assignment.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // Return nil for upsert logic
@@ -78,7 +82,7 @@ func (r *assignmentRepo) GetByComputeAndService(ctx context.Context, computeID,
 
 func (r *assignmentRepo) List(ctx context.Context, filters storage.AssignmentFilters) ([]*domain.Assignment, error) {
 	query := `
-		SELECT id, service_id, compute_id, quantity, notes, created_at, updated_at
+		SELECT id, service_id, compute_id, quantity, notes, starts_at, ends_at, created_at, updated_at
 		FROM assignments
 		WHERE 1=1
 	`
@@ -106,7 +110,7 @@ func (r *assignmentRepo) List(ctx context.Context, filters storage.AssignmentFil
 		var assignment domain.Assignment
 
 		err := rows.Scan(&assignment.ID, &assignment.ServiceID, &assignment.ComputeID, &assignment.Quantity, &assignment.Notes,
-			&assignment.CreatedAt, &assignment.UpdatedAt)
+			&assignment.StartsAt, &assignment.EndsAt, &assignment.CreatedAt, &assignment.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan assignment: %w", err)
 		}
@@ -124,11 +128,13 @@ func (r *assignmentRepo) Update(ctx context.Context, assignment *domain.Assignme
 		assignment.Quantity = 1
 	}
 
+	assignment.Schedule = assignment.Schedule.UTC()
+
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE assignments
-		SET quantity = ?, notes = ?, updated_at = ?
+		SET quantity = ?, notes = ?, starts_at = ?, ends_at = ?, updated_at = ?
 		WHERE id = ?
-	`, assignment.Quantity, assignment.Notes, assignment.UpdatedAt, assignment.ID)
+	`, assignment.Quantity, assignment.Notes, assignment.StartsAt, assignment.EndsAt, assignment.UpdatedAt, assignment.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update assignment: %w", err)
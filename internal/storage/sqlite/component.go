@@ -11,7 +11,7 @@ import (
 )
 
 type componentRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *componentRepo) Create(ctx context.Context, component *domain.Component) error {
@@ -12,7 +12,7 @@ import (
 )
 
 type computeRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *computeRepo) Create(ctx context.Context, compute *domain.Compute) error {
@@ -21,17 +21,28 @@ func (r *computeRepo) Create(ctx context.Context, compute *domain.Compute) error
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
+	overcommitJSON, err := marshalOvercommit(compute.Overcommit)
+	if err != nil {
+		return err
+	}
+
+	sizeJSON, err := marshalSize(compute.Size)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 	compute.CreatedAt = now
 	compute.UpdatedAt = now
 
 	_, err = r.db.ExecContext(ctx, `
 		INSERT INTO computes (id, name, type, provider, region, tags, state, created_at, updated_at,
-			monthly_cost, annual_cost, contract_end_date, next_renewal_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			monthly_cost, annual_cost, contract_end_date, next_renewal_date, overcommit, parent_id, size)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, compute.ID, compute.Name, compute.Type, compute.Provider, compute.Region,
 	   string(tagsJSON), compute.State, compute.CreatedAt, compute.UpdatedAt,
-	   compute.MonthlyCost, compute.AnnualCost, compute.ContractEndDate, compute.NextRenewalDate)
+	   compute.MonthlyCost, compute.AnnualCost, compute.ContractEndDate, compute.NextRenewalDate,
+	   overcommitJSON, nullString(compute.ParentID), sizeJSON)
 
 	if err != nil {
 		return fmt.Errorf("failed to create compute: %w", err)
@@ -43,15 +54,17 @@ func (r *computeRepo) Create(ctx context.Context, compute *domain.Compute) error
 func (r *computeRepo) Get(ctx context.Context, id string) (*domain.Compute, error) {
 	var compute domain.Compute
 	var tagsJSON string
+	var overcommitJSON, parentID, sizeJSON sql.NullString
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, name, type, provider, region, tags, state, created_at, updated_at,
-			monthly_cost, annual_cost, contract_end_date, next_renewal_date
+			monthly_cost, annual_cost, contract_end_date, next_renewal_date, overcommit, parent_id, size
 		FROM computes
 		WHERE id = ?
 	`, id).Scan(&compute.ID, &compute.Name, &compute.Type, &compute.Provider, &compute.Region,
 		&tagsJSON, &compute.State, &compute.CreatedAt, &compute.UpdatedAt,
-		&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate)
+		&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate, &overcommitJSON,
+		&parentID, &sizeJSON)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("compute not found")
@@ -64,21 +77,31 @@ func (r *computeRepo) Get(ctx context.Context, id string) (*domain.Compute, erro
 		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
 	}
 
+	if err := unmarshalOvercommit(overcommitJSON, &compute); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalHierarchy(parentID, sizeJSON, &compute); err != nil {
+		return nil, err
+	}
+
 	return &compute, nil
 }
 
 func (r *computeRepo) GetByNameProviderRegionType(ctx context.Context, name, provider, region, computeType string) (*domain.Compute, error) {
 	var compute domain.Compute
 	var tagsJSON string
+	var overcommitJSON, parentID, sizeJSON sql.NullString
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT id, name, type, provider, region, tags, state, created_at, updated_at,
-			monthly_cost, annual_cost, contract_end_date, next_renewal_date
+			monthly_cost, annual_cost, contract_end_date, next_renewal_date, overcommit, parent_id, size
 		FROM computes
 		WHERE name = ? AND provider = ? AND region = ? AND type = ?
 	`, name, provider, region, computeType).Scan(&compute.ID, &compute.Name, &compute.Type, &compute.Provider, &compute.Region,
 		&tagsJSON, &compute.State, &compute.CreatedAt, &compute.UpdatedAt,
-		&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate)
+		&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate, &overcommitJSON,
+		&parentID, &sizeJSON)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // Return nil if not found (not an error for upsert logic)
@@ -91,13 +114,21 @@ func (r *computeRepo) GetByNameProviderRegionType(ctx context.Context, name, pro
 		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
 	}
 
+	if err := unmarshalOvercommit(overcommitJSON, &compute); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalHierarchy(parentID, sizeJSON, &compute); err != nil {
+		return nil, err
+	}
+
 	return &compute, nil
 }
 
 func (r *computeRepo) List(ctx context.Context, filters storage.ComputeFilters) ([]*domain.Compute, error) {
 	query := `
 		SELECT id, name, type, provider, region, tags, state, created_at, updated_at,
-			monthly_cost, annual_cost, contract_end_date, next_renewal_date
+			monthly_cost, annual_cost, contract_end_date, next_renewal_date, overcommit, parent_id, size
 		FROM computes
 		WHERE 1=1
 	`
@@ -119,6 +150,10 @@ func (r *computeRepo) List(ctx context.Context, filters storage.ComputeFilters)
 		query += " AND state = ?"
 		args = append(args, filters.State)
 	}
+	if filters.ParentID != "" {
+		query += " AND parent_id = ?"
+		args = append(args, filters.ParentID)
+	}
 
 	query += " ORDER BY created_at DESC"
 
@@ -132,10 +167,12 @@ func (r *computeRepo) List(ctx context.Context, filters storage.ComputeFilters)
 	for rows.Next() {
 		var compute domain.Compute
 		var tagsJSON string
+		var overcommitJSON, parentID, sizeJSON sql.NullString
 
 		err := rows.Scan(&compute.ID, &compute.Name, &compute.Type, &compute.Provider, &compute.Region,
 			&tagsJSON, &compute.State, &compute.CreatedAt, &compute.UpdatedAt,
-			&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate)
+			&compute.MonthlyCost, &compute.AnnualCost, &compute.ContractEndDate, &compute.NextRenewalDate, &overcommitJSON,
+		&parentID, &sizeJSON)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan compute: %w", err)
 		}
@@ -144,6 +181,14 @@ func (r *computeRepo) List(ctx context.Context, filters storage.ComputeFilters)
 			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
 		}
 
+		if err := unmarshalOvercommit(overcommitJSON, &compute); err != nil {
+			return nil, err
+		}
+
+		if err := unmarshalHierarchy(parentID, sizeJSON, &compute); err != nil {
+			return nil, err
+		}
+
 		// Apply tag filters (post-query since tags are JSON)
 		if len(filters.Tags) > 0 {
 			match := true
@@ -170,17 +215,28 @@ func (r *computeRepo) Update(ctx context.Context, compute *domain.Compute) error
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
+	overcommitJSON, err := marshalOvercommit(compute.Overcommit)
+	if err != nil {
+		return err
+	}
+
+	sizeJSON, err := marshalSize(compute.Size)
+	if err != nil {
+		return err
+	}
+
 	compute.UpdatedAt = time.Now()
 
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE computes
 		SET name = ?, type = ?, provider = ?, region = ?, tags = ?, state = ?, updated_at = ?,
-			monthly_cost = ?, annual_cost = ?, contract_end_date = ?, next_renewal_date = ?
+			monthly_cost = ?, annual_cost = ?, contract_end_date = ?, next_renewal_date = ?, overcommit = ?,
+			parent_id = ?, size = ?
 		WHERE id = ?
 	`, compute.Name, compute.Type, compute.Provider, compute.Region,
 	   string(tagsJSON), compute.State, compute.UpdatedAt,
 	   compute.MonthlyCost, compute.AnnualCost, compute.ContractEndDate, compute.NextRenewalDate,
-	   compute.ID)
+	   overcommitJSON, nullString(compute.ParentID), sizeJSON, compute.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update compute: %w", err)
@@ -215,3 +271,58 @@ func (r *computeRepo) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// marshalOvercommit encodes overcommit ratios, storing NULL when there are none
+func marshalOvercommit(ratios map[string]float64) (interface{}, error) {
+	if len(ratios) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(ratios)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal overcommit: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalOvercommit decodes the overcommit ratios of a compute row
+func unmarshalOvercommit(data sql.NullString, compute *domain.Compute) error {
+	if !data.Valid || data.String == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data.String), &compute.Overcommit); err != nil {
+		return fmt.Errorf("failed to unmarshal overcommit: %w", err)
+	}
+	return nil
+}
+
+// marshalSize encodes the size of a VM or VPS, storing NULL when there is none
+func marshalSize(size domain.Resources) (interface{}, error) {
+	if len(size) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal size: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalHierarchy decodes the parent and size of a compute row
+func unmarshalHierarchy(parentID, size sql.NullString, compute *domain.Compute) error {
+	compute.ParentID = parentID.String
+	if !size.Valid || size.String == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(size.String), &compute.Size); err != nil {
+		return fmt.Errorf("failed to unmarshal size: %w", err)
+	}
+	return nil
+}
+
+// nullString stores an empty string as NULL
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
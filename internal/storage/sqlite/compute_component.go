@@ -9,13 +9,13 @@ import (
 )
 
 type computeComponentRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *computeComponentRepo) Assign(ctx context.Context, assignment *domain.ComputeComponent) error {
 	query := `
-		INSERT INTO compute_components (id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO compute_components (id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, raid_role, raid_spans, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -28,6 +28,8 @@ func (r *computeComponentRepo) Assign(ctx context.Context, assignment *domain.Co
 		assignment.Notes,
 		assignment.RaidLevel,
 		assignment.RaidGroup,
+		assignment.RaidRole,
+		assignment.RaidSpans,
 		assignment.CreatedAt,
 	)
 
@@ -38,6 +40,38 @@ func (r *computeComponentRepo) Assign(ctx context.Context, assignment *domain.Co
 	return nil
 }
 
+func (r *computeComponentRepo) Get(ctx context.Context, id string) (*domain.ComputeComponent, error) {
+	query := `
+		SELECT id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, raid_role, raid_spans, created_at
+		FROM compute_components
+		WHERE id = ?
+	`
+
+	var assignment domain.ComputeComponent
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&assignment.ID,
+		&assignment.ComputeID,
+		&assignment.ComponentID,
+		&assignment.Quantity,
+		&assignment.Slot,
+		&assignment.SerialNo,
+		&assignment.Notes,
+		&assignment.RaidLevel,
+		&assignment.RaidGroup,
+		&assignment.RaidRole,
+		&assignment.RaidSpans,
+		&assignment.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("assignment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compute component: %w", err)
+	}
+
+	return &assignment, nil
+}
+
 func (r *computeComponentRepo) Unassign(ctx context.Context, id string) error {
 	query := "DELETE FROM compute_components WHERE id = ?"
 
@@ -60,7 +94,7 @@ func (r *computeComponentRepo) Unassign(ctx context.Context, id string) error {
 
 func (r *computeComponentRepo) ListByCompute(ctx context.Context, computeID string) ([]*domain.ComputeComponent, error) {
 	query := `
-		SELECT id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, created_at
+		SELECT id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, raid_role, raid_spans, created_at
 		FROM compute_components
 		WHERE compute_id = ?
 		ORDER BY created_at
@@ -86,6 +120,8 @@ func (r *computeComponentRepo) ListByCompute(ctx context.Context, computeID stri
 			&assignment.Notes,
 			&assignment.RaidLevel,
 			&assignment.RaidGroup,
+			&assignment.RaidRole,
+			&assignment.RaidSpans,
 			&assignment.CreatedAt,
 		)
 		if err != nil {
@@ -100,7 +136,7 @@ func (r *computeComponentRepo) ListByCompute(ctx context.Context, computeID stri
 
 func (r *computeComponentRepo) ListByComponent(ctx context.Context, componentID string) ([]*domain.ComputeComponent, error) {
 	query := `
-		SELECT id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, created_at
+		SELECT id, compute_id, component_id, quantity, slot, serial_no, notes, raid_level, raid_group, raid_role, raid_spans, created_at
 		FROM compute_components
 		WHERE component_id = ?
 		ORDER BY created_at
@@ -126,6 +162,8 @@ func (r *computeComponentRepo) ListByComponent(ctx context.Context, componentID
 			&assignment.Notes,
 			&assignment.RaidLevel,
 			&assignment.RaidGroup,
+			&assignment.RaidRole,
+			&assignment.RaidSpans,
 			&assignment.CreatedAt,
 		)
 		if err != nil {
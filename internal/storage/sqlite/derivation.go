@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+type derivationRuleRepo struct {
+	db dbtx
+}
+
+const derivationRuleColumns = "id, name, component_type, spec_fields, unit, resource, aggregation, priority, description, created_at, updated_at"
+
+func (r *derivationRuleRepo) Create(ctx context.Context, rule *domain.DerivationRule) error {
+	specFieldsJSON, err := marshalSpecFields(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO derivation_rules (` + derivationRuleColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.ComponentType,
+		specFieldsJSON,
+		rule.Unit,
+		rule.Resource,
+		rule.Aggregation,
+		rule.Priority,
+		rule.Description,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create derivation rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *derivationRuleRepo) Get(ctx context.Context, id string) (*domain.DerivationRule, error) {
+	query := "SELECT " + derivationRuleColumns + " FROM derivation_rules WHERE id = ?"
+
+	rule, err := scanDerivationRule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("derivation rule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get derivation rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *derivationRuleRepo) GetByName(ctx context.Context, name string) (*domain.DerivationRule, error) {
+	query := "SELECT " + derivationRuleColumns + " FROM derivation_rules WHERE name = ?"
+
+	rule, err := scanDerivationRule(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil if not found (not an error for upsert logic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get derivation rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *derivationRuleRepo) List(ctx context.Context) ([]*domain.DerivationRule, error) {
+	query := "SELECT " + derivationRuleColumns + " FROM derivation_rules ORDER BY component_type, priority, name"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list derivation rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.DerivationRule, 0)
+	for rows.Next() {
+		rule, err := scanDerivationRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan derivation rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *derivationRuleRepo) Update(ctx context.Context, rule *domain.DerivationRule) error {
+	specFieldsJSON, err := marshalSpecFields(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE derivation_rules
+		SET name = ?, component_type = ?, spec_fields = ?, unit = ?, resource = ?, aggregation = ?,
+			priority = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rule.Name,
+		rule.ComponentType,
+		specFieldsJSON,
+		rule.Unit,
+		rule.Resource,
+		rule.Aggregation,
+		rule.Priority,
+		rule.Description,
+		rule.UpdatedAt,
+		rule.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update derivation rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("derivation rule not found")
+	}
+
+	return nil
+}
+
+func (r *derivationRuleRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM derivation_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete derivation rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("derivation rule not found")
+	}
+
+	return nil
+}
+
+// marshalSpecFields encodes the spec fields of a rule
+func marshalSpecFields(rule *domain.DerivationRule) (string, error) {
+	fields := rule.SpecFields
+	if fields == nil {
+		fields = make([]string, 0)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec_fields: %w", err)
+	}
+	return string(data), nil
+}
+
+// scanDerivationRule reads one rule row
+func scanDerivationRule(row rowScanner) (*domain.DerivationRule, error) {
+	var rule domain.DerivationRule
+	var specFieldsJSON string
+	var unit, description sql.NullString
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.ComponentType,
+		&specFieldsJSON,
+		&unit,
+		&rule.Resource,
+		&rule.Aggregation,
+		&rule.Priority,
+		&description,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rule.Unit = unit.String
+	rule.Description = description.String
+
+	if err := json.Unmarshal([]byte(specFieldsJSON), &rule.SpecFields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec_fields: %w", err)
+	}
+
+	return &rule, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type dnsRecordRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *dnsRecordRepo) Create(ctx context.Context, record *domain.DNSRecord) error {
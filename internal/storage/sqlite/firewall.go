@@ -10,7 +10,7 @@ import (
 )
 
 type firewallRuleRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *firewallRuleRepo) Create(ctx context.Context, rule *domain.FirewallRule) error {
@@ -263,7 +263,7 @@ func (r *firewallRuleRepo) Delete(ctx context.Context, id string) error {
 }
 
 type computeFirewallRuleRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *computeFirewallRuleRepo) Assign(ctx context.Context, assignment *domain.ComputeFirewallRule) error {
@@ -12,7 +12,7 @@ import (
 )
 
 type ipAddressRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *ipAddressRepo) Create(ctx context.Context, ip *domain.IPAddress) error {
@@ -259,7 +259,7 @@ func (r *ipAddressRepo) Delete(ctx context.Context, id string) error {
 }
 
 type computeIPRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *computeIPRepo) Assign(ctx context.Context, assignment *domain.ComputeIP) error {
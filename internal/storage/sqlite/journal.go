@@ -11,7 +11,7 @@ import (
 )
 
 type journalRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *journalRepo) Create(ctx context.Context, entry *domain.JournalEntry) error {
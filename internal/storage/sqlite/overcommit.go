@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+)
+
+type overcommitPolicyRepo struct {
+	db dbtx
+}
+
+const overcommitPolicyColumns = "id, name, tags, ratios, priority, description, created_at, updated_at"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *overcommitPolicyRepo) Create(ctx context.Context, policy *domain.OvercommitPolicy) error {
+	tagsJSON, ratiosJSON, err := marshalOvercommitPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO overcommit_policies (` + overcommitPolicyColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		policy.ID,
+		policy.Name,
+		tagsJSON,
+		ratiosJSON,
+		policy.Priority,
+		policy.Description,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create overcommit policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *overcommitPolicyRepo) Get(ctx context.Context, id string) (*domain.OvercommitPolicy, error) {
+	query := "SELECT " + overcommitPolicyColumns + " FROM overcommit_policies WHERE id = ?"
+
+	policy, err := scanOvercommitPolicy(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("overcommit policy not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overcommit policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (r *overcommitPolicyRepo) GetByName(ctx context.Context, name string) (*domain.OvercommitPolicy, error) {
+	query := "SELECT " + overcommitPolicyColumns + " FROM overcommit_policies WHERE name = ?"
+
+	policy, err := scanOvercommitPolicy(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil if not found (not an error for upsert logic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overcommit policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (r *overcommitPolicyRepo) List(ctx context.Context) ([]*domain.OvercommitPolicy, error) {
+	query := "SELECT " + overcommitPolicyColumns + " FROM overcommit_policies ORDER BY priority, name"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overcommit policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*domain.OvercommitPolicy, 0)
+	for rows.Next() {
+		policy, err := scanOvercommitPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan overcommit policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func (r *overcommitPolicyRepo) Update(ctx context.Context, policy *domain.OvercommitPolicy) error {
+	tagsJSON, ratiosJSON, err := marshalOvercommitPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE overcommit_policies
+		SET name = ?, tags = ?, ratios = ?, priority = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		policy.Name,
+		tagsJSON,
+		ratiosJSON,
+		policy.Priority,
+		policy.Description,
+		policy.UpdatedAt,
+		policy.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update overcommit policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("overcommit policy not found")
+	}
+
+	return nil
+}
+
+func (r *overcommitPolicyRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM overcommit_policies WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete overcommit policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("overcommit policy not found")
+	}
+
+	return nil
+}
+
+// marshalOvercommitPolicy encodes the tags and ratios of a policy
+func marshalOvercommitPolicy(policy *domain.OvercommitPolicy) (string, string, error) {
+	tags := policy.Tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	ratiosJSON, err := json.Marshal(policy.Ratios)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal ratios: %w", err)
+	}
+
+	return string(tagsJSON), string(ratiosJSON), nil
+}
+
+// scanOvercommitPolicy reads one policy row
+func scanOvercommitPolicy(row rowScanner) (*domain.OvercommitPolicy, error) {
+	var policy domain.OvercommitPolicy
+	var tagsJSON, ratiosJSON string
+	var description sql.NullString
+
+	err := row.Scan(
+		&policy.ID,
+		&policy.Name,
+		&tagsJSON,
+		&ratiosJSON,
+		&policy.Priority,
+		&description,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	policy.Description = description.String
+
+	if err := json.Unmarshal([]byte(tagsJSON), &policy.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := json.Unmarshal([]byte(ratiosJSON), &policy.Ratios); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ratios: %w", err)
+	}
+
+	return &policy, nil
+}
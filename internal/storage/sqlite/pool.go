@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+type poolRepo struct {
+	db dbtx
+}
+
+const poolColumns = "id, name, compute_id, type, raid_groups, description, created_at, updated_at"
+
+func (r *poolRepo) Create(ctx context.Context, pool *domain.Pool) error {
+	groupsJSON, err := json.Marshal(pool.RaidGroups)
+	if err != nil {
+		return fmt.Errorf("failed to marshal raid groups: %w", err)
+	}
+
+	query := `
+		INSERT INTO storage_pools (` + poolColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		pool.ID,
+		pool.Name,
+		pool.ComputeID,
+		pool.Type,
+		string(groupsJSON),
+		pool.Description,
+		pool.CreatedAt,
+		pool.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create pool: %w", err)
+	}
+
+	return nil
+}
+
+func (r *poolRepo) Get(ctx context.Context, id string) (*domain.Pool, error) {
+	query := "SELECT " + poolColumns + " FROM storage_pools WHERE id = ?"
+
+	pool, err := scanPool(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pool not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+
+	return pool, nil
+}
+
+func (r *poolRepo) GetByName(ctx context.Context, computeID, name string) (*domain.Pool, error) {
+	query := "SELECT " + poolColumns + " FROM storage_pools WHERE compute_id = ? AND name = ?"
+
+	pool, err := scanPool(r.db.QueryRowContext(ctx, query, computeID, name))
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil if not found (not an error for upsert logic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+
+	return pool, nil
+}
+
+func (r *poolRepo) List(ctx context.Context, filters storage.PoolFilters) ([]*domain.Pool, error) {
+	query := "SELECT " + poolColumns + " FROM storage_pools WHERE 1=1"
+	args := make([]interface{}, 0)
+
+	if filters.ComputeID != "" {
+		query += " AND compute_id = ?"
+		args = append(args, filters.ComputeID)
+	}
+
+	query += " ORDER BY compute_id, name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pools: %w", err)
+	}
+	defer rows.Close()
+
+	pools := make([]*domain.Pool, 0)
+	for rows.Next() {
+		pool, err := scanPool(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pool: %w", err)
+		}
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+func (r *poolRepo) Update(ctx context.Context, pool *domain.Pool) error {
+	groupsJSON, err := json.Marshal(pool.RaidGroups)
+	if err != nil {
+		return fmt.Errorf("failed to marshal raid groups: %w", err)
+	}
+
+	query := `
+		UPDATE storage_pools
+		SET name = ?, compute_id = ?, type = ?, raid_groups = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		pool.Name,
+		pool.ComputeID,
+		pool.Type,
+		string(groupsJSON),
+		pool.Description,
+		pool.UpdatedAt,
+		pool.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update pool: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("pool not found")
+	}
+
+	return nil
+}
+
+func (r *poolRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM storage_pools WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pool: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("pool not found")
+	}
+
+	return nil
+}
+
+// scanPool reads one storage pool row
+func scanPool(row rowScanner) (*domain.Pool, error) {
+	var pool domain.Pool
+	var groupsJSON string
+	var description sql.NullString
+
+	err := row.Scan(
+		&pool.ID,
+		&pool.Name,
+		&pool.ComputeID,
+		&pool.Type,
+		&groupsJSON,
+		&description,
+		&pool.CreatedAt,
+		&pool.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pool.Description = description.String
+
+	if err := json.Unmarshal([]byte(groupsJSON), &pool.RaidGroups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal raid groups: %w", err)
+	}
+
+	return &pool, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type portAssignmentRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *portAssignmentRepo) Create(ctx context.Context, assignment *domain.PortAssignment) error {
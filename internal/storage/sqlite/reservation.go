@@ -0,0 +1,225 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+type reservationRepo struct {
+	db dbtx
+}
+
+const reservationColumns = "id, name, owner, resources, compute_id, selector, starts_at, expires_at, description, created_at, updated_at"
+
+func (r *reservationRepo) Create(ctx context.Context, reservation *domain.Reservation) error {
+	resourcesJSON, selectorJSON, err := marshalReservation(reservation)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO reservations (` + reservationColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		reservation.ID,
+		reservation.Name,
+		reservation.Owner,
+		resourcesJSON,
+		nullString(reservation.ComputeID),
+		selectorJSON,
+		reservation.StartsAt,
+		reservation.ExpiresAt,
+		reservation.Description,
+		reservation.CreatedAt,
+		reservation.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reservationRepo) Get(ctx context.Context, id string) (*domain.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE id = ?"
+
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reservation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+func (r *reservationRepo) GetByName(ctx context.Context, name string) (*domain.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE name = ?"
+
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil if not found (not an error for upsert logic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+func (r *reservationRepo) List(ctx context.Context, filters storage.ReservationFilters) ([]*domain.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE 1=1"
+	args := make([]interface{}, 0)
+
+	if filters.Owner != "" {
+		query += " AND owner = ?"
+		args = append(args, filters.Owner)
+	}
+	if filters.ComputeID != "" {
+		query += " AND compute_id = ?"
+		args = append(args, filters.ComputeID)
+	}
+
+	query += " ORDER BY expires_at, name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := make([]*domain.Reservation, 0)
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, nil
+}
+
+func (r *reservationRepo) Update(ctx context.Context, reservation *domain.Reservation) error {
+	resourcesJSON, selectorJSON, err := marshalReservation(reservation)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE reservations
+		SET name = ?, owner = ?, resources = ?, compute_id = ?, selector = ?, starts_at = ?, expires_at = ?,
+			description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		reservation.Name,
+		reservation.Owner,
+		resourcesJSON,
+		nullString(reservation.ComputeID),
+		selectorJSON,
+		reservation.StartsAt,
+		reservation.ExpiresAt,
+		reservation.Description,
+		reservation.UpdatedAt,
+		reservation.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reservation not found")
+	}
+
+	return nil
+}
+
+func (r *reservationRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM reservations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reservation not found")
+	}
+
+	return nil
+}
+
+// marshalReservation encodes the resources and selector of a reservation, storing NULL when
+// there is no selector
+func marshalReservation(reservation *domain.Reservation) (string, interface{}, error) {
+	resources, err := json.Marshal(reservation.Resources)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal resources: %w", err)
+	}
+	if reservation.Selector == nil {
+		return string(resources), nil, nil
+	}
+	selector, err := json.Marshal(reservation.Selector)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal selector: %w", err)
+	}
+	return string(resources), string(selector), nil
+}
+
+// scanReservation reads one reservation row
+func scanReservation(row rowScanner) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	var resourcesJSON string
+	var computeID, selectorJSON, description sql.NullString
+
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.Name,
+		&reservation.Owner,
+		&resourcesJSON,
+		&computeID,
+		&selectorJSON,
+		&reservation.StartsAt,
+		&reservation.ExpiresAt,
+		&description,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	reservation.ComputeID = computeID.String
+	reservation.Description = description.String
+
+	if err := json.Unmarshal([]byte(resourcesJSON), &reservation.Resources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resources: %w", err)
+	}
+	if selectorJSON.Valid && selectorJSON.String != "" {
+		reservation.Selector = &domain.ReservationSelector{}
+		if err := json.Unmarshal([]byte(selectorJSON.String), reservation.Selector); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal selector: %w", err)
+		}
+	}
+
+	return &reservation, nil
+}
@@ -48,10 +48,10 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 				"type": "baremetal",
 			},
 			Resources: domain.Resources{
-				"cpu":            32,
-				"ram_gb":         128,
-				"nvme_gb":        2000,
-				"bandwidth_mbps": 10000,
+				"cores":          32,
+				"memory":         131072,
+				"nvme":           2000,
+				"bandwidth_gbps": 10,
 			},
 			State: domain.ComputeStateActive,
 		},
@@ -67,10 +67,10 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 				"type": "vps",
 			},
 			Resources: domain.Resources{
-				"cpu":            8,
-				"ram_gb":         16,
-				"ssd_gb":         200,
-				"bandwidth_mbps": 1000,
+				"cores":          8,
+				"memory":         16384,
+				"ssd":            200,
+				"bandwidth_gbps": 1,
 			},
 			State: domain.ComputeStateActive,
 		},
@@ -87,10 +87,10 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 				"gpu":  "nvidia-t4",
 			},
 			Resources: domain.Resources{
-				"cpu":            4,
-				"ram_gb":         8,
-				"ssd_gb":         100,
-				"bandwidth_mbps": 500,
+				"cores":          4,
+				"memory":         8192,
+				"ssd":            100,
+				"bandwidth_gbps": 0.5,
 				"gpu":            1,
 			},
 			State: domain.ComputeStateActive,
@@ -109,12 +109,12 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 			ID:   uuid.New().String(),
 			Name: "nginx-ingress",
 			MinSpec: domain.Resources{
-				"cpu":    2,
-				"ram_gb": 4,
+				"cores":  2,
+				"memory": 4096,
 			},
 			MaxSpec: domain.Resources{
-				"cpu":    4,
-				"ram_gb": 8,
+				"cores":  4,
+				"memory": 8192,
 			},
 			Placement: domain.PlacementRules{
 				Affinity: []domain.TagSelector{
@@ -131,14 +131,14 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 			ID:   uuid.New().String(),
 			Name: "postgres-db",
 			MinSpec: domain.Resources{
-				"cpu":       4,
-				"ram_gb":    16,
-				"nvme_gb":   100,
+				"cores":  4,
+				"memory": 16384,
+				"nvme":   100,
 			},
 			MaxSpec: domain.Resources{
-				"cpu":       8,
-				"ram_gb":    32,
-				"nvme_gb":   500,
+				"cores":  8,
+				"memory": 32768,
+				"nvme":   500,
 			},
 			Placement: domain.PlacementRules{
 				AntiAffinity: []domain.TagSelector{
@@ -155,13 +155,13 @@ func (s *SQLiteStorage) Seed(ctx context.Context) error {
 			ID:   uuid.New().String(),
 			Name: "ml-worker",
 			MinSpec: domain.Resources{
-				"cpu":    2,
-				"ram_gb": 8,
+				"cores":  2,
+				"memory": 8192,
 				"gpu":    1,
 			},
 			MaxSpec: domain.Resources{
-				"cpu":    4,
-				"ram_gb": 16,
+				"cores":  4,
+				"memory": 16384,
 				"gpu":    1,
 			},
 			Placement: domain.PlacementRules{
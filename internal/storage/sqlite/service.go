@@ -11,7 +11,7 @@ import (
 )
 
 type serviceRepo struct {
-	db *sql.DB
+	db dbtx
 }
 
 func (r *serviceRepo) Create(ctx context.Context, service *domain.Service) error {
@@ -30,15 +30,20 @@ func (r *serviceRepo) Create(ctx context.Context, service *domain.Service) error
 		return fmt.Errorf("failed to marshal placement: %w", err)
 	}
 
+	storageJSON, err := json.Marshal(service.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal storage: %w", err)
+	}
+
 	now := time.Now()
 	service.CreatedAt = now
 	service.UpdatedAt = now
 
 	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO services (id, name, min_spec, max_spec, placement, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO services (id, name, min_spec, max_spec, placement, storage, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`, service.ID, service.Name, string(minSpecJSON), string(maxSpecJSON),
-	   string(placementJSON), service.CreatedAt, service.UpdatedAt)
+	   string(placementJSON), string(storageJSON), service.CreatedAt, service.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create service: %w", err)
@@ -50,13 +55,14 @@ func (r *serviceRepo) Create(ctx context.Context, service *domain.Service) error
 func (r *serviceRepo) Get(ctx context.Context, id string) (*domain.Service, error) {
 	var service domain.Service
 	var minSpecJSON, maxSpecJSON, placementJSON string
+	var storageJSON sql.NullString
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, name, min_spec, max_spec, placement, created_at, updated_at
+		SELECT id, name, min_spec, max_spec, placement, storage, created_at, updated_at
 		FROM services
 		WHERE id = ?
 	`, id).Scan(&service.ID, &service.Name, &minSpecJSON, &maxSpecJSON,
-		&placementJSON, &service.CreatedAt, &service.UpdatedAt)
+		&placementJSON, &storageJSON, &service.CreatedAt, &service.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("service not found")
@@ -77,19 +83,26 @@ func (r *serviceRepo) Get(ctx context.Context, id string) (*domain.Service, erro
 		return nil, fmt.Errorf("failed to unmarshal placement: %w", err)
 	}
 
+	if storageJSON.Valid && storageJSON.String != "" {
+		if err := json.Unmarshal([]byte(storageJSON.String), &service.Storage); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal storage: %w", err)
+		}
+	}
+
 	return &service, nil
 }
 
 func (r *serviceRepo) GetByName(ctx context.Context, name string) (*domain.Service, error) {
 	var service domain.Service
 	var minSpecJSON, maxSpecJSON, placementJSON string
+	var storageJSON sql.NullString
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, name, min_spec, max_spec, placement, created_at, updated_at
+		SELECT id, name, min_spec, max_spec, placement, storage, created_at, updated_at
 		FROM services
 		WHERE name = ?
 	`, name).Scan(&service.ID, &service.Name, &minSpecJSON, &maxSpecJSON,
-		&placementJSON, &service.CreatedAt, &service.UpdatedAt)
+		&placementJSON, &storageJSON, &service.CreatedAt, &service.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // Return nil for upsert logic
@@ -110,12 +123,18 @@ func (r *serviceRepo) GetByName(ctx context.Context, name string) (*domain.Servi
 		return nil, fmt.Errorf("failed to unmarshal placement: %w", err)
 	}
 
+	if storageJSON.Valid && storageJSON.String != "" {
+		if err := json.Unmarshal([]byte(storageJSON.String), &service.Storage); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal storage: %w", err)
+		}
+	}
+
 	return &service, nil
 }
 
 func (r *serviceRepo) List(ctx context.Context) ([]*domain.Service, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, name, min_spec, max_spec, placement, created_at, updated_at
+		SELECT id, name, min_spec, max_spec, placement, storage, created_at, updated_at
 		FROM services
 		ORDER BY created_at DESC
 	`)
@@ -128,9 +147,10 @@ func (r *serviceRepo) List(ctx context.Context) ([]*domain.Service, error) {
 	for rows.Next() {
 		var service domain.Service
 		var minSpecJSON, maxSpecJSON, placementJSON string
+		var storageJSON sql.NullString
 
 		err := rows.Scan(&service.ID, &service.Name, &minSpecJSON, &maxSpecJSON,
-			&placementJSON, &service.CreatedAt, &service.UpdatedAt)
+			&placementJSON, &storageJSON, &service.CreatedAt, &service.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
 		}
@@ -147,6 +167,12 @@ func (r *serviceRepo) List(ctx context.Context) ([]*domain.Service, error) {
 			return nil, fmt.Errorf("failed to unmarshal placement: %w", err)
 		}
 
+		if storageJSON.Valid && storageJSON.String != "" {
+			if err := json.Unmarshal([]byte(storageJSON.String), &service.Storage); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal storage: %w", err)
+			}
+		}
+
 		services = append(services, &service)
 	}
 
@@ -169,14 +195,19 @@ func (r *serviceRepo) Update(ctx context.Context, service *domain.Service) error
 		return fmt.Errorf("failed to marshal placement: %w", err)
 	}
 
+	storageJSON, err := json.Marshal(service.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal storage: %w", err)
+	}
+
 	service.UpdatedAt = time.Now()
 
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE services
-		SET name = ?, min_spec = ?, max_spec = ?, placement = ?, updated_at = ?
+		SET name = ?, min_spec = ?, max_spec = ?, placement = ?, storage = ?, updated_at = ?
 		WHERE id = ?
 	`, service.Name, string(minSpecJSON), string(maxSpecJSON),
-	   string(placementJSON), service.UpdatedAt, service.ID)
+	   string(placementJSON), string(storageJSON), service.UpdatedAt, service.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update service: %w", err)
@@ -0,0 +1,253 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+type snapshotRepo struct {
+	db dbtx
+}
+
+const sampleColumns = "compute_id, compute_name, type, parent_id, provider, region, tags, state, total, allocated, available, utilization_pct"
+
+// Create inserts the snapshot and its samples; run it in a transaction to keep them together
+func (r *snapshotRepo) Create(ctx context.Context, snapshot *domain.CapacitySnapshot) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO capacity_snapshots (id, taken_at, reservation, computes) VALUES (?, ?, ?, ?)",
+		snapshot.ID,
+		snapshot.TakenAt.UTC(),
+		string(snapshot.Reservation),
+		len(snapshot.Samples),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	query := "INSERT INTO capacity_samples (snapshot_id, " + sampleColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	for _, sample := range snapshot.Samples {
+		tagsJSON, err := json.Marshal(sample.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tags: %w", err)
+		}
+		totalJSON, err := json.Marshal(sample.Total)
+		if err != nil {
+			return fmt.Errorf("failed to marshal total: %w", err)
+		}
+		allocatedJSON, err := json.Marshal(sample.Allocated)
+		if err != nil {
+			return fmt.Errorf("failed to marshal allocated: %w", err)
+		}
+		availableJSON, err := json.Marshal(sample.Available)
+		if err != nil {
+			return fmt.Errorf("failed to marshal available: %w", err)
+		}
+
+		_, err = r.db.ExecContext(ctx, query,
+			snapshot.ID,
+			sample.ComputeID,
+			sample.ComputeName,
+			sample.Type,
+			nullString(sample.ParentID),
+			sample.Provider,
+			sample.Region,
+			string(tagsJSON),
+			sample.State,
+			string(totalJSON),
+			string(allocatedJSON),
+			string(availableJSON),
+			sample.UtilizationPct,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create snapshot sample: %w", err)
+		}
+	}
+	snapshot.Computes = len(snapshot.Samples)
+
+	return nil
+}
+
+func (r *snapshotRepo) Get(ctx context.Context, id string) (*domain.CapacitySnapshot, error) {
+	var snapshot domain.CapacitySnapshot
+	var reservation string
+
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, taken_at, reservation, computes FROM capacity_snapshots WHERE id = ?", id,
+	).Scan(&snapshot.ID, &snapshot.TakenAt, &reservation, &snapshot.Computes)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("snapshot not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	snapshot.Reservation = domain.ReservationBasis(reservation)
+
+	samples, err := r.listSamples(ctx, []*domain.CapacitySnapshot{&snapshot}, "")
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Samples = samples[snapshot.ID]
+
+	return &snapshot, nil
+}
+
+func (r *snapshotRepo) List(ctx context.Context, filters storage.SnapshotFilters) ([]*domain.CapacitySnapshot, error) {
+	query := "SELECT id, taken_at, reservation, computes FROM capacity_snapshots WHERE 1=1"
+	args := make([]interface{}, 0)
+
+	if filters.From != nil {
+		query += " AND taken_at >= ?"
+		args = append(args, filters.From.UTC())
+	}
+	if filters.To != nil {
+		query += " AND taken_at <= ?"
+		args = append(args, filters.To.UTC())
+	}
+
+	query += " ORDER BY taken_at DESC"
+	if filters.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filters.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*domain.CapacitySnapshot, 0)
+	for rows.Next() {
+		var snapshot domain.CapacitySnapshot
+		var reservation string
+		if err := rows.Scan(&snapshot.ID, &snapshot.TakenAt, &reservation, &snapshot.Computes); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshot.Reservation = domain.ReservationBasis(reservation)
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	rows.Close()
+
+	// Oldest first, as a time series
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+
+	if !filters.Samples && filters.ComputeID == "" {
+		return snapshots, nil
+	}
+
+	samples, err := r.listSamples(ctx, snapshots, filters.ComputeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		snapshot.Samples = samples[snapshot.ID]
+	}
+
+	return snapshots, nil
+}
+
+func (r *snapshotRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM capacity_snapshots WHERE taken_at < ?", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// listSamples loads the samples of the snapshots keyed by snapshot ID, of one compute when
+// computeID is set
+func (r *snapshotRepo) listSamples(ctx context.Context, snapshots []*domain.CapacitySnapshot, computeID string) (map[string][]domain.ComputeSample, error) {
+	samples := make(map[string][]domain.ComputeSample, len(snapshots))
+	if len(snapshots) == 0 {
+		return samples, nil
+	}
+
+	query := "SELECT snapshot_id, " + sampleColumns + " FROM capacity_samples WHERE snapshot_id IN (?" + repeatPlaceholders(len(snapshots)-1) + ")"
+	args := make([]interface{}, 0, len(snapshots)+1)
+	for _, snapshot := range snapshots {
+		args = append(args, snapshot.ID)
+	}
+	if computeID != "" {
+		query += " AND compute_id = ?"
+		args = append(args, computeID)
+	}
+	query += " ORDER BY compute_name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot samples: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var snapshotID string
+		var sample domain.ComputeSample
+		var parentID, tagsJSON sql.NullString
+		var totalJSON, allocatedJSON, availableJSON string
+
+		err := rows.Scan(
+			&snapshotID,
+			&sample.ComputeID,
+			&sample.ComputeName,
+			&sample.Type,
+			&parentID,
+			&sample.Provider,
+			&sample.Region,
+			&tagsJSON,
+			&sample.State,
+			&totalJSON,
+			&allocatedJSON,
+			&availableJSON,
+			&sample.UtilizationPct,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot sample: %w", err)
+		}
+		sample.ParentID = parentID.String
+
+		if tagsJSON.Valid && tagsJSON.String != "" {
+			if err := json.Unmarshal([]byte(tagsJSON.String), &sample.Tags); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+			}
+		}
+		if err := json.Unmarshal([]byte(totalJSON), &sample.Total); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal total: %w", err)
+		}
+		if err := json.Unmarshal([]byte(allocatedJSON), &sample.Allocated); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal allocated: %w", err)
+		}
+		if err := json.Unmarshal([]byte(availableJSON), &sample.Available); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal available: %w", err)
+		}
+
+		samples[snapshotID] = append(samples[snapshotID], sample)
+	}
+
+	return samples, rows.Err()
+}
+
+// repeatPlaceholders returns n ", ?" placeholders
+func repeatPlaceholders(n int) string {
+	placeholders := ""
+	for i := 0; i < n; i++ {
+		placeholders += ", ?"
+	}
+	return placeholders
+}
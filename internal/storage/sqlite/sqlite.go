@@ -3,10 +3,14 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
+	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/studiowebux/kubebuddy/internal/domain"
 	"github.com/studiowebux/kubebuddy/internal/storage"
 )
 
@@ -27,6 +31,19 @@ type SQLiteStorage struct {
 	portAssignments      *portAssignmentRepo
 	firewallRules        *firewallRuleRepo
 	computeFirewallRules *computeFirewallRuleRepo
+	overcommitPolicies   *overcommitPolicyRepo
+	derivationRules      *derivationRuleRepo
+	reservations         *reservationRepo
+	snapshots            *snapshotRepo
+	pools                *poolRepo
+	volumes              *volumeRepo
+}
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx so repositories can run inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // New creates a new SQLite storage instance
@@ -47,6 +64,19 @@ func New(dataSourceName string) (storage.Storage, error) {
 	}
 
 	// Initialize repositories
+	s.initRepos(db)
+
+	// Run migrations
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return s, nil
+}
+
+// initRepos points every repository at the given connection or transaction
+func (s *SQLiteStorage) initRepos(db dbtx) {
 	s.computes = &computeRepo{db: db}
 	s.services = &serviceRepo{db: db}
 	s.assignments = &assignmentRepo{db: db}
@@ -60,14 +90,12 @@ func New(dataSourceName string) (storage.Storage, error) {
 	s.portAssignments = &portAssignmentRepo{db: db}
 	s.firewallRules = &firewallRuleRepo{db: db}
 	s.computeFirewallRules = &computeFirewallRuleRepo{db: db}
-
-	// Run migrations
-	if err := s.migrate(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	return s, nil
+	s.overcommitPolicies = &overcommitPolicyRepo{db: db}
+	s.derivationRules = &derivationRuleRepo{db: db}
+	s.reservations = &reservationRepo{db: db}
+	s.snapshots = &snapshotRepo{db: db}
+	s.pools = &poolRepo{db: db}
+	s.volumes = &volumeRepo{db: db}
 }
 
 // Close closes the database connection
@@ -75,6 +103,29 @@ func (s *SQLiteStorage) Close() error {
 	return s.db.Close()
 }
 
+// WithTx runs fn with a storage bound to a single transaction.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+func (s *SQLiteStorage) WithTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	txStorage := &SQLiteStorage{db: s.db}
+	txStorage.initRepos(tx)
+
+	if err := fn(txStorage); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // Computes returns the compute repository
 func (s *SQLiteStorage) Computes() storage.ComputeRepository {
 	return s.computes
@@ -140,6 +191,36 @@ func (s *SQLiteStorage) ComputeFirewallRules() storage.ComputeFirewallRuleReposi
 	return s.computeFirewallRules
 }
 
+// OvercommitPolicies returns the overcommit policy repository
+func (s *SQLiteStorage) OvercommitPolicies() storage.OvercommitPolicyRepository {
+	return s.overcommitPolicies
+}
+
+// DerivationRules returns the derivation rule repository
+func (s *SQLiteStorage) DerivationRules() storage.DerivationRuleRepository {
+	return s.derivationRules
+}
+
+// Reservations returns the capacity reservation repository
+func (s *SQLiteStorage) Reservations() storage.ReservationRepository {
+	return s.reservations
+}
+
+// Snapshots returns the capacity snapshot repository
+func (s *SQLiteStorage) Snapshots() storage.SnapshotRepository {
+	return s.snapshots
+}
+
+// Pools returns the storage pool repository
+func (s *SQLiteStorage) Pools() storage.PoolRepository {
+	return s.pools
+}
+
+// Volumes returns the volume repository
+func (s *SQLiteStorage) Volumes() storage.VolumeRepository {
+	return s.volumes
+}
+
 // migrate runs database migrations
 func (s *SQLiteStorage) migrate() error {
 	ctx := context.Background()
@@ -157,16 +238,17 @@ func (s *SQLiteStorage) migrate() error {
 	}
 
 	// Get sorted migration versions
-	versions := make([]int, 0, len(migrations))
+	versions := make([]int, 0, len(migrations)+len(dataMigrations))
 	for version := range migrations {
 		versions = append(versions, version)
 	}
+	for version := range dataMigrations {
+		versions = append(versions, version)
+	}
 	sort.Ints(versions)
 
 	// Run migrations in order
 	for _, version := range versions {
-		migration := migrations[version]
-
 		// Check if migration already applied
 		var count int
 		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM migrations WHERE version = ?", version).Scan(&count)
@@ -178,8 +260,16 @@ func (s *SQLiteStorage) migrate() error {
 			continue
 		}
 
+		// Data migrations are marked as applied in the transaction that rewrites the data
+		if migrate, ok := dataMigrations[version]; ok {
+			if err := s.runDataMigration(ctx, version, migrate); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// Run migration
-		if _, err := s.db.ExecContext(ctx, migration); err != nil {
+		if _, err := s.db.ExecContext(ctx, migrations[version]); err != nil {
 			return fmt.Errorf("failed to run migration version %d: %w", version, err)
 		}
 
@@ -192,6 +282,31 @@ func (s *SQLiteStorage) migrate() error {
 	return nil
 }
 
+// runDataMigration runs a data migration and marks it as applied in one transaction, so that a
+// failure leaves the data as it was
+func (s *SQLiteStorage) runDataMigration(ctx context.Context, version int, migrate func(ctx context.Context, db dbtx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration version %d: %w", version, err)
+	}
+
+	if err := migrate(ctx, tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to run migration version %d: %w", version, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO migrations (version) VALUES (?)", version); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to mark migration version %d as applied: %w", version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration version %d: %w", version, err)
+	}
+
+	return nil
+}
+
 // migrations contains all database schema migrations
 var migrations = map[int]string{
 	1: `
@@ -487,4 +602,277 @@ var migrations = map[int]string{
 		ALTER TABLE ip_addresses ADD COLUMN vlan TEXT;
 		ALTER TABLE compute_ips ADD COLUMN interface_name TEXT;
 	`,
+	17: `
+		-- Add overcommit ratios per compute and tag-based overcommit policies
+		ALTER TABLE computes ADD COLUMN overcommit TEXT;
+
+		CREATE TABLE overcommit_policies (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			tags TEXT NOT NULL,
+			ratios TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 100,
+			description TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_overcommit_policies_priority ON overcommit_policies(priority);
+	`,
+	20: `
+		-- Component-to-resource derivation rules
+		CREATE TABLE derivation_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			component_type TEXT NOT NULL,
+			spec_fields TEXT NOT NULL,
+			unit TEXT,
+			resource TEXT NOT NULL,
+			aggregation TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 100,
+			description TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_derivation_rules_component_type ON derivation_rules(component_type);
+	`,
+	22: `
+		-- Parent compute of VMs and VPS hosted on a baremetal compute, and their size
+		ALTER TABLE computes ADD COLUMN parent_id TEXT REFERENCES computes(id);
+		ALTER TABLE computes ADD COLUMN size TEXT;
+
+		CREATE INDEX idx_computes_parent_id ON computes(parent_id);
+	`,
+	23: `
+		-- Time-bounded capacity reservations
+		CREATE TABLE reservations (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			owner TEXT NOT NULL,
+			resources TEXT NOT NULL,
+			compute_id TEXT REFERENCES computes(id) ON DELETE CASCADE,
+			selector TEXT,
+			starts_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			description TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_reservations_owner ON reservations(owner);
+		CREATE INDEX idx_reservations_expires_at ON reservations(expires_at);
+	`,
+	24: `
+		-- Capacity snapshots, one sample per compute. Samples keep a copy of the compute
+		-- attributes and no foreign key so the history survives compute deletion.
+		CREATE TABLE capacity_snapshots (
+			id TEXT PRIMARY KEY,
+			taken_at TIMESTAMP NOT NULL,
+			reservation TEXT NOT NULL,
+			computes INTEGER NOT NULL DEFAULT 0
+		);
+
+		CREATE TABLE capacity_samples (
+			snapshot_id TEXT NOT NULL REFERENCES capacity_snapshots(id) ON DELETE CASCADE,
+			compute_id TEXT NOT NULL,
+			compute_name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			parent_id TEXT,
+			provider TEXT NOT NULL,
+			region TEXT NOT NULL,
+			tags TEXT,
+			state TEXT NOT NULL,
+			total TEXT NOT NULL,
+			allocated TEXT NOT NULL,
+			available TEXT NOT NULL,
+			utilization_pct REAL NOT NULL,
+			PRIMARY KEY (snapshot_id, compute_id)
+		);
+
+		CREATE INDEX idx_capacity_snapshots_taken_at ON capacity_snapshots(taken_at);
+		CREATE INDEX idx_capacity_samples_compute_id ON capacity_samples(compute_id);
+	`,
+	25: `
+		-- Optional schedule of assignments, NULL meaning already running or running indefinitely
+		ALTER TABLE assignments ADD COLUMN starts_at TIMESTAMP;
+		ALTER TABLE assignments ADD COLUMN ends_at TIMESTAMP;
+	`,
+	26: `
+		-- Storage requirements of services by tier and redundancy
+		ALTER TABLE services ADD COLUMN storage TEXT;
+	`,
+	27: `
+		-- Hot spare role and RAID50/RAID60 span count of storage components in a RAID group
+		ALTER TABLE compute_components ADD COLUMN raid_role TEXT DEFAULT '';
+		ALTER TABLE compute_components ADD COLUMN raid_spans INTEGER DEFAULT 0;
+	`,
+	28: `
+		-- Storage pools (ZFS pools, LVM volume groups) built on RAID groups, and their volumes
+		CREATE TABLE storage_pools (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			compute_id TEXT NOT NULL REFERENCES computes(id) ON DELETE CASCADE,
+			type TEXT NOT NULL,
+			raid_groups TEXT NOT NULL,
+			description TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			UNIQUE(compute_id, name)
+		);
+
+		CREATE TABLE volumes (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			pool_id TEXT NOT NULL REFERENCES storage_pools(id) ON DELETE CASCADE,
+			size REAL NOT NULL,
+			service_id TEXT REFERENCES services(id) ON DELETE SET NULL,
+			requirement TEXT,
+			description TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			UNIQUE(pool_id, name)
+		);
+
+		CREATE INDEX idx_volumes_service ON volumes(service_id);
+	`,
+}
+
+// dataMigrations contains migrations that rewrite stored data in Go, run in version order with the schema migrations
+var dataMigrations = map[int]func(ctx context.Context, db dbtx) error{
+	18: canonicalizeServiceSpecs,
+	19: normalizeServiceSpecKeys,
+	21: seedDerivationRules,
+}
+
+// canonicalizeServiceSpecs rewrites service specs as numbers in the canonical unit of each key.
+// Quantity strings such as "4Gi" are converted; a value that is not a quantity fails the
+// migration, naming the service and key to fix, rather than being dropped.
+func canonicalizeServiceSpecs(ctx context.Context, db dbtx) error {
+	return rewriteServiceSpecs(ctx, db, canonicalSpecJSON)
+}
+
+// normalizeServiceSpecKeys rewrites resource key aliases in service specs (cpu, ram_gb...)
+// to their canonical key. Unknown and conflicting keys are kept and rejected on the next update.
+func normalizeServiceSpecKeys(ctx context.Context, db dbtx) error {
+	registry := domain.DefaultResourceRegistry()
+	return rewriteServiceSpecs(ctx, db, func(spec string) (string, error) {
+		var resources domain.Resources
+		if err := json.Unmarshal([]byte(spec), &resources); err != nil {
+			return "", err
+		}
+
+		known := make(domain.Resources)
+		unknown := make(domain.Resources)
+		for key, value := range resources {
+			if _, ok := registry.Resolve(key); ok {
+				known[key] = value
+			} else {
+				unknown[key] = value
+			}
+		}
+
+		normalized, err := registry.Normalize(known)
+		if err != nil {
+			// Conflicting keys such as cpu and cores: keep the spec for the user to fix
+			return spec, nil
+		}
+		for key, value := range unknown {
+			normalized[key] = value
+		}
+
+		data, err := json.Marshal(normalized)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	})
+}
+
+// seedDerivationRules stores the default derivation rules, which reproduce the derivation
+// that was hard-coded before rules were configurable
+func seedDerivationRules(ctx context.Context, db dbtx) error {
+	repo := &derivationRuleRepo{db: db}
+	now := time.Now()
+	for _, rule := range domain.DefaultDerivationRules() {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+		if err := repo.Create(ctx, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteServiceSpecs applies convert to the min and max spec of every service
+func rewriteServiceSpecs(ctx context.Context, db dbtx, convert func(spec string) (string, error)) error {
+	rows, err := db.QueryContext(ctx, "SELECT id, min_spec, max_spec FROM services")
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	type specs struct {
+		id, minSpec, maxSpec string
+	}
+	var services []specs
+	for rows.Next() {
+		var row specs
+		if err := rows.Scan(&row.id, &row.minSpec, &row.maxSpec); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for _, row := range services {
+		minSpec, err := convert(row.minSpec)
+		if err != nil {
+			return fmt.Errorf("failed to convert min_spec of service %s: %w", row.id, err)
+		}
+		maxSpec, err := convert(row.maxSpec)
+		if err != nil {
+			return fmt.Errorf("failed to convert max_spec of service %s: %w", row.id, err)
+		}
+
+		if _, err := db.ExecContext(ctx, "UPDATE services SET min_spec = ?, max_spec = ? WHERE id = ?", minSpec, maxSpec, row.id); err != nil {
+			return fmt.Errorf("failed to update service %s: %w", row.id, err)
+		}
+	}
+
+	return nil
+}
+
+// canonicalSpecJSON converts a stored spec to canonical quantities
+func canonicalSpecJSON(spec string) (string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(spec), &raw); err != nil {
+		return "", err
+	}
+
+	resources := make(domain.Resources, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case float64:
+			resources[key] = domain.Quantity(v)
+		case string:
+			quantity, err := domain.ParseQuantity(key, v)
+			if err != nil {
+				return "", err
+			}
+			resources[key] = quantity
+		default:
+			return "", fmt.Errorf("invalid quantity %v for %s", value, key)
+		}
+	}
+
+	data, err := json.Marshal(resources)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/studiowebux/kubebuddy/internal/domain"
+	"github.com/studiowebux/kubebuddy/internal/storage"
+)
+
+type volumeRepo struct {
+	db dbtx
+}
+
+const volumeColumns = "id, name, pool_id, size, service_id, requirement, description, created_at, updated_at"
+
+func (r *volumeRepo) Create(ctx context.Context, volume *domain.Volume) error {
+	query := `
+		INSERT INTO volumes (` + volumeColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		volume.ID,
+		volume.Name,
+		volume.PoolID,
+		volume.Size.Float(),
+		nullString(volume.ServiceID),
+		volume.Requirement,
+		volume.Description,
+		volume.CreatedAt,
+		volume.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create volume: %w", err)
+	}
+
+	return nil
+}
+
+func (r *volumeRepo) Get(ctx context.Context, id string) (*domain.Volume, error) {
+	query := "SELECT " + volumeColumns + " FROM volumes WHERE id = ?"
+
+	volume, err := scanVolume(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("volume not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume: %w", err)
+	}
+
+	return volume, nil
+}
+
+func (r *volumeRepo) GetByName(ctx context.Context, poolID, name string) (*domain.Volume, error) {
+	query := "SELECT " + volumeColumns + " FROM volumes WHERE pool_id = ? AND name = ?"
+
+	volume, err := scanVolume(r.db.QueryRowContext(ctx, query, poolID, name))
+	if err == sql.ErrNoRows {
+		return nil, nil // Return nil if not found (not an error for upsert logic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume: %w", err)
+	}
+
+	return volume, nil
+}
+
+func (r *volumeRepo) List(ctx context.Context, filters storage.VolumeFilters) ([]*domain.Volume, error) {
+	query := "SELECT " + volumeColumns + " FROM volumes WHERE 1=1"
+	args := make([]interface{}, 0)
+
+	if filters.PoolID != "" {
+		query += " AND pool_id = ?"
+		args = append(args, filters.PoolID)
+	}
+	if filters.ServiceID != "" {
+		query += " AND service_id = ?"
+		args = append(args, filters.ServiceID)
+	}
+
+	query += " ORDER BY pool_id, name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	defer rows.Close()
+
+	volumes := make([]*domain.Volume, 0)
+	for rows.Next() {
+		volume, err := scanVolume(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan volume: %w", err)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, nil
+}
+
+func (r *volumeRepo) Update(ctx context.Context, volume *domain.Volume) error {
+	query := `
+		UPDATE volumes
+		SET name = ?, pool_id = ?, size = ?, service_id = ?, requirement = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		volume.Name,
+		volume.PoolID,
+		volume.Size.Float(),
+		nullString(volume.ServiceID),
+		volume.Requirement,
+		volume.Description,
+		volume.UpdatedAt,
+		volume.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update volume: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("volume not found")
+	}
+
+	return nil
+}
+
+func (r *volumeRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM volumes WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete volume: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("volume not found")
+	}
+
+	return nil
+}
+
+// scanVolume reads one volume row
+func scanVolume(row rowScanner) (*domain.Volume, error) {
+	var volume domain.Volume
+	var size float64
+	var serviceID, requirement, description sql.NullString
+
+	err := row.Scan(
+		&volume.ID,
+		&volume.Name,
+		&volume.PoolID,
+		&size,
+		&serviceID,
+		&requirement,
+		&description,
+		&volume.CreatedAt,
+		&volume.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	volume.Size = domain.Quantity(size)
+	volume.ServiceID = serviceID.String
+	// A volume whose service was deleted no longer provides its requirement
+	if serviceID.Valid {
+		volume.Requirement = requirement.String
+	}
+	volume.Description = description.String
+
+	return &volume, nil
+}
@@ -10,6 +10,8 @@ import (
 // Storage is the main storage interface
 type Storage interface {
 	Close() error
+	// WithTx runs fn against a storage bound to one transaction, committing only if fn returns nil
+	WithTx(ctx context.Context, fn func(tx Storage) error) error
 	Computes() ComputeRepository
 	Services() ServiceRepository
 	Assignments() AssignmentRepository
@@ -23,6 +25,12 @@ type Storage interface {
 	PortAssignments() PortAssignmentRepository
 	FirewallRules() FirewallRuleRepository
 	ComputeFirewallRules() ComputeFirewallRuleRepository
+	OvercommitPolicies() OvercommitPolicyRepository
+	DerivationRules() DerivationRuleRepository
+	Reservations() ReservationRepository
+	Snapshots() SnapshotRepository
+	Pools() PoolRepository
+	Volumes() VolumeRepository
 }
 
 // ComputeRepository handles compute resource persistence
@@ -41,6 +49,7 @@ type ComputeFilters struct {
 	Provider string
 	Region   string
 	State    string
+	ParentID string // Computes hosted on this compute
 	Tags     map[string]string
 }
 
@@ -58,7 +67,7 @@ type ServiceRepository interface {
 type AssignmentRepository interface {
 	Create(ctx context.Context, assignment *domain.Assignment) error
 	Get(ctx context.Context, id string) (*domain.Assignment, error)
-	GetByComputeAndService(ctx context.Context, computeID, serviceID string) (*domain.Assignment, error)
+	GetByComputeAndService(ctx context.Context, computeID, serviceID string, schedule domain.Schedule) (*domain.Assignment, error)
 	List(ctx context.Context, filters AssignmentFilters) ([]*domain.Assignment, error)
 	Update(ctx context.Context, assignment *domain.Assignment) error
 	Delete(ctx context.Context, id string) error
@@ -123,6 +132,7 @@ type ComputeComponentFilters struct {
 // ComputeComponentRepository handles compute-component assignment persistence
 type ComputeComponentRepository interface {
 	Assign(ctx context.Context, assignment *domain.ComputeComponent) error
+	Get(ctx context.Context, id string) (*domain.ComputeComponent, error)
 	Unassign(ctx context.Context, id string) error
 	ListByCompute(ctx context.Context, computeID string) ([]*domain.ComputeComponent, error)
 	ListByComponent(ctx context.Context, componentID string) ([]*domain.ComputeComponent, error)
@@ -219,3 +229,87 @@ type ComputeFirewallRuleRepository interface {
 	ListByRule(ctx context.Context, ruleID string) ([]*domain.ComputeFirewallRule, error)
 	UpdateEnabled(ctx context.Context, id string, enabled bool) error
 }
+
+// OvercommitPolicyRepository handles overcommit policy persistence
+type OvercommitPolicyRepository interface {
+	Create(ctx context.Context, policy *domain.OvercommitPolicy) error
+	Get(ctx context.Context, id string) (*domain.OvercommitPolicy, error)
+	GetByName(ctx context.Context, name string) (*domain.OvercommitPolicy, error)
+	List(ctx context.Context) ([]*domain.OvercommitPolicy, error)
+	Update(ctx context.Context, policy *domain.OvercommitPolicy) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DerivationRuleRepository handles component-to-resource derivation rule persistence
+type DerivationRuleRepository interface {
+	Create(ctx context.Context, rule *domain.DerivationRule) error
+	Get(ctx context.Context, id string) (*domain.DerivationRule, error)
+	GetByName(ctx context.Context, name string) (*domain.DerivationRule, error)
+	List(ctx context.Context) ([]*domain.DerivationRule, error)
+	Update(ctx context.Context, rule *domain.DerivationRule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ReservationRepository handles capacity reservation persistence
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *domain.Reservation) error
+	Get(ctx context.Context, id string) (*domain.Reservation, error)
+	GetByName(ctx context.Context, name string) (*domain.Reservation, error)
+	List(ctx context.Context, filters ReservationFilters) ([]*domain.Reservation, error)
+	Update(ctx context.Context, reservation *domain.Reservation) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ReservationFilters for querying reservations
+type ReservationFilters struct {
+	Owner     string
+	ComputeID string
+}
+
+// SnapshotRepository handles capacity snapshot persistence
+type SnapshotRepository interface {
+	Create(ctx context.Context, snapshot *domain.CapacitySnapshot) error
+	Get(ctx context.Context, id string) (*domain.CapacitySnapshot, error)
+	List(ctx context.Context, filters SnapshotFilters) ([]*domain.CapacitySnapshot, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SnapshotFilters for querying capacity snapshots
+type SnapshotFilters struct {
+	From      *time.Time
+	To        *time.Time
+	ComputeID string // Only keep the samples of this compute
+	Samples   bool   // Load the compute samples of each snapshot
+	Limit     int    // Most recent snapshots only
+}
+
+// PoolRepository handles storage pool persistence
+type PoolRepository interface {
+	Create(ctx context.Context, pool *domain.Pool) error
+	Get(ctx context.Context, id string) (*domain.Pool, error)
+	GetByName(ctx context.Context, computeID, name string) (*domain.Pool, error)
+	List(ctx context.Context, filters PoolFilters) ([]*domain.Pool, error)
+	Update(ctx context.Context, pool *domain.Pool) error
+	Delete(ctx context.Context, id string) error
+}
+
+// PoolFilters for querying storage pools
+type PoolFilters struct {
+	ComputeID string
+}
+
+// VolumeRepository handles volume persistence
+type VolumeRepository interface {
+	Create(ctx context.Context, volume *domain.Volume) error
+	Get(ctx context.Context, id string) (*domain.Volume, error)
+	GetByName(ctx context.Context, poolID, name string) (*domain.Volume, error)
+	List(ctx context.Context, filters VolumeFilters) ([]*domain.Volume, error)
+	Update(ctx context.Context, volume *domain.Volume) error
+	Delete(ctx context.Context, id string) error
+}
+
+// VolumeFilters for querying volumes
+type VolumeFilters struct {
+	PoolID    string
+	ServiceID string
+}